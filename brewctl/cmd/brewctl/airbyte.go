@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var airbyteCmd = &cobra.Command{
+	Use:   "airbyte",
+	Short: "Inspect Airbyte resources",
+}
+
+var airbyteJobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Inspect Airbyte sync jobs",
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sync jobs of a connection",
+	Run: func(cmd *cobra.Command, args []string) {
+		connectionID, _ := cmd.Flags().GetString("connection")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client := newAirbyteClient()
+		client.Quiet = true
+
+		jobs, err := client.ListJobs(connectionID, limit)
+		if err != nil {
+			log.Fatalf("❌ Failed to list jobs: %v", err)
+		}
+		if len(jobs) == 0 {
+			fmt.Printf("ℹ️ No jobs found for connection %s\n", connectionID)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "JOB ID\tTYPE\tSTATUS\tATTEMPTS\tSTARTED\tDURATION\tRECORDS\tFAILURE")
+		for _, job := range jobs {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\t%d\t%s\n",
+				job.ID,
+				job.ConfigType,
+				job.Status,
+				len(job.Attempts),
+				job.CreatedAt.Format("2006-01-02 15:04:05"),
+				job.Duration().Round(time.Second),
+				job.RecordsSynced(),
+				truncate(job.FailureReason(), 80),
+			)
+		}
+		w.Flush()
+	},
+}
+
+var jobsLogsCmd = &cobra.Command{
+	Use:   "logs <jobID>",
+	Short: "Show attempts and log lines of a job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jobID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("❌ Invalid job ID %q: %v", args[0], err)
+		}
+		follow, _ := cmd.Flags().GetBool("follow")
+		attempt, _ := cmd.Flags().GetInt("attempt")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		client := newAirbyteClient()
+		client.Quiet = true
+
+		job, err := client.StreamJobLogs(jobID, attempt, follow, interval, os.Stdout)
+		if err != nil {
+			log.Fatalf("❌ Failed to get job logs: %v", err)
+		}
+
+		fmt.Printf("\n📋 Job %d (%s): %s after %s\n", job.ID, job.ConfigType, job.Status, job.Duration().Round(time.Second))
+		for _, a := range job.Attempts {
+			fmt.Printf("  • attempt %d: %s, %s, %d records, %d bytes\n",
+				a.Number, a.Status, a.Duration().Round(time.Second), a.RecordsSynced, a.BytesSynced)
+			for _, f := range a.Failures {
+				fmt.Printf("    ❌ %s\n", f)
+			}
+		}
+	},
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
+
+func init() {
+	jobsListCmd.Flags().String("connection", "", "Airbyte connection ID")
+	jobsListCmd.Flags().Int("limit", 20, "Maximum number of jobs to show")
+	jobsListCmd.MarkFlagRequired("connection")
+
+	jobsLogsCmd.Flags().BoolP("follow", "f", false, "Keep streaming new log lines until the job finishes")
+	jobsLogsCmd.Flags().Int("attempt", -1, "Only show logs of this attempt number (default: all attempts)")
+	jobsLogsCmd.Flags().Duration("interval", 5*time.Second, "Polling interval used with --follow")
+
+	airbyteJobsCmd.AddCommand(jobsListCmd, jobsLogsCmd)
+	airbyteCmd.AddCommand(airbyteJobsCmd)
+	rootCmd.AddCommand(airbyteCmd)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"brewctl/internal/kube"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manage the Kind cluster",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := rootCmd.PersistentPreRunE(cmd, args); err != nil {
+			return err
+		}
+		return applyClusterFlags(cmd)
+	},
+}
+
+var clusterCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create the Kind cluster (no-op if it already exists)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := kube.CreateKindCluster(cfg.Cluster); err != nil {
+			log.Fatalf("❌ Failed to create Kind cluster: %v", err)
+		}
+	},
+}
+
+var clusterDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete the Kind cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := kube.DeleteKindCluster(cfg.Cluster.Name); err != nil {
+			log.Fatalf("❌ Failed to delete Kind cluster: %v", err)
+		}
+	},
+}
+
+var clusterRecreateCmd = &cobra.Command{
+	Use:   "recreate",
+	Short: "Delete and create the Kind cluster again",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := kube.RecreateKindCluster(cfg.Cluster); err != nil {
+			log.Fatalf("❌ Failed to recreate Kind cluster: %v", err)
+		}
+	},
+}
+
+var clusterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Kind clusters",
+	Run: func(cmd *cobra.Command, args []string) {
+		clusters, err := kube.ListKindClusters()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if len(clusters) == 0 {
+			fmt.Println("ℹ️ No Kind clusters found")
+			return
+		}
+		for _, name := range clusters {
+			marker := " "
+			if name == cfg.Cluster.Name {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, name)
+		}
+	},
+}
+
+var clusterConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the Kind configuration used to create the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		rendered, err := kube.RenderKindConfig(cfg.Cluster)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Print(rendered)
+	},
+}
+
+// applyClusterFlags sobrescreve a configuração do cluster com as flags informadas
+func applyClusterFlags(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	if flags.Changed("name") {
+		cfg.Cluster.Name, _ = flags.GetString("name")
+	}
+	if flags.Changed("kind-config") {
+		cfg.Cluster.ConfigFile, _ = flags.GetString("kind-config")
+	}
+	if flags.Changed("image") {
+		cfg.Cluster.NodeImage, _ = flags.GetString("image")
+	}
+	if flags.Changed("workers") {
+		cfg.Cluster.Workers, _ = flags.GetInt("workers")
+		// Topologia explícita na linha de comando ignora o arquivo padrão
+		if !flags.Changed("kind-config") {
+			cfg.Cluster.ConfigFile = ""
+		}
+	}
+	return cfg.Cluster.Validate()
+}
+
+func init() {
+	clusterCmd.PersistentFlags().String("name", "", "Kind cluster name (default from config: brewctl-cluster)")
+	clusterCmd.PersistentFlags().String("kind-config", "", "Kind config file (empty generates it from the brewctl config)")
+	clusterCmd.PersistentFlags().String("image", "", "kindest/node image pinned for every node")
+	clusterCmd.PersistentFlags().Int("workers", 0, "Number of worker nodes in the generated topology")
+
+	clusterCmd.AddCommand(clusterCreateCmd, clusterDeleteCmd, clusterRecreateCmd, clusterListCmd, clusterConfigCmd)
+	rootCmd.AddCommand(clusterCmd)
+}
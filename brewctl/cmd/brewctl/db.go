@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"brewctl/internal/mongodb"
+
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the breweries database schema",
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply, revert or list the versioned schema migrations",
+	Long: `Manage the versioned schema migrations of the breweries database (layer indexes
+and JSON Schema validators), recorded in the schema_migrations collection.
+Without a subcommand it behaves like "migrate up". The aggregations migrate a
+database that was never migrated; afterwards they fail while migrations are
+pending (for example after "migrate down") unless run with --migrate.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		migrateUp(0)
+	},
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply pending migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetInt("to")
+		migrateUp(to)
+	},
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the most recently applied migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		steps, _ := cmd.Flags().GetInt("steps")
+		if steps < 1 {
+			log.Fatalf("❌ --steps must be at least 1")
+		}
+
+		withMigrator(func(ctx context.Context, m *mongodb.Migrator) {
+			reverted, err := m.Down(ctx, steps)
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			if len(reverted) == 0 {
+				fmt.Println("ℹ️ No applied migrations to revert")
+				return
+			}
+			fmt.Printf("✅ Reverted %d migration(s)\n", len(reverted))
+		})
+	},
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List migrations and whether they are applied",
+	Run: func(cmd *cobra.Command, args []string) {
+		withMigrator(func(ctx context.Context, m *mongodb.Migrator) {
+			status, err := m.Status(ctx)
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+
+			fmt.Println("🗂️ Schema migrations:")
+			pending := 0
+			for _, s := range status {
+				if s.Applied != nil {
+					fmt.Printf("  ✅ %3d  %s (applied %s)\n", s.Version, s.Description, s.Applied.AppliedAt.Format(time.RFC3339))
+				} else {
+					pending++
+					fmt.Printf("  ⏳ %3d  %s (pending)\n", s.Version, s.Description)
+				}
+			}
+			fmt.Printf("📊 %d applied, %d pending\n", len(status)-pending, pending)
+		})
+	},
+}
+
+func migrateUp(to int) {
+	withMigrator(func(ctx context.Context, m *mongodb.Migrator) {
+		applied, err := m.Up(ctx, to)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("✅ Schema is up to date")
+			return
+		}
+		fmt.Printf("✅ Applied %d migration(s)\n", len(applied))
+	})
+}
+
+// withMigrator conecta ao MongoDB configurado e executa fn com um Migrator
+func withMigrator(fn func(ctx context.Context, m *mongodb.Migrator)) {
+	aggService, err := newAggregationService()
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+	}
+	defer aggService.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	fn(ctx, mongodb.NewMigrator(aggService.DB))
+}
+
+func init() {
+	dbMigrateUpCmd.Flags().Int("to", 0, "Apply migrations up to this version (default: latest)")
+	dbMigrateDownCmd.Flags().Int("steps", 1, "Number of migrations to revert")
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd, dbMigrateDownCmd, dbMigrateStatusCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	rootCmd.AddCommand(dbCmd)
+}
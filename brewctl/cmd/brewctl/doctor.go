@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"brewctl/internal/kube"
+	"brewctl/internal/preflight"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check host prerequisites (tools, Docker, ports) and suggest fixes",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("🩺 Running preflight checks...")
+
+		report := runPreflight()
+		report.Print(os.Stdout)
+
+		if report.Failed() {
+			fmt.Println("\n❌ Some checks failed; fix them before running cluster-init")
+			os.Exit(1)
+		}
+		fmt.Println("\n✅ Your environment is ready for cluster-init")
+	},
+}
+
+// runPreflight executa as verificações do host. As portas só são verificadas
+// quando o cluster ainda não existe, pois um cluster Kind ativo já as ocupa.
+func runPreflight() preflight.Report {
+	var ports []int
+
+	exists, err := kube.KindClusterExists(cfg.Cluster.Name)
+	switch {
+	case err != nil:
+		log.Printf("⚠️ Could not list Kind clusters: %v", err)
+	case exists:
+		fmt.Printf("ℹ️ Kind cluster %s already exists, skipping host port checks\n", cfg.Cluster.Name)
+	}
+
+	if err != nil || !exists {
+		kindConfig, err := kube.LoadKindConfig(cfg.Cluster)
+		if err != nil {
+			log.Printf("⚠️ Could not load Kind config: %v", err)
+		} else {
+			ports = kindConfig.HostPorts()
+		}
+	}
+
+	return preflight.Run(preflight.DefaultOptions(ports))
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
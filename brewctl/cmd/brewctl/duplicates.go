@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"brewctl/internal/mongodb"
+
+	"github.com/spf13/cobra"
+)
+
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Report likely duplicate breweries with different IDs in the silver layer",
+	Long: `Flag silver breweries with different IDs that are probably the same entity:
+same normalized name (lowercase, no punctuation or generic words like "brewing"
+and "company") and postal code (ZIP5 for US codes), and at most --max-distance
+meters apart when both have coordinates. The report replaces the contents of
+the breweries_duplicates collection; silver itself is not changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		maxDistance, _ := cmd.Flags().GetFloat64("max-distance")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		aggService, err := newAggregationService()
+		if err != nil {
+			log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+		}
+		defer aggService.Close()
+
+		candidates, err := aggService.ResolveDuplicates(maxDistance)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		for i, c := range candidates {
+			if i == limit {
+				fmt.Printf("  … %d more in %s\n", len(candidates)-limit, mongodb.DuplicatesCollection)
+				break
+			}
+			distance := "no coordinates"
+			if c.DistanceMeters != nil {
+				distance = fmt.Sprintf("%.0fm apart", *c.DistanceMeters)
+			}
+			fmt.Printf("  • %s (%s) ↔ %s (%s): postal code %s, %s\n",
+				c.Names[0], c.IDs[0], c.Names[1], c.IDs[1], c.PostalCode, distance)
+		}
+	},
+}
+
+func init() {
+	duplicatesCmd.Flags().Float64("max-distance", mongodb.DefaultDuplicateDistance, "Maximum distance in meters between two breweries with coordinates")
+	duplicatesCmd.Flags().Int("limit", 20, "Maximum number of pairs to print")
+	rootCmd.AddCommand(duplicatesCmd)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"brewctl/internal/images"
+	"brewctl/internal/kube"
+	"brewctl/internal/kube/helm"
+
+	"github.com/spf13/cobra"
+)
+
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "List and preload the container images used by brewctl",
+}
+
+var imagesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every image required by the configured charts and manifests",
+	Run: func(cmd *cobra.Command, args []string) {
+		required, err := requiredImages()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "IMAGE\tUSED BY")
+		for _, image := range required {
+			fmt.Fprintf(w, "%s\t%s\n", image.Ref, strings.Join(image.UsedBy, ","))
+		}
+		w.Flush()
+		fmt.Printf("ℹ️ The Kind node image %s is pulled by Docker when the cluster is created\n", cfg.Cluster.NodeImage)
+	},
+}
+
+var imagesPreloadCmd = &cobra.Command{
+	Use:   "preload",
+	Short: "Load the required images into the Kind cluster from the Docker cache or an archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		archive, _ := cmd.Flags().GetString("archive")
+		pull, _ := cmd.Flags().GetBool("pull")
+
+		if archive != "" {
+			if err := images.LoadArchive(cfg.Cluster.Name, archive); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			fmt.Println("✅ Images preloaded")
+			return
+		}
+
+		refs := requiredImageRefs(pull)
+		if err := images.LoadFromDocker(cfg.Cluster.Name, refs); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Println("✅ Images preloaded")
+	},
+}
+
+var imagesSaveCmd = &cobra.Command{
+	Use:   "save <archive.tar>",
+	Short: "Save the required images from the Docker cache into an archive for offline use",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pull, _ := cmd.Flags().GetBool("pull")
+
+		refs := requiredImageRefs(pull)
+		if err := images.Save(refs, args[0]); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Printf("✅ Images saved to %s\n", args[0])
+	},
+}
+
+// requiredImages renderiza os releases gerenciados e junta as imagens dos
+// manifests aplicados diretamente pelo brewctl
+func requiredImages() ([]images.Image, error) {
+	ctx := context.Background()
+	collector := images.NewCollector(helm.NewManager())
+
+	for _, r := range managedReleases() {
+		if err := collector.AddRelease(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	collector.Add("mongodb", kube.MongoDBImages(cfg.MongoDB)...)
+	return collector.Images(), nil
+}
+
+// requiredImageRefs lista as imagens e, com pull, baixa as ausentes do cache local
+func requiredImageRefs(pull bool) []string {
+	required, err := requiredImages()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	refs := images.Refs(required)
+
+	if pull {
+		if err := images.Pull(images.Missing(refs)); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+	return refs
+}
+
+func init() {
+	imagesPreloadCmd.Flags().String("archive", "", "Load images from a tarball created by `brewctl images save` or `docker save` instead of the Docker cache")
+	imagesPreloadCmd.Flags().Bool("pull", false, "Pull images missing from the Docker cache before loading")
+	imagesSaveCmd.Flags().Bool("pull", false, "Pull images missing from the Docker cache before saving")
+
+	imagesCmd.AddCommand(imagesListCmd, imagesPreloadCmd, imagesSaveCmd)
+	rootCmd.AddCommand(imagesCmd)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"brewctl/internal/kube"
+	"brewctl/internal/kube/helm"
+
+	"github.com/spf13/cobra"
+)
+
+var cleanupLegacyCmd = &cobra.Command{
+	Use:   "cleanup-legacy",
+	Short: "Remove brewctl resources left in the default namespace by previous versions",
+	Long: `Previous versions of brewctl deployed every component into the default namespace.
+Those releases and the MongoDB resources keep the NodePorts (30017, 32000, 30090)
+and are not seen by teardown or release. This command uninstalls them from
+default; use --keep-data to preserve their PVCs and the MongoDB credentials.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keepData, _ := cmd.Flags().GetBool("keep-data")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client, err := kube.NewClient("")
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		ctx := context.Background()
+
+		resources, err := findLegacyResources(ctx, client)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if len(resources) == 0 {
+			fmt.Printf("✅ No brewctl resources found in namespace %s\n", kube.LegacyNamespace)
+			return
+		}
+
+		fmt.Println("🧹 The following will be removed:")
+		for _, r := range resources {
+			if keepData && (r.Kind == kube.LegacyPVC || r.Kind == kube.LegacySecret) {
+				continue
+			}
+			fmt.Printf("  • %s\n", r)
+		}
+		if !yes && !confirm(os.Stdin, "Proceed?") {
+			fmt.Println("ℹ️ Cleanup cancelled")
+			return
+		}
+
+		if err := removeLegacyResources(ctx, client, resources, keepData); err != nil {
+			log.Fatalf("❌ Failed to remove legacy resources: %v", err)
+		}
+		fmt.Println("✅ Legacy resources removed; run cluster-init to deploy into the new namespaces")
+	},
+}
+
+// findLegacyResources procura no namespace default os releases e o MongoDB de
+// versões anteriores, para os componentes que hoje ficam em outro namespace
+func findLegacyResources(ctx context.Context, client *kube.Client) ([]kube.LegacyResource, error) {
+	var releases []string
+	for _, r := range append(managedReleases(), kube.MongoDBRelease(cfg.MongoDB)) {
+		if r.Namespace != kube.LegacyNamespace && !contains(releases, r.Name) {
+			releases = append(releases, r.Name)
+		}
+	}
+	return client.FindLegacyResources(ctx, releases, cfg.MongoDB)
+}
+
+// removeLegacyResources desinstala os releases e remove o MongoDB do namespace default
+func removeLegacyResources(ctx context.Context, client *kube.Client, resources []kube.LegacyResource, keepData bool) error {
+	m := helm.NewManager()
+	mongoDB := false
+	for _, r := range resources {
+		switch r.Kind {
+		case kube.LegacyHelmRelease:
+			if err := m.Uninstall(ctx, r.Name, kube.LegacyNamespace); err != nil {
+				return err
+			}
+			if keepData {
+				continue
+			}
+			if _, err := client.DeletePVCs(ctx, kube.LegacyNamespace, "app.kubernetes.io/instance="+r.Name, ""); err != nil {
+				return err
+			}
+		default:
+			mongoDB = true
+		}
+	}
+	if mongoDB {
+		return client.RemoveLegacyMongoDB(ctx, cfg.MongoDB, keepData)
+	}
+	return nil
+}
+
+// checkLegacyNamespace falha se houver recursos de versões anteriores no
+// namespace default, que ocupariam os NodePorts dos novos serviços
+func checkLegacyNamespace() error {
+	client, err := kube.NewClient("")
+	if err != nil {
+		return err
+	}
+	resources, err := findLegacyResources(context.Background(), client)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(resources))
+	for i, r := range resources {
+		names[i] = r.String()
+	}
+	return fmt.Errorf("found resources from a previous brewctl version in namespace %s (%s); "+
+		"they hold the NodePorts of the new deployment. Run `brewctl cleanup-legacy` "+
+		"(--keep-data preserves their volumes) or set the component namespaces back to %s in the config",
+		kube.LegacyNamespace, strings.Join(names, ", "), kube.LegacyNamespace)
+}
+
+// warnLegacyNamespace avisa sobre recursos que teardown e release não gerenciam
+func warnLegacyNamespace() {
+	client, err := kube.NewClient("")
+	if err != nil {
+		return
+	}
+	resources, err := findLegacyResources(context.Background(), client)
+	if err != nil || len(resources) == 0 {
+		return
+	}
+	fmt.Printf("⚠️ %d brewctl resources from a previous version remain in namespace %s; run `brewctl cleanup-legacy` to remove them\n",
+		len(resources), kube.LegacyNamespace)
+}
+
+func init() {
+	cleanupLegacyCmd.Flags().Bool("keep-data", false, "Keep PersistentVolumeClaims and the MongoDB credentials secret")
+	cleanupLegacyCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	rootCmd.AddCommand(cleanupLegacyCmd)
+}
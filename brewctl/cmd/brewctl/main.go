@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"brewctl/internal/airbyte"
+	"brewctl/internal/config"
 	"brewctl/internal/kube"
 	"brewctl/internal/mongodb"
 	"brewctl/internal/monitoring"
@@ -26,6 +27,78 @@ Features:
 • MongoDB with aggregation pipelines
 • Monitoring with Prometheus/Grafana
 • Bronze/Silver/Gold data layers`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		cfg, err = config.Load(configPath)
+		return err
+	},
+}
+
+var (
+	configPath string
+	cfg        *config.Config
+)
+
+// newAirbyteClient cria o cliente Airbyte a partir da configuração carregada
+func newAirbyteClient() *airbyte.AirbyteClient {
+	client, err := airbyte.NewAirbyteClientFromConfig(cfg.Airbyte)
+	if err != nil {
+		log.Fatalf("❌ Invalid Airbyte configuration: %v", err)
+	}
+	return client
+}
+
+// newAggregationService conecta ao MongoDB configurado
+func newAggregationService() (*mongodb.AggregationService, error) {
+	resolveMongoDBCredentials()
+
+	auth := cfg.MongoDB.Destination.Auth
+	if auth.Type != config.MongoDBAuthLoginPassword || auth.Password == "" {
+		auth.Username = ""
+	}
+	uri, err := cfg.MongoDB.ConnectionURI(auth.Username, auth.Password)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.NewAggregationServiceForDatabase(uri, cfg.MongoDB.Database)
+}
+
+// resolveMongoDBCredentials completa a senha login/password com a credencial
+// root gerada pelo deploy quando ela não foi informada na configuração
+func resolveMongoDBCredentials() {
+	auth := &cfg.MongoDB.Destination.Auth
+	if auth.Type != config.MongoDBAuthLoginPassword || auth.Password != "" ||
+		cfg.MongoDB.Mode == config.MongoDBModeExternal ||
+		cfg.MongoDB.Destination.InstanceType == config.MongoDBAtlas {
+		return
+	}
+
+	username, password, err := kube.MongoDBCredentials(cfg.MongoDB)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	auth.Username, auth.Password = username, password
+}
+
+// checkMongoDBDestination avisa quando o destination aponta para um service que não existe no cluster
+func checkMongoDBDestination() {
+	if cfg.MongoDB.Mode == config.MongoDBModeExternal || cfg.MongoDB.Destination.InstanceType == config.MongoDBAtlas {
+		return
+	}
+	if err := kube.CheckMongoDBService(cfg.MongoDB); err != nil {
+		log.Printf("⚠️ %v", err)
+		log.Printf("⚠️ The Airbyte destination will point to %s; run cluster-init or fix mongodb.namespace/service_name", cfg.MongoDB.ServiceHost())
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 var clusterInitCmd = &cobra.Command{
@@ -34,28 +107,53 @@ var clusterInitCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("🚀 Initializing Breweries Data Cluster...")
 
-		if err := kube.CreateKindCluster(); err != nil {
+		if err := applyMongoDBModeFlag(cmd); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		if skip, _ := cmd.Flags().GetBool("skip-preflight"); !skip {
+			report := runPreflight()
+			if report.Failed() {
+				report.Print(os.Stderr)
+				log.Fatalf("❌ Preflight checks failed (run `brewctl doctor` for details or pass --skip-preflight)")
+			}
+		}
+
+		if err := kube.CreateKindCluster(cfg.Cluster); err != nil {
 			log.Fatalf("❌ Failed to create Kind cluster: %v", err)
 		}
 
+		if err := checkLegacyNamespace(); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
 		// CORREÇÃO: MongoDB PRIMEIRO, depois Airbyte
-		if err := kube.DeployMongoDB(); err != nil {
+		if err := deployMongoDB(); err != nil {
 			log.Fatalf("❌ Failed to deploy MongoDB: %v", err)
 		}
 
-		if err := airbyte.Deploy(); err != nil {
+		if err := airbyte.Deploy(cfg.Airbyte); err != nil {
 			log.Fatalf("❌ Failed to deploy Airbyte: %v", err)
 		}
 
-		if err := monitoring.Deploy(); err != nil {
+		if err := monitoring.Deploy(cfg.Monitoring); err != nil {
 			log.Fatalf("❌ Failed to deploy monitoring stack: %v", err)
 		}
 
+		if err := kube.ApplyNetworkPolicies(cfg); err != nil {
+			log.Fatalf("❌ Failed to apply network policies: %v", err)
+		}
+
 		fmt.Println("✅ Cluster initialization completed!")
 		fmt.Println("🌐 Airbyte: http://localhost:8000")
 		fmt.Println("📊 Grafana: http://localhost:3000 (admin/admin)")
 		fmt.Println("📈 Prometheus: http://localhost:9090")
-		fmt.Println("🍃 MongoDB: localhost:27017")
+		if cfg.MongoDB.Mode == config.MongoDBModeExternal {
+			fmt.Println("🍃 MongoDB: external (mongodb.uri)")
+		} else {
+			fmt.Printf("🍃 MongoDB: localhost:27017 (user %s, password in secret %s/%s)\n",
+				config.MongoDBRootUser, cfg.MongoDB.Namespace, cfg.MongoDB.CredentialsSecretName())
+		}
 	},
 }
 
@@ -63,23 +161,34 @@ var deployConnectionsCmd = &cobra.Command{
 	Use:   "deploy-connections",
 	Short: "Deploy Airbyte source and destination connections",
 	Run: func(cmd *cobra.Command, args []string) {
+		names, _ := cmd.Flags().GetStringSlice("destination")
+		destinations, err := airbyte.ParseDestinations(names)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
 		fmt.Println("🔗 Deploying Airbyte connections...")
 
 		// CORREÇÃO: Aguardar Airbyte ficar totalmente pronto
 		fmt.Println("⏳ Waiting for Airbyte to be ready...")
 		time.Sleep(60 * time.Second)
 
-		client := airbyte.NewAirbyteClient("http://localhost:8000")
+		client := newAirbyteClient()
 		if err := client.WaitForReady(); err != nil {
 			log.Fatalf("❌ Airbyte not ready: %v", err)
 		}
 
-		if err := client.SetupConnections(); err != nil {
+		if contains(destinations, airbyte.DestinationMongoDB) {
+			checkMongoDBDestination()
+			resolveMongoDBCredentials()
+		}
+
+		if err := client.SetupConnections(cfg, destinations); err != nil {
 			log.Fatalf("❌ Failed to deploy connections: %v", err)
 		}
 
 		fmt.Println("✅ Airbyte connections deployed successfully!")
-		fmt.Println("💡 You can trigger sync in Airbyte UI at http://localhost:8000")
+		fmt.Printf("💡 You can trigger sync in Airbyte UI at %s\n", cfg.Airbyte.URL)
 	},
 }
 
@@ -89,14 +198,18 @@ var runAggregationsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("🔄 Running MongoDB aggregation pipelines...")
 
-		aggService, err := mongodb.NewAggregationService("mongodb://localhost:27017")
+		aggService, err := newAggregationService()
 		if err != nil {
 			log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
 		}
 		defer aggService.Close()
 
+		full, _ := cmd.Flags().GetBool("full")
+		overlap, _ := cmd.Flags().GetDuration("overlap")
+		aggService.AutoMigrate, _ = cmd.Flags().GetBool("migrate")
+
 		// Run Silver Layer
-		if err := aggService.RunSilverLayerAggregation(); err != nil {
+		if err := aggService.RunSilverLayerAggregation(mongodb.SilverOptions{Full: full, Overlap: overlap}); err != nil {
 			log.Fatalf("❌ Silver layer aggregation failed: %v", err)
 		}
 
@@ -141,14 +254,14 @@ var statusCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("🔍 Checking cluster status...")
 
-		if err := kube.CheckClusterStatus(); err != nil {
+		if err := kube.CheckClusterStatus(""); err != nil {
 			log.Printf("⚠️ Cluster status: %v", err)
 		} else {
 			fmt.Println("✅ Kubernetes cluster is healthy")
 		}
 
 		// Check MongoDB
-		aggService, err := mongodb.NewAggregationService("mongodb://localhost:27017")
+		aggService, err := newAggregationService()
 		if err != nil {
 			log.Printf("⚠️ MongoDB connection: %v", err)
 		} else {
@@ -174,10 +287,24 @@ var statusCmd = &cobra.Command{
 			} else {
 				log.Printf("⚠️ Failed to count aggregated documents: %v", err)
 			}
+
+			if run, err := aggService.LastSuccessfulRun(ctx, mongodb.LayerSilver); err != nil {
+				log.Printf("⚠️ %v", err)
+			} else if run != nil {
+				fmt.Printf("🕒 Last silver run: %s (%s, %d bronze documents)\n",
+					run.StartedAt.Local().Format(time.RFC3339), run.Mode, run.Processed)
+			}
+
+			if run, err := aggService.LastSuccessfulRun(ctx, mongodb.LayerGold); err != nil {
+				log.Printf("⚠️ %v", err)
+			} else if run != nil {
+				fmt.Printf("🕒 Last gold build: %s at %s (%d records)\n",
+					run.BuildID, run.StartedAt.Local().Format(time.RFC3339), run.Processed)
+			}
 		}
 
 		// Check Airbyte
-		client := airbyte.NewAirbyteClient("http://localhost:8000")
+		client := newAirbyteClient()
 		if err := client.WaitForReady(); err != nil {
 			log.Printf("⚠️ Airbyte status: %v", err)
 		} else {
@@ -198,12 +325,15 @@ var fullPipelineCmd = &cobra.Command{
 
 		// Primeiro, deploy das conexões
 		fmt.Println("\n📍 Step 1: Deploying Airbyte connections...")
-		client := airbyte.NewAirbyteClient("http://localhost:8000")
+		client := newAirbyteClient()
 		if err := client.WaitForReady(); err != nil {
 			log.Fatalf("❌ Airbyte not ready: %v", err)
 		}
 
-		if err := client.SetupConnections(); err != nil {
+		checkMongoDBDestination()
+
+		resolveMongoDBCredentials()
+		if err := client.SetupConnections(cfg, []string{airbyte.DestinationMongoDB}); err != nil {
 			log.Fatalf("❌ Failed to deploy connections: %v", err)
 		}
 
@@ -213,13 +343,14 @@ var fullPipelineCmd = &cobra.Command{
 
 		// Depois, executar agregações
 		fmt.Println("\n📍 Step 2: Running MongoDB aggregations...")
-		aggService, err := mongodb.NewAggregationService("mongodb://localhost:27017")
+		aggService, err := newAggregationService()
 		if err != nil {
 			log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
 		}
 		defer aggService.Close()
+		aggService.AutoMigrate, _ = cmd.Flags().GetBool("migrate")
 
-		if err := aggService.RunSilverLayerAggregation(); err != nil {
+		if err := aggService.RunSilverLayerAggregation(mongodb.SilverOptions{}); err != nil {
 			log.Fatalf("❌ Silver layer aggregation failed: %v", err)
 		}
 
@@ -242,6 +373,19 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	clusterInitCmd.Flags().Bool("skip-preflight", false, "Skip host port, tool and Docker checks")
+
+	deployConnectionsCmd.Flags().StringSlice("destination", []string{airbyte.DestinationMongoDB},
+		"Comma-separated destinations to connect the brewery source to (mongodb, file, postgres)")
+
+	runAggregationsCmd.Flags().Bool("full", false, "Reprocess the whole bronze layer instead of only documents newer than the last silver run")
+	runAggregationsCmd.Flags().Duration("overlap", mongodb.DefaultWatermarkOverlap, "Window below the last silver watermark that is read again to catch late bronze writes")
+	for _, cmd := range []*cobra.Command{runAggregationsCmd, fullPipelineCmd} {
+		cmd.Flags().Bool("migrate", false, "Apply pending schema migrations instead of failing (a database never migrated always receives them)")
+	}
+
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to the brewctl config file (default $BREWCTL_CONFIG or ~/.brewctl/config.yaml)")
+
 	rootCmd.AddCommand(
 		clusterInitCmd,
 		deployConnectionsCmd,
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"brewctl/internal/config"
+	"brewctl/internal/kube"
+
+	"github.com/spf13/cobra"
+)
+
+var deployMongoDBCmd = &cobra.Command{
+	Use:   "deploy-mongodb",
+	Short: "Deploy MongoDB (manifest, Helm chart) or validate an external instance",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyMongoDBModeFlag(cmd); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if cfg.MongoDB.Mode != config.MongoDBModeExternal {
+			if err := checkLegacyNamespace(); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+		if err := deployMongoDB(); err != nil {
+			log.Fatalf("❌ Failed to deploy MongoDB: %v", err)
+		}
+	},
+}
+
+// applyMongoDBModeFlag sobrescreve mongodb.mode com --mongodb-mode, se informada
+func applyMongoDBModeFlag(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("mongodb-mode") {
+		return nil
+	}
+	cfg.MongoDB.Mode, _ = cmd.Flags().GetString("mongodb-mode")
+	return cfg.MongoDB.Validate()
+}
+
+// deployMongoDB implanta o MongoDB no cluster ou, no modo external, apenas
+// confirma que a URI configurada responde
+func deployMongoDB() error {
+	if cfg.MongoDB.Mode != config.MongoDBModeExternal {
+		return kube.DeployMongoDB(cfg.MongoDB)
+	}
+
+	fmt.Println("🍃 Using external MongoDB, skipping deployment...")
+	aggService, err := newAggregationService()
+	if err != nil {
+		return fmt.Errorf("external MongoDB is not reachable: %v", err)
+	}
+	defer aggService.Close()
+
+	fmt.Println("✅ External MongoDB is reachable")
+	return nil
+}
+
+func addMongoDBModeFlag(cmd *cobra.Command) {
+	cmd.Flags().String("mongodb-mode", "", "How to provide MongoDB: manifest, helm or external (default from config: manifest)")
+}
+
+func init() {
+	addMongoDBModeFlag(deployMongoDBCmd)
+	addMongoDBModeFlag(clusterInitCmd)
+	rootCmd.AddCommand(deployMongoDBCmd)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"brewctl/internal/kube"
+
+	"github.com/spf13/cobra"
+)
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward [all|airbyte|grafana|prometheus|mongodb]...",
+	Short: "Forward Airbyte, Grafana, Prometheus and MongoDB to localhost",
+	Long: `Forward the brewctl services to localhost through the Kubernetes API, without
+relying on NodePorts or Kind host port mappings, so it also works on non-Kind clusters.
+Local ports default to 8000, 3000, 9090 and 27017 (shifted by --port-offset); a port
+that is already in use, e.g. by the Kind host port mappings, is replaced by a free one
+and the actual URLs are printed. Runs in the foreground and reconnects automatically
+until interrupted (Ctrl+C).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		kubeContext, _ := cmd.Flags().GetString("context")
+		offset, _ := cmd.Flags().GetInt("port-offset")
+
+		targets, err := kube.SelectForwardTargets(kube.ForwardTargets(cfg), args)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		targets = kube.WithPortOffset(targets, offset)
+
+		client, err := kube.NewClient(kubeContext)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Println("🔌 Starting port-forwards (Ctrl+C to stop)...")
+		if err := kube.NewPortForwarder(client, os.Stdout).Run(ctx, targets); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Println("👋 Port-forwards stopped")
+	},
+}
+
+func init() {
+	portForwardCmd.Flags().String("context", "", "Kubeconfig context to use (default: current context)")
+	portForwardCmd.Flags().Int("port-offset", 0, "Added to every default local port (e.g. 10000 forwards Airbyte to 18000)")
+	rootCmd.AddCommand(portForwardCmd)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"brewctl/internal/airbyte"
+	"brewctl/internal/config"
+	"brewctl/internal/kube"
+	"brewctl/internal/kube/helm"
+	"brewctl/internal/monitoring"
+
+	"github.com/spf13/cobra"
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Inspect and manage the Helm releases installed by brewctl",
+}
+
+var releaseStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of every managed Helm release",
+	Run: func(cmd *cobra.Command, args []string) {
+		m := helm.NewManager()
+		ctx := context.Background()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RELEASE\tNAMESPACE\tREVISION\tSTATUS\tCHART\tPINNED\tUPDATED")
+		for _, r := range managedReleases() {
+			status, err := m.Status(ctx, r.Name, r.Namespace)
+			switch {
+			case errors.Is(err, helm.ErrReleaseNotFound):
+				fmt.Fprintf(w, "%s\t%s\t-\tnot installed\t-\t%s\t-\n", r.Name, r.Namespace, r.Chart.Version)
+			case err != nil:
+				fmt.Fprintf(w, "%s\t%s\t-\terror: %v\t-\t%s\t-\n", r.Name, r.Namespace, err, r.Chart.Version)
+			default:
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s-%s\t%s\t%s\n", status.Name, status.Namespace, status.Revision,
+					status.Status, status.Chart, status.ChartVersion, r.Chart.Version, status.Updated.Format(time.RFC3339))
+			}
+		}
+		w.Flush()
+		warnLegacyNamespace()
+	},
+}
+
+var releaseRollbackCmd = &cobra.Command{
+	Use:   "rollback <release>",
+	Short: "Roll a managed release back to a previous revision",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := findRelease(args[0])
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		revision, _ := cmd.Flags().GetInt("revision")
+
+		fmt.Printf("⏪ Rolling back %s...\n", r.Name)
+		if err := helm.NewManager().Rollback(context.Background(), r.Name, r.Namespace, revision, r.Timeout); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Printf("✅ Release %s rolled back\n", r.Name)
+	},
+}
+
+var releaseUninstallCmd = &cobra.Command{
+	Use:   "uninstall <release>",
+	Short: "Uninstall a managed release",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := findRelease(args[0])
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		fmt.Printf("🗑️ Uninstalling %s...\n", r.Name)
+		if err := helm.NewManager().Uninstall(context.Background(), r.Name, r.Namespace); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Printf("✅ Release %s uninstalled\n", r.Name)
+	},
+}
+
+// managedReleases lista os releases Helm que o brewctl instala
+func managedReleases() []helm.Release {
+	releases := []helm.Release{
+		airbyte.Release(cfg.Airbyte),
+		monitoring.PrometheusRelease(cfg.Monitoring),
+		monitoring.GrafanaRelease(cfg.Monitoring),
+	}
+	if cfg.MongoDB.Mode == config.MongoDBModeHelm {
+		releases = append(releases, kube.MongoDBRelease(cfg.MongoDB))
+	}
+	return releases
+}
+
+func findRelease(name string) (helm.Release, error) {
+	var names []string
+	for _, r := range managedReleases() {
+		if r.Name == name {
+			return r, nil
+		}
+		names = append(names, r.Name)
+	}
+	return helm.Release{}, fmt.Errorf("unknown release %q (managed releases: %v)", name, names)
+}
+
+func init() {
+	releaseRollbackCmd.Flags().Int("revision", 0, "Revision to roll back to (default: the previous one)")
+
+	releaseCmd.AddCommand(releaseStatusCmd, releaseRollbackCmd, releaseUninstallCmd)
+	rootCmd.AddCommand(releaseCmd)
+}
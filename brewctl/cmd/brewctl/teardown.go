@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"brewctl/internal/airbyte"
+	"brewctl/internal/config"
+	"brewctl/internal/kube"
+	"brewctl/internal/monitoring"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	componentAirbyte    = "airbyte"
+	componentMongoDB    = "mongodb"
+	componentMonitoring = "monitoring"
+	componentAll        = "all"
+)
+
+var teardownCmd = &cobra.Command{
+	Use:   "teardown",
+	Short: "Remove the components installed by cluster-init",
+	Long: `Remove the components installed by cluster-init: Helm releases, the MongoDB
+resources and their volumes. Use --keep-data to preserve the PVCs (and the MongoDB
+credentials), --connections to delete the Airbyte sources, destinations and
+connections created by deploy-connections, and --delete-cluster to delete the Kind
+cluster as well.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		component, _ := cmd.Flags().GetString("component")
+		keepData, _ := cmd.Flags().GetBool("keep-data")
+		deleteCluster, _ := cmd.Flags().GetBool("delete-cluster")
+		connections, _ := cmd.Flags().GetBool("connections")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		components, err := teardownComponents(component)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		plan := teardownPlan(components, keepData, connections, deleteCluster)
+		fmt.Println("🧹 The following will be removed:")
+		for _, step := range plan {
+			fmt.Printf("  • %s\n", step)
+		}
+		if !yes && !confirm(os.Stdin, "Proceed?") {
+			fmt.Println("ℹ️ Teardown cancelled")
+			return
+		}
+
+		if connections {
+			removed, err := newAirbyteClient().TeardownConnections()
+			if err != nil {
+				log.Fatalf("❌ Failed to remove Airbyte connections: %v", err)
+			}
+			fmt.Printf("✅ Removed %d Airbyte resources\n", len(removed))
+		}
+
+		for _, c := range components {
+			if err := teardownComponent(c, keepData); err != nil {
+				log.Fatalf("❌ Failed to remove %s: %v", c, err)
+			}
+		}
+
+		if deleteCluster {
+			if err := kube.DeleteKindCluster(cfg.Cluster.Name); err != nil {
+				log.Fatalf("❌ Failed to delete Kind cluster: %v", err)
+			}
+		}
+
+		if !deleteCluster {
+			warnLegacyNamespace()
+		}
+		fmt.Println("✅ Teardown completed!")
+	},
+}
+
+// teardownComponents expande --component na lista de componentes, na ordem
+// inversa à do cluster-init
+func teardownComponents(component string) ([]string, error) {
+	switch component {
+	case componentAll:
+		return []string{componentMonitoring, componentAirbyte, componentMongoDB}, nil
+	case componentAirbyte, componentMongoDB, componentMonitoring:
+		return []string{component}, nil
+	}
+	return nil, fmt.Errorf("unknown component %q (expected %s, %s, %s or %s)",
+		component, componentAirbyte, componentMongoDB, componentMonitoring, componentAll)
+}
+
+// teardownPlan descreve o que será removido, para a confirmação
+func teardownPlan(components []string, keepData, connections, deleteCluster bool) []string {
+	var plan []string
+	if connections {
+		plan = append(plan, fmt.Sprintf("Airbyte sources, destinations and connections created by brewctl (%s)", cfg.Airbyte.URL))
+	}
+	for _, c := range components {
+		switch c {
+		case componentAirbyte:
+			step := fmt.Sprintf("Helm release airbyte in namespace %s", cfg.Airbyte.Namespace)
+			if !keepData {
+				step += " and its volumes"
+			}
+			plan = append(plan, step)
+		case componentMonitoring:
+			step := fmt.Sprintf("Helm releases grafana and prometheus in namespace %s", cfg.Monitoring.Namespace)
+			if !keepData {
+				step += " and their volumes"
+			}
+			plan = append(plan, step)
+		case componentMongoDB:
+			switch {
+			case cfg.MongoDB.Mode == config.MongoDBModeExternal:
+				plan = append(plan, "nothing for MongoDB (external mode)")
+			case keepData:
+				plan = append(plan, fmt.Sprintf("MongoDB (%s mode) in namespace %s, keeping volumes and credentials", cfg.MongoDB.Mode, cfg.MongoDB.Namespace))
+			default:
+				plan = append(plan, fmt.Sprintf("MongoDB (%s mode) in namespace %s, including volumes and credentials", cfg.MongoDB.Mode, cfg.MongoDB.Namespace))
+			}
+		}
+	}
+	if deleteCluster {
+		plan = append(plan, fmt.Sprintf("Kind cluster %s", cfg.Cluster.Name))
+	}
+	return plan
+}
+
+func teardownComponent(component string, keepData bool) error {
+	switch component {
+	case componentAirbyte:
+		return airbyte.Teardown(cfg.Airbyte, keepData)
+	case componentMonitoring:
+		return monitoring.Teardown(cfg.Monitoring, keepData)
+	case componentMongoDB:
+		return kube.TeardownMongoDB(cfg.MongoDB, keepData)
+	}
+	return nil
+}
+
+// confirm pergunta ao usuário e só aceita "y" ou "yes" como resposta positiva
+func confirm(in io.Reader, prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func init() {
+	teardownCmd.Flags().String("component", componentAll, "Component to remove: airbyte, mongodb, monitoring or all")
+	teardownCmd.Flags().Bool("keep-data", false, "Keep PersistentVolumeClaims and the MongoDB credentials secret")
+	teardownCmd.Flags().Bool("delete-cluster", false, "Also delete the Kind cluster")
+	teardownCmd.Flags().Bool("connections", false, "Also delete the Airbyte resources created by deploy-connections")
+	teardownCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	rootCmd.AddCommand(teardownCmd)
+}
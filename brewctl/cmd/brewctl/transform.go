@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"brewctl/internal/mongodb"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var transformCmd = &cobra.Command{
+	Use:   "transform",
+	Short: "Print the silver document for bronze records without MongoDB",
+	Long: `Apply the silver transformation to bronze records in Go and print the
+cleaned documents as Extended JSON. --input is a JSON (or Extended JSON) file
+with one record or an array of records; "-" reads from stdin.
+
+The output is what the silver aggregation writes for each record, including
+data_quality, except for _id, ingestion_date and last_updated, which are set
+by the server. Records without a string id are skipped, as in the silver layer.
+Each record is transformed on its own: when the input repeats an id, the silver
+layer keeps only the version with the latest updated_at.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+
+		records, isArray, err := readBronzeRecords(input)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		cleaned := []mongodb.Clean{}
+		for i, record := range records {
+			clean, _ := mongodb.CleanBrewery(record)
+			if clean == nil {
+				fmt.Fprintf(os.Stderr, "⚠️ Record %d skipped: the silver layer requires a string id\n", i+1)
+				continue
+			}
+			cleaned = append(cleaned, clean)
+		}
+
+		var output interface{} = cleaned
+		if !isArray {
+			if len(cleaned) == 0 {
+				os.Exit(1)
+			}
+			output = cleaned[0]
+		}
+		out, err := mongodb.ExtJSON(output)
+		if err != nil {
+			log.Fatalf("❌ Failed to format silver documents: %v", err)
+		}
+		fmt.Println(out)
+	},
+}
+
+// readBronzeRecords lê um registro ou um array de registros do arquivo, ou do
+// stdin quando path é "-"
+func readBronzeRecords(path string) ([]bson.M, bool, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("[")) {
+		var record bson.M
+		if err := bson.UnmarshalExtJSON(data, false, &record); err != nil {
+			return nil, false, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		return []bson.M{record}, false, nil
+	}
+
+	// O Extended JSON só é lido como documento, então o array vai em um envelope
+	var envelope struct {
+		Records []bson.M `bson:"records"`
+	}
+	wrapped := append(append([]byte(`{"records": `), data...), '}')
+	if err := bson.UnmarshalExtJSON(wrapped, false, &envelope); err != nil {
+		return nil, false, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return envelope.Records, true, nil
+}
+
+func init() {
+	transformCmd.Flags().String("input", "", `Bronze record(s) in JSON; "-" reads from stdin`)
+	transformCmd.MarkFlagRequired("input")
+	rootCmd.AddCommand(transformCmd)
+}
@@ -0,0 +1,176 @@
+package airbyte
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// LegacyAPI é a Config API (/api/v1), descontinuada nas versões novas do Airbyte
+	LegacyAPI = "legacy"
+	// PublicAPI é a API REST pública (/api/public/v1)
+	PublicAPI = "public"
+
+	legacyHealthEndpoint = "/api/v1/health"
+	publicHealthEndpoint = "/api/public/v1/health"
+)
+
+// AirbyteAPI abstrai as operações usadas pelo brewctl, que existem tanto na
+// Config API legada quanto na API pública do Airbyte
+type AirbyteAPI interface {
+	Name() string
+	GetFirstWorkspace() (string, error)
+	CreateSource(workspaceID, name, sourceDefinitionID string, config map[string]interface{}) (string, error)
+	CreateDestination(workspaceID, name, destinationDefinitionID string, config map[string]interface{}) (string, error)
+	CreateConnection(sourceID, destinationID, name string) (string, error)
+	TestConnection(connectionID string) error
+	SyncConnection(connectionID string) error
+	ListJobs(connectionID string, limit int) ([]Job, error)
+	GetJob(jobID int64) (*Job, error)
+	ListResources(kind ResourceKind, workspaceID string) ([]Resource, error)
+	DeleteResource(kind ResourceKind, id string) error
+}
+
+// NewAPI retorna a implementação de AirbyteAPI com o nome informado
+func (c *AirbyteClient) NewAPI(name string) (AirbyteAPI, error) {
+	switch name {
+	case LegacyAPI:
+		return &configAPI{c: c}, nil
+	case PublicAPI:
+		return &publicAPI{c: c}, nil
+	}
+	return nil, fmt.Errorf("unknown airbyte API %q (expected %s or %s)", name, LegacyAPI, PublicAPI)
+}
+
+// DetectAPI descobre qual API o servidor suporta, preferindo a API pública
+func (c *AirbyteClient) DetectAPI() (AirbyteAPI, error) {
+	publicStatus, publicErr := c.probe(publicHealthEndpoint)
+	if publicErr == nil && publicStatus == http.StatusOK {
+		return &publicAPI{c: c}, nil
+	}
+
+	legacyStatus, legacyErr := c.probe(legacyHealthEndpoint)
+	if legacyErr == nil && legacyStatus == http.StatusOK {
+		return &configAPI{c: c}, nil
+	}
+
+	if publicErr != nil {
+		return nil, fmt.Errorf("failed to detect airbyte API: %v", publicErr)
+	}
+	return nil, fmt.Errorf("failed to detect airbyte API: %s returned %d, %s returned %d",
+		publicHealthEndpoint, publicStatus, legacyHealthEndpoint, legacyStatus)
+}
+
+func (c *AirbyteClient) probe(endpoint string) (int, error) {
+	req, err := c.newRequest("GET", c.BaseURL+endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// api retorna a API configurada, detectando-a na primeira chamada
+func (c *AirbyteClient) api() (AirbyteAPI, error) {
+	if c.API != nil {
+		return c.API, nil
+	}
+
+	api, err := c.DetectAPI()
+	if err != nil {
+		return nil, err
+	}
+	if !c.Quiet {
+		fmt.Printf("🔎 Using Airbyte %s API\n", api.Name())
+	}
+	c.API = api
+	return api, nil
+}
+
+// APIName retorna o nome da API em uso ("legacy" ou "public")
+func (c *AirbyteClient) APIName() (string, error) {
+	api, err := c.api()
+	if err != nil {
+		return "", err
+	}
+	return api.Name(), nil
+}
+
+// GetFirstWorkspace obtém o primeiro workspace disponível
+func (c *AirbyteClient) GetFirstWorkspace() (string, error) {
+	api, err := c.api()
+	if err != nil {
+		return "", err
+	}
+	return api.GetFirstWorkspace()
+}
+
+// CreateSource cria uma nova source no Airbyte
+func (c *AirbyteClient) CreateSource(workspaceID, name, sourceDefinitionID string, config map[string]interface{}) (string, error) {
+	api, err := c.api()
+	if err != nil {
+		return "", err
+	}
+	return api.CreateSource(workspaceID, name, sourceDefinitionID, config)
+}
+
+// CreateDestination cria um novo destination no Airbyte
+func (c *AirbyteClient) CreateDestination(workspaceID, name, destinationDefinitionID string, config map[string]interface{}) (string, error) {
+	api, err := c.api()
+	if err != nil {
+		return "", err
+	}
+	return api.CreateDestination(workspaceID, name, destinationDefinitionID, config)
+}
+
+// CreateConnection cria uma conexão entre source e destination
+func (c *AirbyteClient) CreateConnection(sourceID, destinationID, name string) (string, error) {
+	api, err := c.api()
+	if err != nil {
+		return "", err
+	}
+	return api.CreateConnection(sourceID, destinationID, name)
+}
+
+// TestConnection testa uma conexão existente
+func (c *AirbyteClient) TestConnection(connectionID string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.TestConnection(connectionID)
+}
+
+// SyncConnection inicia uma sincronização manual
+func (c *AirbyteClient) SyncConnection(connectionID string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.SyncConnection(connectionID)
+}
+
+// ListJobs lista os jobs de sincronização de uma conexão, do mais recente para o mais antigo
+func (c *AirbyteClient) ListJobs(connectionID string, limit int) ([]Job, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+	return api.ListJobs(connectionID, limit)
+}
+
+// GetJob obtém um job com suas tentativas (e, na API legada, as linhas de log)
+func (c *AirbyteClient) GetJob(jobID int64) (*Job, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+	return api.GetJob(jobID)
+}
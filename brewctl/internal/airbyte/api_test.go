@@ -0,0 +1,114 @@
+package airbyte
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAPI(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints map[string]int
+		want      string
+	}{
+		{"public available", map[string]int{publicHealthEndpoint: 200, legacyHealthEndpoint: 200}, PublicAPI},
+		{"legacy only", map[string]int{publicHealthEndpoint: 404, legacyHealthEndpoint: 200}, LegacyAPI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status, ok := tt.endpoints[r.URL.Path]
+				if !ok {
+					status = http.StatusNotFound
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			api, err := NewAirbyteClient(server.URL).DetectAPI()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, api.Name())
+		})
+	}
+}
+
+func TestPublicAPIListJobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/public/v1/jobs", r.URL.Path)
+		assert.Equal(t, "conn-1", r.URL.Query().Get("connectionId"))
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{{
+				"jobId":         42,
+				"status":        "succeeded",
+				"jobType":       "sync",
+				"connectionId":  "conn-1",
+				"startTime":     "2024-05-01T10:00:00Z",
+				"lastUpdatedAt": "2024-05-01T10:05:00Z",
+				"rowsSynced":    8300,
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAirbyteClient(server.URL)
+	client.Quiet = true
+	client.API = &publicAPI{c: client}
+
+	jobs, err := client.ListJobs("conn-1", 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	assert.Equal(t, int64(42), jobs[0].ID)
+	assert.True(t, jobs[0].Finished())
+	assert.Equal(t, int64(8300), jobs[0].RecordsSynced())
+	assert.Equal(t, "5m0s", jobs[0].Duration().String())
+}
+
+func TestTeardownConnectionsDeletesOnlyManagedResources(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var data []map[string]interface{}
+		switch r.URL.Path {
+		case "/api/public/v1/workspaces":
+			data = []map[string]interface{}{{"workspaceId": "ws-1", "name": "Default"}}
+		case "/api/public/v1/connections":
+			assert.Equal(t, "ws-1", r.URL.Query().Get("workspaceIds"))
+			data = []map[string]interface{}{
+				{"connectionId": "conn-1", "name": MongoDBConnectionName},
+				{"connectionId": "conn-2", "name": "Someone else's pipeline"},
+			}
+		case "/api/public/v1/destinations":
+			data = []map[string]interface{}{{"destinationId": "dst-1", "name": MongoDBDestinationName}}
+		case "/api/public/v1/sources":
+			data = []map[string]interface{}{{"sourceId": "src-1", "name": BrewerySourceName}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	client := NewAirbyteClient(server.URL)
+	client.Quiet = true
+	client.API = &publicAPI{c: client}
+
+	removed, err := client.TeardownConnections()
+	require.NoError(t, err)
+	assert.Len(t, removed, 3)
+	assert.Equal(t, []string{
+		"/api/public/v1/connections/conn-1",
+		"/api/public/v1/destinations/dst-1",
+		"/api/public/v1/sources/src-1",
+	}, deleted)
+}
@@ -0,0 +1,184 @@
+package airbyte
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"brewctl/internal/config"
+)
+
+// Authenticator adiciona credenciais às requisições feitas para a API do Airbyte.
+// As implementações nunca devem expor segredos em String() ou em mensagens de erro.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// invalidator é implementado por autenticadores cujas credenciais podem ser
+// renovadas depois de uma resposta 401
+type invalidator interface {
+	Invalidate()
+}
+
+// BasicAuth autentica com usuário e senha (ex.: Airbyte atrás de um proxy com basic auth)
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a *BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+func (a *BasicAuth) String() string {
+	return fmt.Sprintf("basic(user=%s, password=%s)", a.Username, redact(a.Password))
+}
+
+// BearerToken autentica com um token estático
+type BearerToken struct {
+	Token string
+}
+
+func (a *BearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+func (a *BearerToken) String() string {
+	return fmt.Sprintf("bearer(token=%s)", redact(a.Token))
+}
+
+// ClientCredentials obtém tokens de acesso a partir do client ID/secret de uma
+// aplicação do Airbyte e os renova automaticamente antes de expirarem.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// tokenExpiryMargin antecipa a renovação para evitar usar um token prestes a expirar
+const tokenExpiryMargin = 30 * time.Second
+
+func (a *ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := a.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token retorna um token válido, solicitando um novo se necessário
+func (a *ClientCredentials) Token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && (a.expiry.IsZero() || time.Now().Add(tokenExpiryMargin).Before(a.expiry)) {
+		return a.token, nil
+	}
+
+	token, expiresIn, err := a.requestToken()
+	if err != nil {
+		return "", err
+	}
+
+	a.token = token
+	a.expiry = time.Time{}
+	if expiresIn > 0 {
+		a.expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return a.token, nil
+}
+
+// Invalidate descarta o token atual, forçando a renovação na próxima requisição
+func (a *ClientCredentials) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = ""
+	a.expiry = time.Time{}
+}
+
+func (a *ClientCredentials) String() string {
+	return fmt.Sprintf("client_credentials(client_id=%s, client_secret=%s)", a.ClientID, redact(a.ClientSecret))
+}
+
+func (a *ClientCredentials) requestToken() (string, int64, error) {
+	body, err := json.Marshal(map[string]string{
+		"client_id":     a.ClientID,
+		"client_secret": a.ClientSecret,
+		"grant-type":    "client_credentials",
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("marshaling token request failed: %v", err)
+	}
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Post(a.TokenURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return "", 0, fmt.Errorf("decoding token response failed: %v", err)
+	}
+	if result.AccessToken == "" {
+		return "", 0, fmt.Errorf("token endpoint returned an empty access_token")
+	}
+	return result.AccessToken, result.ExpiresIn, nil
+}
+
+// NewAuthenticator cria o Authenticator descrito na configuração. Retorna nil
+// quando nenhuma autenticação é necessária.
+func NewAuthenticator(cfg config.AirbyteAuth, baseURL string, httpClient *http.Client) (Authenticator, error) {
+	switch cfg.ResolvedType() {
+	case config.AuthNone:
+		return nil, nil
+	case config.AuthBasic:
+		return &BasicAuth{Username: cfg.Username, Password: cfg.Password}, nil
+	case config.AuthBearer:
+		return &BearerToken{Token: cfg.Token}, nil
+	case config.AuthClientCredentials:
+		tokenURL := cfg.TokenURL
+		if tokenURL == "" {
+			tokenURL = strings.TrimSuffix(baseURL, "/") + "/api/v1/applications/token"
+		}
+		return &ClientCredentials{
+			TokenURL:     tokenURL,
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			HTTPClient:   httpClient,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported airbyte auth type %q", cfg.Type)
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return "<empty>"
+	}
+	return "****"
+}
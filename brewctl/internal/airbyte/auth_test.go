@@ -0,0 +1,100 @@
+package airbyte
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"brewctl/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicAuthIsSentOnRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "brew" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewAirbyteClientFromConfig(config.AirbyteConfig{
+		URL:  server.URL,
+		Auth: config.AirbyteAuth{Username: "brew", Password: "s3cret"},
+	})
+	require.NoError(t, err)
+	client.Quiet = true
+
+	resp, err := client.makeRequest("POST", "/api/v1/workspaces/list", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestClientCredentialsRefreshesTokenAfterUnauthorized(t *testing.T) {
+	var issued int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/applications/token", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "app-id", body["client_id"])
+		assert.Equal(t, "app-secret", body["client_secret"])
+
+		n := atomic.AddInt32(&issued, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"expires_in":   180,
+		})
+	})
+	mux.HandleFunc("/api/v1/workspaces/list", func(w http.ResponseWriter, r *http.Request) {
+		// O primeiro token é considerado revogado pelo servidor
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewAirbyteClientFromConfig(config.AirbyteConfig{
+		URL:  server.URL,
+		Auth: config.AirbyteAuth{ClientID: "app-id", ClientSecret: "app-secret"},
+	})
+	require.NoError(t, err)
+	client.Quiet = true
+
+	resp, err := client.makeRequest("POST", "/api/v1/workspaces/list", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued))
+
+	// Token ainda válido deve ser reutilizado
+	resp, err = client.makeRequest("POST", "/api/v1/workspaces/list", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued))
+}
+
+func TestAuthenticatorsDoNotLeakSecrets(t *testing.T) {
+	auths := []Authenticator{
+		&BasicAuth{Username: "brew", Password: "s3cret"},
+		&BearerToken{Token: "s3cret"},
+		&ClientCredentials{ClientID: "app-id", ClientSecret: "s3cret"},
+	}
+
+	for _, auth := range auths {
+		assert.False(t, strings.Contains(fmt.Sprint(auth), "s3cret"), "%T leaks its secret", auth)
+	}
+}
@@ -0,0 +1,329 @@
+package airbyte
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// configAPI implementa AirbyteAPI sobre a Config API legada (/api/v1)
+type configAPI struct {
+	c *AirbyteClient
+}
+
+func (a *configAPI) Name() string {
+	return LegacyAPI
+}
+
+// GetFirstWorkspace obtém o primeiro workspace disponível
+func (a *configAPI) GetFirstWorkspace() (string, error) {
+	resp, err := a.c.makeRequest("POST", "/api/v1/workspaces/list", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list workspaces: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("workspace list API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Workspaces []struct {
+			WorkspaceID string `json:"workspaceId"`
+			Name        string `json:"name"`
+		} `json:"workspaces"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding workspace response failed: %v", err)
+	}
+
+	if len(result.Workspaces) == 0 {
+		return "", fmt.Errorf("no workspaces found")
+	}
+
+	fmt.Printf("✅ Found workspace: %s (%s)\n", result.Workspaces[0].Name, result.Workspaces[0].WorkspaceID)
+	return result.Workspaces[0].WorkspaceID, nil
+}
+
+// CreateSource cria uma nova source no Airbyte
+func (a *configAPI) CreateSource(workspaceID, name, sourceDefinitionID string, config map[string]interface{}) (string, error) {
+	sourceReq := map[string]interface{}{
+		"workspaceId":             workspaceID,
+		"name":                    name,
+		"sourceDefinitionId":      sourceDefinitionID,
+		"connectionConfiguration": config,
+	}
+
+	resp, err := a.c.makeRequest("POST", "/api/v1/sources/create", sourceReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to create source: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("source creation API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		SourceID string `json:"sourceId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding source response failed: %v", err)
+	}
+
+	fmt.Printf("✅ Created source: %s (%s)\n", name, result.SourceID)
+	return result.SourceID, nil
+}
+
+// CreateDestination cria um novo destination no Airbyte
+func (a *configAPI) CreateDestination(workspaceID, name, destinationDefinitionID string, config map[string]interface{}) (string, error) {
+	destinationReq := map[string]interface{}{
+		"workspaceId":             workspaceID,
+		"name":                    name,
+		"destinationDefinitionId": destinationDefinitionID,
+		"connectionConfiguration": config,
+	}
+
+	resp, err := a.c.makeRequest("POST", "/api/v1/destinations/create", destinationReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to create destination: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("destination creation API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		DestinationID string `json:"destinationId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding destination response failed: %v", err)
+	}
+
+	fmt.Printf("✅ Created destination: %s (%s)\n", name, result.DestinationID)
+	return result.DestinationID, nil
+}
+
+// CreateConnection cria uma conexão entre source e destination
+func (a *configAPI) CreateConnection(sourceID, destinationID, name string) (string, error) {
+	connectionConfig := map[string]interface{}{
+		"name":          name,
+		"sourceId":      sourceID,
+		"destinationId": destinationID,
+		"syncCatalog": map[string]interface{}{
+			"streams": []map[string]interface{}{
+				{
+					"stream": map[string]interface{}{
+						"name": "breweries",
+						"jsonSchema": map[string]interface{}{
+							"type":       "object",
+							"properties": map[string]interface{}{},
+						},
+						"supportedSyncModes":      []string{"full_refresh", "incremental"},
+						"sourceDefinedCursor":     false,
+						"defaultCursorField":      []string{},
+						"sourceDefinedPrimaryKey": [][]string{{"id"}},
+						"namespace":               "public",
+					},
+					"config": map[string]interface{}{
+						"syncMode":            "full_refresh",
+						"cursorField":         []string{},
+						"destinationSyncMode": "append",
+						"primaryKey":          [][]string{{"id"}},
+						"selected":            true,
+						"aliasName":           "breweries",
+					},
+				},
+			},
+		},
+		"scheduleType": "manual",
+		"scheduleData": map[string]interface{}{
+			"basicSchedule": map[string]interface{}{
+				"timeUnit": "hours",
+				"units":    24,
+			},
+		},
+		"status": "active",
+	}
+
+	resp, err := a.c.makeRequest("POST", "/api/v1/connections/create", connectionConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to create connection: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("connection creation API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		ConnectionID string `json:"connectionId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding connection response failed: %v", err)
+	}
+
+	fmt.Printf("✅ Created connection: %s (%s)\n", name, result.ConnectionID)
+	return result.ConnectionID, nil
+}
+
+// TestConnection testa uma conexão existente
+func (a *configAPI) TestConnection(connectionID string) error {
+	testReq := map[string]interface{}{
+		"connectionId": connectionID,
+	}
+
+	resp, err := a.c.makeRequest("POST", "/api/v1/connections/get", testReq)
+	if err != nil {
+		return fmt.Errorf("failed to test connection: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("connection test failed with status %d", resp.StatusCode)
+	}
+
+	fmt.Printf("✅ Connection %s is valid\n", connectionID)
+	return nil
+}
+
+// SyncConnection inicia uma sincronização manual
+func (a *configAPI) SyncConnection(connectionID string) error {
+	syncReq := map[string]interface{}{
+		"connectionId": connectionID,
+	}
+
+	resp, err := a.c.makeRequest("POST", "/api/v1/connections/sync", syncReq)
+	if err != nil {
+		return fmt.Errorf("failed to start sync: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("sync failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	fmt.Printf("✅ Started sync for connection: %s\n", connectionID)
+	return nil
+}
+
+// ListJobs lista os jobs de sincronização de uma conexão, do mais recente para o mais antigo
+func (a *configAPI) ListJobs(connectionID string, limit int) ([]Job, error) {
+	listReq := map[string]interface{}{
+		"configTypes": []string{"sync", "reset_connection"},
+		"configId":    connectionID,
+		"pagination": map[string]interface{}{
+			"pageSize":  limit,
+			"rowOffset": 0,
+		},
+	}
+
+	resp, err := a.c.makeRequest("POST", "/api/v1/jobs/list", listReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("job list API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Jobs []struct {
+			Job      jobPayload       `json:"job"`
+			Attempts []attemptPayload `json:"attempts"`
+		} `json:"jobs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding job list response failed: %v", err)
+	}
+
+	jobs := make([]Job, 0, len(result.Jobs))
+	for _, item := range result.Jobs {
+		job := item.Job.toJob()
+		for _, a := range item.Attempts {
+			job.Attempts = append(job.Attempts, a.toAttempt())
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetJob obtém um job com todas as tentativas e suas linhas de log
+func (a *configAPI) GetJob(jobID int64) (*Job, error) {
+	resp, err := a.c.makeRequest("POST", "/api/v1/jobs/get", map[string]interface{}{"id": jobID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("job get API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Job      jobPayload `json:"job"`
+		Attempts []struct {
+			Attempt attemptPayload `json:"attempt"`
+			Logs    struct {
+				LogLines []string `json:"logLines"`
+			} `json:"logs"`
+		} `json:"attempts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding job response failed: %v", err)
+	}
+
+	job := result.Job.toJob()
+	for _, a := range result.Attempts {
+		attempt := a.Attempt.toAttempt()
+		attempt.LogLines = a.Logs.LogLines
+		job.Attempts = append(job.Attempts, attempt)
+	}
+	return &job, nil
+}
+
+// ListResources lista os recursos do tipo informado no workspace
+func (a *configAPI) ListResources(kind ResourceKind, workspaceID string) ([]Resource, error) {
+	resp, err := a.c.makeRequest("POST", fmt.Sprintf("/api/v1/%s/list", kind), map[string]interface{}{"workspaceId": workspaceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s list API returned status %d: %s", kind, resp.StatusCode, string(body))
+	}
+
+	var result map[string][]map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding %s response failed: %v", kind, err)
+	}
+	return toResources(kind, result[string(kind)]), nil
+}
+
+// DeleteResource remove um recurso pelo ID
+func (a *configAPI) DeleteResource(kind ResourceKind, id string) error {
+	resp, err := a.c.makeRequest("POST", fmt.Sprintf("/api/v1/%s/delete", kind), map[string]interface{}{kind.idField(): id})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %s: %v", kind, id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s delete API returned status %d: %s", kind, resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -2,12 +2,60 @@ package airbyte
 
 import (
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
+
+	"brewctl/internal/config"
+)
+
+// Destinations suportados por SetupConnections
+const (
+	DestinationMongoDB  = "mongodb"
+	DestinationFile     = "file"
+	DestinationPostgres = "postgres"
+)
+
+// Nomes dos recursos criados por SetupConnections, usados também pelo teardown
+const (
+	BrewerySourceName = "BreweryDB API"
+
+	MongoDBDestinationName  = "Breweries MongoDB"
+	FileJSONDestinationName = "Breweries Local JSON"
+	FileCSVDestinationName  = "Breweries Local CSV"
+	PostgresDestinationName = "Breweries Postgres"
+
+	MongoDBConnectionName  = "BreweryDB to MongoDB Pipeline"
+	FileConnectionName     = "BreweryDB to Local File Pipeline"
+	PostgresConnectionName = "BreweryDB to Postgres Pipeline"
 )
 
-// SetupConnections configura todas as conexões do Airbyte
-func (c *AirbyteClient) SetupConnections() error {
+// ParseDestinations valida a lista de destinations informada na CLI
+func ParseDestinations(names []string) ([]string, error) {
+	var destinations []string
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case DestinationMongoDB, DestinationFile, DestinationPostgres:
+		default:
+			return nil, fmt.Errorf("unknown destination %q (expected mongodb, file or postgres)", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			destinations = append(destinations, name)
+		}
+	}
+
+	if len(destinations) == 0 {
+		return nil, fmt.Errorf("at least one destination is required")
+	}
+	return destinations, nil
+}
+
+// SetupConnections cria a source da BreweryDB e uma conexão para cada destination
+func (c *AirbyteClient) SetupConnections(cfg *config.Config, destinations []string) error {
 	fmt.Println("🔗 Setting up Airbyte connections...")
 
 	// 1. Aguardar Airbyte ficar pronto
@@ -28,21 +76,36 @@ func (c *AirbyteClient) SetupConnections() error {
 		return fmt.Errorf("failed to create source: %v", err)
 	}
 
-	// 4. Criar destination do MongoDB
-	destinationID, err := c.CreateMongoDBDestination(workspaceID)
-	if err != nil {
-		return fmt.Errorf("failed to create destination: %v", err)
-	}
+	for _, destination := range destinations {
+		// 4. Criar o destination
+		var destinationID, connectionName string
+		switch destination {
+		case DestinationMongoDB:
+			destinationID, err = c.CreateMongoDBDestination(workspaceID, cfg.MongoDB)
+			connectionName = MongoDBConnectionName
+		case DestinationFile:
+			destinationID, err = c.CreateLocalFileDestination(workspaceID, cfg.Destinations.File)
+			connectionName = FileConnectionName
+		case DestinationPostgres:
+			destinationID, err = c.CreatePostgresDestination(workspaceID, cfg.Destinations.Postgres)
+			connectionName = PostgresConnectionName
+		default:
+			err = fmt.Errorf("unknown destination %q", destination)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create %s destination: %v", destination, err)
+		}
 
-	// 5. Criar conexão entre source e destination
-	connectionID, err := c.CreateConnection(sourceID, destinationID, "BreweryDB to MongoDB Pipeline")
-	if err != nil {
-		return fmt.Errorf("failed to create connection: %v", err)
-	}
+		// 5. Criar conexão entre source e destination
+		connectionID, err := c.CreateConnection(sourceID, destinationID, connectionName)
+		if err != nil {
+			return fmt.Errorf("failed to create %s connection: %v", destination, err)
+		}
 
-	// 6. Testar e iniciar a sincronização
-	if err := c.TestAndSyncConnection(connectionID); err != nil {
-		return fmt.Errorf("failed to sync connection: %v", err)
+		// 6. Testar e iniciar a sincronização
+		if err := c.TestAndSyncConnection(connectionID); err != nil {
+			return fmt.Errorf("failed to sync %s connection: %v", destination, err)
+		}
 	}
 
 	fmt.Println("🎯 Airbyte connections setup completed successfully!")
@@ -64,52 +127,139 @@ func (c *AirbyteClient) CreateBrewerySource(workspaceID string) (string, error)
 		"start_page":          1,
 	}
 
-	// CORREÇÃO: Source Definition ID correto para HTTP Request
+	// FIXME: este é o definition ID do destination-csv, não de uma source HTTP;
+	// o Airbyte rejeita a criação até ele ser trocado pelo ID da source usada
 	sourceDefinitionID := "8be1cf83-fde1-477f-a4ad-318d23c9f3c6"
 
-	return c.CreateSource(workspaceID, "BreweryDB API", sourceDefinitionID, sourceConfig)
+	return c.CreateSource(workspaceID, BrewerySourceName, sourceDefinitionID, sourceConfig)
 }
 
-// CreateMongoDBDestination cria um destination para MongoDB
-func (c *AirbyteClient) CreateMongoDBDestination(workspaceID string) (string, error) {
-	destinationConfig := map[string]interface{}{
-		"instance_type": "standalone",
-		"host":          "mongodb.default.svc.cluster.local",
-		"port":          27017,
-		"database":      "breweries_db",
-		"auth_type": map[string]interface{}{
-			"authorization": "none",
-		},
-		"tls": false,
+// CreateMongoDBDestination cria um destination para o MongoDB descrito na configuração
+func (c *AirbyteClient) CreateMongoDBDestination(workspaceID string, cfg config.MongoDBConfig) (string, error) {
+	if err := cfg.Validate(); err != nil {
+		return "", err
 	}
 
-	// CORREÇÃO: Destination Definition ID correto para MongoDB
-	destinationDefinitionID := "8e1c2c78-6c49-4c4a-b2c5-6e0b4b3c5a7b"
+	// Definition ID do conector destination-mongodb no catálogo do Airbyte
+	destinationDefinitionID := "8b746512-8c2e-6ac1-4adc-b59faafd473c"
 
-	return c.CreateDestination(workspaceID, "Breweries MongoDB", destinationDefinitionID, destinationConfig)
+	return c.CreateDestination(workspaceID, MongoDBDestinationName, destinationDefinitionID, MongoDBDestinationConfig(cfg))
 }
 
-// TestAndSyncConnection testa e inicia a sincronização
-func (c *AirbyteClient) TestAndSyncConnection(connectionID string) error {
-	fmt.Printf("🔍 Testing connection %s...\n", connectionID)
+// MongoDBDestinationConfig monta a connectionConfiguration do destination MongoDB
+func MongoDBDestinationConfig(cfg config.MongoDBConfig) map[string]interface{} {
+	dest := cfg.Destination
 
-	// Primeiro testar a conexão
-	testReq := map[string]interface{}{
-		"connectionId": connectionID,
+	var instance map[string]interface{}
+	switch dest.InstanceType {
+	case config.MongoDBReplica:
+		instance = map[string]interface{}{
+			"instance":         "replica",
+			"server_addresses": cfg.DestinationServerAddresses(),
+			"replica_set":      dest.ReplicaSet,
+		}
+	case config.MongoDBAtlas:
+		instance = map[string]interface{}{
+			"instance":    "atlas",
+			"cluster_url": dest.ClusterURL,
+		}
+	default:
+		instance = map[string]interface{}{
+			"instance": "standalone",
+			"host":     cfg.DestinationHost(),
+			"port":     cfg.Port,
+			"tls":      dest.TLS,
+		}
 	}
 
-	resp, err := c.makeRequest("POST", "/api/v1/connections/get", testReq)
-	if err != nil {
-		return fmt.Errorf("failed to get connection: %v", err)
+	var auth map[string]interface{}
+	switch dest.Auth.Type {
+	case config.MongoDBAuthLoginPassword:
+		auth = map[string]interface{}{
+			"authorization": "login/password",
+			"username":      dest.Auth.Username,
+			"password":      dest.Auth.Password,
+		}
+	default:
+		auth = map[string]interface{}{
+			"authorization": "none",
+		}
+	}
+
+	return map[string]interface{}{
+		"instance_type": instance,
+		"database":      cfg.Database,
+		"auth_type":     auth,
+	}
+}
+
+// CreateLocalFileDestination cria um destination de arquivo local (JSON lines ou CSV)
+func (c *AirbyteClient) CreateLocalFileDestination(workspaceID string, cfg config.FileDestination) (string, error) {
+	if err := cfg.Validate(); err != nil {
+		return "", err
+	}
+
+	// Local JSON grava um registro JSON por linha
+	destinationDefinitionID := "a625d593-bba5-4a1c-a53d-2d246268a816"
+	name := FileJSONDestinationName
+
+	if cfg.Format == config.FileFormatCSV {
+		// Definition ID do conector destination-csv
+		destinationDefinitionID = "8be1cf83-fde1-477f-a4ad-318d23c9f3c6"
+		name = FileCSVDestinationName
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("connection test failed with status %d: %s", resp.StatusCode, string(body))
+	return c.CreateDestination(workspaceID, name, destinationDefinitionID, LocalFileDestinationConfig(cfg))
+}
+
+// LocalFileDestinationConfig monta a connectionConfiguration do destination de arquivo local
+func LocalFileDestinationConfig(cfg config.FileDestination) map[string]interface{} {
+	destinationConfig := map[string]interface{}{
+		"destination_path": cfg.Path,
+	}
+	if cfg.Format == config.FileFormatCSV {
+		destinationConfig["delimiter_type"] = map[string]interface{}{
+			"delimiter": "\\u002c",
+		}
+	}
+	return destinationConfig
+}
+
+// CreatePostgresDestination cria um destination para o Postgres
+func (c *AirbyteClient) CreatePostgresDestination(workspaceID string, cfg config.PostgresDestination) (string, error) {
+	if err := cfg.Validate(); err != nil {
+		return "", err
 	}
 
-	fmt.Printf("✅ Connection %s is valid\n", connectionID)
+	destinationDefinitionID := "25c5221d-dce2-4163-ade9-739ef790f503"
+
+	return c.CreateDestination(workspaceID, PostgresDestinationName, destinationDefinitionID, PostgresDestinationConfig(cfg))
+}
+
+// PostgresDestinationConfig monta a connectionConfiguration do destination Postgres
+func PostgresDestinationConfig(cfg config.PostgresDestination) map[string]interface{} {
+	return map[string]interface{}{
+		"host":     cfg.Host,
+		"port":     cfg.Port,
+		"database": cfg.Database,
+		"schema":   cfg.Schema,
+		"username": cfg.Username,
+		"password": cfg.Password,
+		"ssl":      cfg.SSLMode != "disable",
+		"ssl_mode": map[string]interface{}{
+			"mode": cfg.SSLMode,
+		},
+	}
+}
+
+// TestAndSyncConnection testa e inicia a sincronização
+func (c *AirbyteClient) TestAndSyncConnection(connectionID string) error {
+	fmt.Printf("🔍 Testing connection %s...\n", connectionID)
+
+	// Primeiro testar a conexão
+	if err := c.TestConnection(connectionID); err != nil {
+		return fmt.Errorf("connection test failed: %v", err)
+	}
 
 	// Iniciar sincronização
 	fmt.Printf("🔄 Starting sync for connection %s...\n", connectionID)
@@ -0,0 +1,343 @@
+package airbyte
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"brewctl/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDestinations(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr string
+	}{
+		{"single", []string{"mongodb"}, []string{DestinationMongoDB}, ""},
+		{"normalized and deduplicated", []string{" File", "POSTGRES", "file", ""}, []string{DestinationFile, DestinationPostgres}, ""},
+		{"unknown", []string{"mongodb", "s3"}, nil, `unknown destination "s3"`},
+		{"empty", []string{"", " "}, nil, "at least one destination is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDestinations(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocalFileDestinationConfig(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"destination_path": "/local/breweries",
+	}, LocalFileDestinationConfig(config.FileDestination{Format: config.FileFormatJSONL, Path: "/local/breweries"}))
+
+	assert.Equal(t, map[string]interface{}{
+		"destination_path": "/local/breweries",
+		"delimiter_type":   map[string]interface{}{"delimiter": "\\u002c"},
+	}, LocalFileDestinationConfig(config.FileDestination{Format: config.FileFormatCSV, Path: "/local/breweries"}))
+}
+
+func TestPostgresDestinationConfig(t *testing.T) {
+	cfg := config.Default().Destinations.Postgres
+	cfg.Host = "postgres.example.com"
+	cfg.Username = "airbyte"
+	cfg.Password = "secret"
+
+	assert.Equal(t, map[string]interface{}{
+		"host":     "postgres.example.com",
+		"port":     5432,
+		"database": "breweries",
+		"schema":   "public",
+		"username": "airbyte",
+		"password": "secret",
+		"ssl":      false,
+		"ssl_mode": map[string]interface{}{"mode": "disable"},
+	}, PostgresDestinationConfig(cfg))
+
+	cfg.SSLMode = "verify-full"
+	got := PostgresDestinationConfig(cfg)
+	assert.Equal(t, true, got["ssl"])
+	assert.Equal(t, map[string]interface{}{"mode": "verify-full"}, got["ssl_mode"])
+}
+
+func TestCreateLocalFileDestinationDefinitions(t *testing.T) {
+	tests := []struct {
+		format       string
+		name         string
+		definitionID string
+	}{
+		{config.FileFormatJSONL, FileJSONDestinationName, "a625d593-bba5-4a1c-a53d-2d246268a816"},
+		{config.FileFormatCSV, FileCSVDestinationName, "8be1cf83-fde1-477f-a4ad-318d23c9f3c6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var body map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/api/public/v1/destinations", r.URL.Path)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				json.NewEncoder(w).Encode(map[string]interface{}{"destinationId": "dst-1"})
+			}))
+			defer server.Close()
+
+			client := NewAirbyteClient(server.URL)
+			client.Quiet = true
+			client.API = &publicAPI{c: client}
+
+			id, err := client.CreateLocalFileDestination("ws-1", config.FileDestination{Format: tt.format, Path: "/local/breweries"})
+			require.NoError(t, err)
+			assert.Equal(t, "dst-1", id)
+			assert.Equal(t, tt.name, body["name"])
+			assert.Equal(t, tt.definitionID, body["definitionId"])
+		})
+	}
+}
+
+func TestMongoDBDestinationConfig(t *testing.T) {
+	base := config.Default().MongoDB
+
+	tests := []struct {
+		name        string
+		destination config.MongoDBDestination
+		replicas    int
+		want        map[string]interface{}
+	}{
+		{
+			name: "standalone on the deployed service",
+			destination: config.MongoDBDestination{
+				InstanceType: config.MongoDBStandalone,
+				Auth:         config.MongoDBAuth{Type: config.MongoDBAuthLoginPassword, Username: "root", Password: "secret"},
+			},
+			want: map[string]interface{}{
+				"instance_type": map[string]interface{}{
+					"instance": "standalone",
+					"host":     "mongodb.brewctl-data.svc.cluster.local",
+					"port":     27017,
+					"tls":      false,
+				},
+				"database": "breweries_db",
+				"auth_type": map[string]interface{}{
+					"authorization": "login/password",
+					"username":      "root",
+					"password":      "secret",
+				},
+			},
+		},
+		{
+			name: "external standalone over TLS",
+			destination: config.MongoDBDestination{
+				InstanceType: config.MongoDBStandalone,
+				Host:         "mongo.example.com",
+				TLS:          true,
+				Auth:         config.MongoDBAuth{Type: config.MongoDBAuthNone, Username: "ignored"},
+			},
+			want: map[string]interface{}{
+				"instance_type": map[string]interface{}{
+					"instance": "standalone",
+					"host":     "mongo.example.com",
+					"port":     27017,
+					"tls":      true,
+				},
+				"database":  "breweries_db",
+				"auth_type": map[string]interface{}{"authorization": "none"},
+			},
+		},
+		{
+			name: "replica set members of the StatefulSet",
+			destination: config.MongoDBDestination{
+				InstanceType: config.MongoDBReplica,
+				ReplicaSet:   "rs0",
+			},
+			replicas: 3,
+			want: map[string]interface{}{
+				"instance_type": map[string]interface{}{
+					"instance": "replica",
+					"server_addresses": "mongodb-0.mongodb-headless.brewctl-data.svc.cluster.local:27017," +
+						"mongodb-1.mongodb-headless.brewctl-data.svc.cluster.local:27017," +
+						"mongodb-2.mongodb-headless.brewctl-data.svc.cluster.local:27017",
+					"replica_set": "rs0",
+				},
+				"database":  "breweries_db",
+				"auth_type": map[string]interface{}{"authorization": "none"},
+			},
+		},
+		{
+			name: "replica set with explicit addresses",
+			destination: config.MongoDBDestination{
+				InstanceType:    config.MongoDBReplica,
+				ServerAddresses: "db1:27017,db2:27017",
+				ReplicaSet:      "prod",
+				Auth:            config.MongoDBAuth{Type: config.MongoDBAuthNone},
+			},
+			want: map[string]interface{}{
+				"instance_type": map[string]interface{}{
+					"instance":         "replica",
+					"server_addresses": "db1:27017,db2:27017",
+					"replica_set":      "prod",
+				},
+				"database":  "breweries_db",
+				"auth_type": map[string]interface{}{"authorization": "none"},
+			},
+		},
+		{
+			name: "atlas",
+			destination: config.MongoDBDestination{
+				InstanceType: config.MongoDBAtlas,
+				ClusterURL:   "cluster0.abcde.mongodb.net",
+				Auth:         config.MongoDBAuth{Type: config.MongoDBAuthLoginPassword, Username: "airbyte", Password: "secret"},
+			},
+			want: map[string]interface{}{
+				"instance_type": map[string]interface{}{
+					"instance":    "atlas",
+					"cluster_url": "cluster0.abcde.mongodb.net",
+				},
+				"database": "breweries_db",
+				"auth_type": map[string]interface{}{
+					"authorization": "login/password",
+					"username":      "airbyte",
+					"password":      "secret",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.Destination = tt.destination
+			if tt.replicas > 0 {
+				cfg.Replicas = tt.replicas
+			}
+			require.NoError(t, cfg.Validate())
+			got := MongoDBDestinationConfig(cfg)
+			assert.Equal(t, tt.want, got)
+			assertMatchesSpec(t, loadSpec(t, "destination_mongodb_spec.json"), got)
+		})
+	}
+}
+
+// specSchema é o subconjunto do JSON Schema usado pelas specs dos conectores
+type specSchema struct {
+	Type       string                `json:"type"`
+	Const      interface{}           `json:"const"`
+	Required   []string              `json:"required"`
+	Properties map[string]specSchema `json:"properties"`
+	OneOf      []specSchema          `json:"oneOf"`
+}
+
+func loadSpec(t *testing.T, name string) specSchema {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	var spec specSchema
+	require.NoError(t, json.Unmarshal(data, &spec))
+	return spec
+}
+
+// assertMatchesSpec valida a configuração como o conector, após passar pelo
+// JSON enviado à API: oneOf escolhido pela constante, campos obrigatórios,
+// tipos e nenhum campo fora da opção escolhida
+func assertMatchesSpec(t *testing.T, spec specSchema, config map[string]interface{}) {
+	t.Helper()
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	for _, problem := range specProblems(spec, decoded, "$") {
+		assert.Fail(t, problem)
+	}
+}
+
+func specProblems(spec specSchema, value interface{}, path string) []string {
+	if len(spec.OneOf) > 0 {
+		var problems []string
+		for _, option := range spec.OneOf {
+			optionProblems := specProblems(option, value, path)
+			if len(optionProblems) == 0 {
+				return nil
+			}
+			problems = append(problems, optionProblems...)
+		}
+		return append([]string{fmt.Sprintf("%s matches none of the oneOf options", path)}, problems...)
+	}
+	if spec.Const != nil && spec.Const != value {
+		return []string{fmt.Sprintf("%s: %v is not %v", path, value, spec.Const)}
+	}
+
+	switch spec.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{fmt.Sprintf("%s: %v is not a string", path, value)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: %v is not a boolean", path, value)}
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			return []string{fmt.Sprintf("%s: %v is not an integer", path, value)}
+		}
+	}
+	if spec.Properties == nil {
+		return nil
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: %v is not an object", path, value)}
+	}
+	var problems []string
+	for _, field := range spec.Required {
+		if _, ok := object[field]; !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s is required", path, field))
+		}
+	}
+	for field, fieldValue := range object {
+		fieldSpec, ok := spec.Properties[field]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s is not in the connector spec", path, field))
+			continue
+		}
+		problems = append(problems, specProblems(fieldSpec, fieldValue, path+"."+field)...)
+	}
+	return problems
+}
+
+func TestSpecProblemsRejectsUnsupportedOptions(t *testing.T) {
+	spec := loadSpec(t, "destination_mongodb_spec.json")
+	valid := map[string]interface{}{
+		"instance_type": map[string]interface{}{"instance": "standalone", "host": "db", "port": 27017.0},
+		"database":      "breweries_db",
+		"auth_type":     map[string]interface{}{"authorization": "none"},
+	}
+	assert.Empty(t, specProblems(spec, valid, "$"))
+
+	x509 := map[string]interface{}{
+		"instance_type": valid["instance_type"],
+		"database":      "breweries_db",
+		"auth_type":     map[string]interface{}{"authorization": "x509", "username": "CN=airbyte"},
+	}
+	assert.NotEmpty(t, specProblems(spec, x509, "$"))
+
+	replicaTLS := map[string]interface{}{
+		"instance_type": map[string]interface{}{"instance": "replica", "server_addresses": "db:27017", "tls": true},
+		"database":      "breweries_db",
+		"auth_type":     valid["auth_type"],
+	}
+	assert.NotEmpty(t, specProblems(spec, replicaTLS, "$"))
+}
@@ -1,48 +1,58 @@
 package airbyte
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"time"
+
+	"brewctl/internal/config"
+	"brewctl/internal/kube"
+	"brewctl/internal/kube/helm"
 )
 
-func Deploy() error {
-	fmt.Println("📦 Deploying Airbyte...")
+// NodePort expõe a API do Airbyte no nó; o Kind a mapeia para localhost:8000
+const NodePort = 30800
 
-	// Add Airbyte Helm repo
-	cmd := exec.Command("helm", "repo", "add", "airbyte", "https://airbytehq.github.io/helm-charts")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add airbyte repo: %v", err)
+// Chart é o chart do Airbyte com a versão fixada pelo brewctl
+var Chart = helm.Chart{
+	Repo:    "airbyte",
+	RepoURL: "https://airbytehq.github.io/helm-charts",
+	Name:    "airbyte",
+	Version: "1.1.0",
+}
+
+// Release descreve o release Helm do Airbyte. O values file ajusta o chart
+// para o cluster local; os Values expõem a API no NodePort mapeado pelo Kind e
+// prevalecem sobre ele.
+func Release(cfg config.AirbyteConfig) helm.Release {
+	return helm.Release{
+		Name:        "airbyte",
+		Namespace:   cfg.Namespace,
+		Chart:       Chart,
+		ValuesFiles: helm.OptionalValuesFiles(cfg.ValuesFile, config.DefaultAirbyteValuesFile),
+		Values: map[string]interface{}{
+			"global.service.type":          "NodePort",
+			"server.service.nodePorts.api": NodePort,
+		},
+		Timeout: 15 * time.Minute,
 	}
+}
 
-	// Update Helm repos
-	cmd = exec.Command("helm", "repo", "update")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to update helm repos: %v", err)
+func Deploy(cfg config.AirbyteConfig) error {
+	fmt.Println("📦 Deploying Airbyte...")
+
+	release := Release(cfg)
+	ctx := context.Background()
+
+	client, err := kube.NewClient("")
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureNamespace(ctx, release.Namespace); err != nil {
+		return err
 	}
 
-	// Deploy Airbyte with optimized settings
-	cmd = exec.Command("helm", "upgrade", "--install", "airbyte", "airbyte/airbyte",
-		"--namespace", "default",
-		"--set", "global.service.type=NodePort",
-		"--set", "server.service.nodePorts.api=8000",
-		"--set", "worker.enabled=true",
-		"--set", "bootloader.enabled=true",
-		"--set", "ingress.enabled=false",
-		"--set", "resources.server.requests.memory=1Gi",
-		"--set", "resources.server.requests.cpu=500m",
-		"--wait",
-		"--timeout", "15m",
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	if err := helm.NewManager().UpgradeInstall(ctx, release); err != nil {
 		return fmt.Errorf("failed to deploy Airbyte: %v", err)
 	}
 
@@ -50,10 +60,7 @@ func Deploy() error {
 	fmt.Println("⏳ Waiting for Airbyte to be ready...")
 
 	// Wait for Airbyte pods to be ready
-	cmd = exec.Command("kubectl", "wait", "--for=condition=Ready", "pod", "-l", "app.kubernetes.io/name=airbyte", "--timeout=600s")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := client.WaitForPodsReady(ctx, release.Namespace, "app.kubernetes.io/name=airbyte", 10*time.Minute); err != nil {
 		fmt.Printf("⚠️ Some pods took longer than expected, continuing anyway...\n")
 	}
 
@@ -63,3 +70,30 @@ func Deploy() error {
 	fmt.Println("✅ Airbyte deployed successfully")
 	return nil
 }
+
+// Teardown desinstala o Airbyte; sem keepData também apaga os volumes do
+// banco interno e do MinIO, que o chart cria via StatefulSet e o helm não remove
+func Teardown(cfg config.AirbyteConfig, keepData bool) error {
+	fmt.Println("🗑️ Removing Airbyte...")
+
+	release := Release(cfg)
+	ctx := context.Background()
+
+	if err := helm.NewManager().Uninstall(ctx, release.Name, release.Namespace); err != nil {
+		return err
+	}
+
+	if keepData {
+		fmt.Println("ℹ️ Keeping Airbyte volumes")
+		return nil
+	}
+
+	client, err := kube.NewClient("")
+	if err != nil {
+		return err
+	}
+	if _, err := client.DeletePVCs(ctx, release.Namespace, "", release.Name+"-"); err != nil {
+		return fmt.Errorf("failed to delete Airbyte volumes: %v", err)
+	}
+	return nil
+}
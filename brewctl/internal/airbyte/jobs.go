@@ -0,0 +1,237 @@
+package airbyte
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Job representa um job de sincronização do Airbyte e suas tentativas
+type Job struct {
+	ID           int64
+	ConfigType   string
+	ConnectionID string
+	Status       string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Attempts     []Attempt
+
+	// rowsSynced é o total informado pela API pública, que não expõe tentativas
+	rowsSynced int64
+}
+
+// Attempt representa uma tentativa de execução de um job
+type Attempt struct {
+	Number        int
+	Status        string
+	CreatedAt     time.Time
+	EndedAt       time.Time
+	RecordsSynced int64
+	BytesSynced   int64
+	Failures      []Failure
+	LogLines      []string
+}
+
+// Failure descreve o motivo de falha de uma tentativa
+type Failure struct {
+	Origin  string
+	Type    string
+	Message string
+}
+
+// Finished indica se o job chegou a um estado terminal
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case "succeeded", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+// Duration retorna a duração do job (até agora, se ainda estiver rodando)
+func (j *Job) Duration() time.Duration {
+	end := j.UpdatedAt
+	if !j.Finished() {
+		end = time.Now()
+	}
+	if j.CreatedAt.IsZero() || end.Before(j.CreatedAt) {
+		return 0
+	}
+	return end.Sub(j.CreatedAt)
+}
+
+// RecordsSynced soma os registros sincronizados em todas as tentativas
+func (j *Job) RecordsSynced() int64 {
+	if len(j.Attempts) == 0 {
+		return j.rowsSynced
+	}
+	var total int64
+	for _, a := range j.Attempts {
+		total += a.RecordsSynced
+	}
+	return total
+}
+
+// FailureReason retorna o motivo de falha da última tentativa que falhou
+func (j *Job) FailureReason() string {
+	for i := len(j.Attempts) - 1; i >= 0; i-- {
+		if len(j.Attempts[i].Failures) > 0 {
+			return j.Attempts[i].Failures[0].String()
+		}
+	}
+	return ""
+}
+
+// Duration retorna a duração da tentativa
+func (a *Attempt) Duration() time.Duration {
+	end := a.EndedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if a.CreatedAt.IsZero() || end.Before(a.CreatedAt) {
+		return 0
+	}
+	return end.Sub(a.CreatedAt)
+}
+
+func (f Failure) String() string {
+	var parts []string
+	if f.Origin != "" {
+		parts = append(parts, f.Origin)
+	}
+	if f.Type != "" {
+		parts = append(parts, f.Type)
+	}
+	prefix := strings.Join(parts, "/")
+	if prefix == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("[%s] %s", prefix, f.Message)
+}
+
+type jobPayload struct {
+	ID         int64  `json:"id"`
+	ConfigType string `json:"configType"`
+	ConfigID   string `json:"configId"`
+	Status     string `json:"status"`
+	CreatedAt  int64  `json:"createdAt"`
+	UpdatedAt  int64  `json:"updatedAt"`
+}
+
+type attemptPayload struct {
+	ID             int    `json:"id"`
+	Status         string `json:"status"`
+	CreatedAt      int64  `json:"createdAt"`
+	EndedAt        int64  `json:"endedAt"`
+	BytesSynced    int64  `json:"bytesSynced"`
+	RecordsSynced  int64  `json:"recordsSynced"`
+	FailureSummary *struct {
+		Failures []struct {
+			FailureOrigin   string `json:"failureOrigin"`
+			FailureType     string `json:"failureType"`
+			ExternalMessage string `json:"externalMessage"`
+			InternalMessage string `json:"internalMessage"`
+		} `json:"failures"`
+	} `json:"failureSummary"`
+}
+
+func (p jobPayload) toJob() Job {
+	return Job{
+		ID:           p.ID,
+		ConfigType:   p.ConfigType,
+		ConnectionID: p.ConfigID,
+		Status:       p.Status,
+		CreatedAt:    unixTime(p.CreatedAt),
+		UpdatedAt:    unixTime(p.UpdatedAt),
+	}
+}
+
+func (p attemptPayload) toAttempt() Attempt {
+	attempt := Attempt{
+		Number:        p.ID,
+		Status:        p.Status,
+		CreatedAt:     unixTime(p.CreatedAt),
+		EndedAt:       unixTime(p.EndedAt),
+		RecordsSynced: p.RecordsSynced,
+		BytesSynced:   p.BytesSynced,
+	}
+	if p.FailureSummary != nil {
+		for _, f := range p.FailureSummary.Failures {
+			msg := f.ExternalMessage
+			if msg == "" {
+				msg = f.InternalMessage
+			}
+			attempt.Failures = append(attempt.Failures, Failure{Origin: f.FailureOrigin, Type: f.FailureType, Message: msg})
+		}
+	}
+	return attempt
+}
+
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// StreamJobLogs escreve as linhas de log de um job em w. Com follow, continua
+// consultando o job a cada interval e escreve apenas as linhas novas até que
+// ele termine.
+func (c *AirbyteClient) StreamJobLogs(jobID int64, attempt int, follow bool, interval time.Duration, w io.Writer) (*Job, error) {
+	api, err := c.logsAPI()
+	if err != nil {
+		return nil, err
+	}
+	if api.Name() == PublicAPI {
+		fmt.Fprintln(w, "ℹ️ The Airbyte public API does not expose attempt logs; showing job status only")
+	}
+
+	printed := map[int]int{}
+
+	for {
+		job, err := api.GetJob(jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range job.Attempts {
+			if attempt >= 0 && a.Number != attempt {
+				continue
+			}
+			if printed[a.Number] > len(a.LogLines) {
+				printed[a.Number] = 0
+			}
+			if printed[a.Number] == 0 && len(a.LogLines) > 0 {
+				fmt.Fprintf(w, "──── attempt %d (%s) ────\n", a.Number, a.Status)
+			}
+			for _, line := range a.LogLines[printed[a.Number]:] {
+				fmt.Fprintln(w, line)
+			}
+			printed[a.Number] = len(a.LogLines)
+		}
+
+		if !follow || job.Finished() {
+			return job, nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// logsAPI retorna a API usada para ler tentativas e logs: a API pública não os
+// expõe, então a Config API é usada sempre que o servidor ainda a responder
+func (c *AirbyteClient) logsAPI() (AirbyteAPI, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+	if api.Name() != PublicAPI {
+		return api, nil
+	}
+
+	if status, err := c.probe(legacyHealthEndpoint); err == nil && status == http.StatusOK {
+		return &configAPI{c: c}, nil
+	}
+	return api, nil
+}
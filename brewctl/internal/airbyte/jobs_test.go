@@ -0,0 +1,222 @@
+package airbyte
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobPayloadToJob(t *testing.T) {
+	job := jobPayload{
+		ID:         7,
+		ConfigType: "sync",
+		ConfigID:   "conn-1",
+		Status:     "running",
+		CreatedAt:  1714557600,
+	}.toJob()
+
+	assert.Equal(t, int64(7), job.ID)
+	assert.Equal(t, "sync", job.ConfigType)
+	assert.Equal(t, "conn-1", job.ConnectionID)
+	assert.Equal(t, time.Unix(1714557600, 0), job.CreatedAt)
+	assert.True(t, job.UpdatedAt.IsZero())
+	assert.False(t, job.Finished())
+}
+
+func TestAttemptPayloadToAttempt(t *testing.T) {
+	var payload attemptPayload
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1,
+		"status": "failed",
+		"createdAt": 1714557600,
+		"endedAt": 1714557660,
+		"recordsSynced": 120,
+		"bytesSynced": 4096,
+		"failureSummary": {"failures": [
+			{"failureOrigin": "source", "failureType": "system_error", "externalMessage": "Connection refused"},
+			{"failureOrigin": "destination", "internalMessage": "internal only"}
+		]}
+	}`), &payload))
+
+	attempt := payload.toAttempt()
+	assert.Equal(t, 1, attempt.Number)
+	assert.Equal(t, int64(120), attempt.RecordsSynced)
+	assert.Equal(t, int64(4096), attempt.BytesSynced)
+	assert.Equal(t, time.Minute, attempt.Duration())
+	assert.Equal(t, []Failure{
+		{Origin: "source", Type: "system_error", Message: "Connection refused"},
+		{Origin: "destination", Message: "internal only"},
+	}, attempt.Failures)
+}
+
+func TestJobDuration(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		job  Job
+		min  time.Duration
+		max  time.Duration
+	}{
+		{"finished", Job{Status: "succeeded", CreatedAt: created, UpdatedAt: created.Add(5 * time.Minute)}, 5 * time.Minute, 5 * time.Minute},
+		{"no start time", Job{Status: "succeeded", UpdatedAt: created}, 0, 0},
+		{"clock skew", Job{Status: "failed", CreatedAt: created, UpdatedAt: created.Add(-time.Second)}, 0, 0},
+		{"running counts until now", Job{Status: "running", CreatedAt: time.Now().Add(-time.Hour), UpdatedAt: created}, time.Hour, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.job.Duration()
+			assert.GreaterOrEqual(t, d, tt.min)
+			assert.LessOrEqual(t, d, tt.max)
+		})
+	}
+}
+
+func TestJobFailureReason(t *testing.T) {
+	job := Job{Attempts: []Attempt{
+		{Number: 0, Failures: []Failure{{Origin: "source", Type: "config_error", Message: "bad url"}}},
+		{Number: 1, Failures: []Failure{{Origin: "destination", Message: "timeout"}, {Message: "ignored"}}},
+		{Number: 2},
+	}}
+	assert.Equal(t, "[destination] timeout", job.FailureReason())
+
+	assert.Equal(t, "", (&Job{Attempts: []Attempt{{Number: 0}}}).FailureReason())
+	assert.Equal(t, "no origin", Failure{Message: "no origin"}.String())
+}
+
+// jobServer responde ao /api/v1/jobs/get com as respostas em sequência,
+// repetindo a última
+func jobServer(t *testing.T, responses ...string) (*AirbyteClient, *int) {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/jobs/get", r.URL.Path)
+		var req struct {
+			ID int64 `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, int64(42), req.ID)
+
+		i := calls
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		calls++
+		w.Write([]byte(responses[i]))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewAirbyteClient(server.URL)
+	client.Quiet = true
+	client.API = &configAPI{c: client}
+	return client, &calls
+}
+
+func TestStreamJobLogs(t *testing.T) {
+	client, calls := jobServer(t, `{
+		"job": {"id": 42, "status": "failed"},
+		"attempts": [
+			{"attempt": {"id": 0, "status": "failed"}, "logs": {"logLines": ["a0-1", "a0-2"]}},
+			{"attempt": {"id": 1, "status": "failed"}, "logs": {"logLines": ["a1-1"]}}
+		]
+	}`)
+
+	var out bytes.Buffer
+	job, err := client.StreamJobLogs(42, -1, false, time.Millisecond, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", job.Status)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, "──── attempt 0 (failed) ────\na0-1\na0-2\n──── attempt 1 (failed) ────\na1-1\n", out.String())
+}
+
+func TestStreamJobLogsFiltersAttempt(t *testing.T) {
+	client, _ := jobServer(t, `{
+		"job": {"id": 42, "status": "succeeded"},
+		"attempts": [
+			{"attempt": {"id": 0, "status": "failed"}, "logs": {"logLines": ["a0-1"]}},
+			{"attempt": {"id": 1, "status": "succeeded"}, "logs": {"logLines": ["a1-1"]}}
+		]
+	}`)
+
+	var out bytes.Buffer
+	_, err := client.StreamJobLogs(42, 1, false, time.Millisecond, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "──── attempt 1 (succeeded) ────\na1-1\n", out.String())
+}
+
+func TestStreamJobLogsFollow(t *testing.T) {
+	client, calls := jobServer(t,
+		`{"job": {"id": 42, "status": "running"}, "attempts": [
+			{"attempt": {"id": 0, "status": "running"}, "logs": {"logLines": ["l1"]}}
+		]}`,
+		`{"job": {"id": 42, "status": "running"}, "attempts": [
+			{"attempt": {"id": 0, "status": "running"}, "logs": {"logLines": ["l1", "l2"]}}
+		]}`,
+		// O log da tentativa foi rotacionado: volta a ser impresso do início
+		`{"job": {"id": 42, "status": "running"}, "attempts": [
+			{"attempt": {"id": 0, "status": "running"}, "logs": {"logLines": ["r1"]}}
+		]}`,
+		`{"job": {"id": 42, "status": "succeeded"}, "attempts": [
+			{"attempt": {"id": 0, "status": "succeeded"}, "logs": {"logLines": ["r1", "r2"]}}
+		]}`,
+	)
+
+	var out bytes.Buffer
+	job, err := client.StreamJobLogs(42, -1, true, time.Millisecond, &out)
+	require.NoError(t, err)
+	assert.True(t, job.Finished())
+	assert.Equal(t, 4, *calls)
+	assert.Equal(t,
+		"──── attempt 0 (running) ────\nl1\nl2\n──── attempt 0 (running) ────\nr1\nr2\n",
+		out.String())
+}
+
+func TestStreamJobLogsWithPublicAPI(t *testing.T) {
+	tests := []struct {
+		name         string
+		legacyStatus int
+		want         string
+	}{
+		{"legacy API available", http.StatusOK, "──── attempt 0 (succeeded) ────\nl1\n"},
+		{"public API only", http.StatusNotFound, "ℹ️ The Airbyte public API does not expose attempt logs; showing job status only\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case legacyHealthEndpoint:
+					w.WriteHeader(tt.legacyStatus)
+				case "/api/v1/jobs/get":
+					w.Write([]byte(`{"job": {"id": 42, "status": "succeeded"}, "attempts": [
+						{"attempt": {"id": 0, "status": "succeeded"}, "logs": {"logLines": ["l1"]}}
+					]}`))
+				case "/api/public/v1/jobs/42":
+					w.Write([]byte(`{"jobId": 42, "status": "succeeded", "rowsSynced": 10}`))
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewAirbyteClient(server.URL)
+			client.Quiet = true
+			client.API = &publicAPI{c: client}
+
+			var out bytes.Buffer
+			job, err := client.StreamJobLogs(42, -1, false, time.Millisecond, &out)
+			require.NoError(t, err)
+			assert.Equal(t, "succeeded", job.Status)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package airbyte
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// publicAPI implementa AirbyteAPI sobre a API REST pública (/api/public/v1)
+type publicAPI struct {
+	c *AirbyteClient
+}
+
+func (a *publicAPI) Name() string {
+	return PublicAPI
+}
+
+// GetFirstWorkspace obtém o primeiro workspace disponível
+func (a *publicAPI) GetFirstWorkspace() (string, error) {
+	var result struct {
+		Data []struct {
+			WorkspaceID string `json:"workspaceId"`
+			Name        string `json:"name"`
+		} `json:"data"`
+	}
+	if err := a.doJSON("GET", "/api/public/v1/workspaces?limit=1", nil, &result); err != nil {
+		return "", fmt.Errorf("failed to list workspaces: %v", err)
+	}
+
+	if len(result.Data) == 0 {
+		return "", fmt.Errorf("no workspaces found")
+	}
+
+	fmt.Printf("✅ Found workspace: %s (%s)\n", result.Data[0].Name, result.Data[0].WorkspaceID)
+	return result.Data[0].WorkspaceID, nil
+}
+
+// CreateSource cria uma nova source no Airbyte
+func (a *publicAPI) CreateSource(workspaceID, name, sourceDefinitionID string, config map[string]interface{}) (string, error) {
+	sourceReq := map[string]interface{}{
+		"workspaceId":   workspaceID,
+		"name":          name,
+		"definitionId":  sourceDefinitionID,
+		"configuration": config,
+	}
+
+	var result struct {
+		SourceID string `json:"sourceId"`
+	}
+	if err := a.doJSON("POST", "/api/public/v1/sources", sourceReq, &result); err != nil {
+		return "", fmt.Errorf("failed to create source: %v", err)
+	}
+
+	fmt.Printf("✅ Created source: %s (%s)\n", name, result.SourceID)
+	return result.SourceID, nil
+}
+
+// CreateDestination cria um novo destination no Airbyte
+func (a *publicAPI) CreateDestination(workspaceID, name, destinationDefinitionID string, config map[string]interface{}) (string, error) {
+	destinationReq := map[string]interface{}{
+		"workspaceId":   workspaceID,
+		"name":          name,
+		"definitionId":  destinationDefinitionID,
+		"configuration": config,
+	}
+
+	var result struct {
+		DestinationID string `json:"destinationId"`
+	}
+	if err := a.doJSON("POST", "/api/public/v1/destinations", destinationReq, &result); err != nil {
+		return "", fmt.Errorf("failed to create destination: %v", err)
+	}
+
+	fmt.Printf("✅ Created destination: %s (%s)\n", name, result.DestinationID)
+	return result.DestinationID, nil
+}
+
+// CreateConnection cria uma conexão entre source e destination
+func (a *publicAPI) CreateConnection(sourceID, destinationID, name string) (string, error) {
+	connectionReq := map[string]interface{}{
+		"name":          name,
+		"sourceId":      sourceID,
+		"destinationId": destinationID,
+		"schedule": map[string]interface{}{
+			"scheduleType": "manual",
+		},
+		"configurations": map[string]interface{}{
+			"streams": []map[string]interface{}{
+				{
+					"name":       "breweries",
+					"syncMode":   "full_refresh_append",
+					"primaryKey": [][]string{{"id"}},
+				},
+			},
+		},
+		"status": "active",
+	}
+
+	var result struct {
+		ConnectionID string `json:"connectionId"`
+	}
+	if err := a.doJSON("POST", "/api/public/v1/connections", connectionReq, &result); err != nil {
+		return "", fmt.Errorf("failed to create connection: %v", err)
+	}
+
+	fmt.Printf("✅ Created connection: %s (%s)\n", name, result.ConnectionID)
+	return result.ConnectionID, nil
+}
+
+// TestConnection testa uma conexão existente
+func (a *publicAPI) TestConnection(connectionID string) error {
+	if err := a.doJSON("GET", "/api/public/v1/connections/"+url.PathEscape(connectionID), nil, nil); err != nil {
+		return fmt.Errorf("failed to test connection: %v", err)
+	}
+
+	fmt.Printf("✅ Connection %s is valid\n", connectionID)
+	return nil
+}
+
+// SyncConnection inicia uma sincronização manual
+func (a *publicAPI) SyncConnection(connectionID string) error {
+	syncReq := map[string]interface{}{
+		"connectionId": connectionID,
+		"jobType":      "sync",
+	}
+
+	var result publicJob
+	if err := a.doJSON("POST", "/api/public/v1/jobs", syncReq, &result); err != nil {
+		return fmt.Errorf("failed to start sync: %v", err)
+	}
+
+	fmt.Printf("✅ Started sync for connection: %s (job %d)\n", connectionID, result.JobID)
+	return nil
+}
+
+// ListJobs lista os jobs de sincronização de uma conexão, do mais recente para o mais antigo
+func (a *publicAPI) ListJobs(connectionID string, limit int) ([]Job, error) {
+	query := url.Values{}
+	query.Set("connectionId", connectionID)
+	query.Set("limit", fmt.Sprint(limit))
+	query.Set("orderBy", "createdAt|DESC")
+
+	var result struct {
+		Data []publicJob `json:"data"`
+	}
+	if err := a.doJSON("GET", "/api/public/v1/jobs?"+query.Encode(), nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	jobs := make([]Job, 0, len(result.Data))
+	for _, j := range result.Data {
+		jobs = append(jobs, j.toJob())
+	}
+	return jobs, nil
+}
+
+// GetJob obtém um job. A API pública não expõe tentativas nem logs; o
+// StreamJobLogs os busca na Config API quando ela está disponível.
+func (a *publicAPI) GetJob(jobID int64) (*Job, error) {
+	var result publicJob
+	if err := a.doJSON("GET", fmt.Sprintf("/api/public/v1/jobs/%d", jobID), nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+
+	job := result.toJob()
+	return &job, nil
+}
+
+// ListResources lista os recursos do tipo informado no workspace
+func (a *publicAPI) ListResources(kind ResourceKind, workspaceID string) ([]Resource, error) {
+	query := url.Values{}
+	query.Set("workspaceIds", workspaceID)
+	query.Set("limit", "100")
+
+	var result struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := a.doJSON("GET", fmt.Sprintf("/api/public/v1/%s?%s", kind, query.Encode()), nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", kind, err)
+	}
+	return toResources(kind, result.Data), nil
+}
+
+// DeleteResource remove um recurso pelo ID
+func (a *publicAPI) DeleteResource(kind ResourceKind, id string) error {
+	if err := a.doJSON("DELETE", fmt.Sprintf("/api/public/v1/%s/%s", kind, url.PathEscape(id)), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete %s %s: %v", kind, id, err)
+	}
+	return nil
+}
+
+// doJSON executa a requisição, aceita qualquer status 2xx e decodifica a resposta em out
+func (a *publicAPI) doJSON(method, endpoint string, body, out interface{}) error {
+	resp, err := a.c.makeRequest(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s returned status %d: %s", method, endpoint, resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response failed: %v", endpoint, err)
+	}
+	return nil
+}
+
+type publicJob struct {
+	JobID         int64  `json:"jobId"`
+	Status        string `json:"status"`
+	JobType       string `json:"jobType"`
+	ConnectionID  string `json:"connectionId"`
+	StartTime     string `json:"startTime"`
+	LastUpdatedAt string `json:"lastUpdatedAt"`
+	BytesSynced   int64  `json:"bytesSynced"`
+	RowsSynced    int64  `json:"rowsSynced"`
+}
+
+func (p publicJob) toJob() Job {
+	return Job{
+		ID:           p.JobID,
+		ConfigType:   p.JobType,
+		ConnectionID: p.ConnectionID,
+		Status:       p.Status,
+		CreatedAt:    parseTime(p.StartTime),
+		UpdatedAt:    parseTime(p.LastUpdatedAt),
+		rowsSynced:   p.RowsSynced,
+	}
+}
+
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
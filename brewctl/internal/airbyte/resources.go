@@ -0,0 +1,105 @@
+package airbyte
+
+import "fmt"
+
+// ResourceKind identifica um tipo de recurso do Airbyte
+type ResourceKind string
+
+const (
+	ResourceSources      ResourceKind = "sources"
+	ResourceDestinations ResourceKind = "destinations"
+	ResourceConnections  ResourceKind = "connections"
+)
+
+// idField retorna o nome do campo de ID do recurso nas duas APIs
+func (k ResourceKind) idField() string {
+	switch k {
+	case ResourceSources:
+		return "sourceId"
+	case ResourceDestinations:
+		return "destinationId"
+	}
+	return "connectionId"
+}
+
+// Resource é uma source, destination ou connection do Airbyte
+type Resource struct {
+	Kind ResourceKind
+	ID   string
+	Name string
+}
+
+// managedResources são os nomes criados por SetupConnections, por tipo
+var managedResources = map[ResourceKind][]string{
+	ResourceConnections:  {MongoDBConnectionName, FileConnectionName, PostgresConnectionName},
+	ResourceDestinations: {MongoDBDestinationName, FileJSONDestinationName, FileCSVDestinationName, PostgresDestinationName},
+	ResourceSources:      {BrewerySourceName},
+}
+
+// ListResources lista os recursos do tipo informado no workspace
+func (c *AirbyteClient) ListResources(kind ResourceKind, workspaceID string) ([]Resource, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+	return api.ListResources(kind, workspaceID)
+}
+
+// DeleteResource remove um recurso pelo ID
+func (c *AirbyteClient) DeleteResource(kind ResourceKind, id string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.DeleteResource(kind, id)
+}
+
+// TeardownConnections remove as connections, destinations e a source criados
+// por SetupConnections, identificados pelo nome. Connections são removidas
+// primeiro para que nenhuma fique apontando para um recurso inexistente.
+func (c *AirbyteClient) TeardownConnections() ([]Resource, error) {
+	workspaceID, err := c.GetFirstWorkspace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %v", err)
+	}
+
+	var removed []Resource
+	for _, kind := range []ResourceKind{ResourceConnections, ResourceDestinations, ResourceSources} {
+		resources, err := c.ListResources(kind, workspaceID)
+		if err != nil {
+			return removed, err
+		}
+
+		for _, r := range resources {
+			if !contains(managedResources[kind], r.Name) {
+				continue
+			}
+			if err := c.DeleteResource(kind, r.ID); err != nil {
+				return removed, err
+			}
+			fmt.Printf("🗑️ Deleted %s %s (%s)\n", kind, r.Name, r.ID)
+			removed = append(removed, r)
+		}
+	}
+	return removed, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// toResources converte os objetos retornados pelas APIs em Resource
+func toResources(kind ResourceKind, items []map[string]interface{}) []Resource {
+	resources := make([]Resource, 0, len(items))
+	for _, item := range items {
+		id, _ := item[kind.idField()].(string)
+		name, _ := item["name"].(string)
+		resources = append(resources, Resource{Kind: kind, ID: id, Name: name})
+	}
+	return resources
+}
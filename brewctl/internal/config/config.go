@@ -0,0 +1,628 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config reúne as configurações do brewctl. Os valores vêm, em ordem de
+// precedência crescente, dos defaults, do arquivo de configuração e das
+// variáveis de ambiente BREWCTL_*.
+type Config struct {
+	Cluster      ClusterConfig      `yaml:"cluster"`
+	Airbyte      AirbyteConfig      `yaml:"airbyte"`
+	MongoDB      MongoDBConfig      `yaml:"mongodb"`
+	Monitoring   MonitoringConfig   `yaml:"monitoring"`
+	Destinations DestinationsConfig `yaml:"destinations"`
+}
+
+// ClusterConfig define o cluster Kind gerenciado pelo brewctl. Quando
+// ConfigFile existe, a topologia vem dele; caso contrário é gerada a partir de
+// ControlPlanes, Workers e PortMappings.
+type ClusterConfig struct {
+	Name       string `yaml:"name"`
+	ConfigFile string `yaml:"config_file"`
+	// NodeImage fixa a versão do Kubernetes (kindest/node) de todos os nós
+	NodeImage     string        `yaml:"node_image"`
+	ControlPlanes int           `yaml:"control_planes"`
+	Workers       int           `yaml:"workers"`
+	PortMappings  []PortMapping `yaml:"port_mappings"`
+	// NetworkPolicies isola os namespaces do brewctl, aceitando apenas tráfego
+	// entre eles e dos nós (NodePorts)
+	NetworkPolicies bool `yaml:"network_policies"`
+}
+
+// PortMapping expõe uma porta do nó control-plane no host
+type PortMapping struct {
+	ContainerPort int `yaml:"container_port"`
+	HostPort      int `yaml:"host_port"`
+}
+
+// Faixa padrão de NodePorts do Kubernetes, alvo dos port_mappings
+const (
+	MinNodePort = 30000
+	MaxNodePort = 32767
+)
+
+// DefaultKindConfigFile é a configuração do Kind versionada no repositório
+const DefaultKindConfigFile = "deployments/kind-config.yaml"
+
+// Validate verifica a configuração do cluster
+func (c ClusterConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("cluster.name must not be empty")
+	}
+	if c.ControlPlanes < 1 {
+		return fmt.Errorf("cluster.control_planes must be at least 1")
+	}
+	if c.Workers < 0 {
+		return fmt.Errorf("cluster.workers must not be negative")
+	}
+	for _, pm := range c.PortMappings {
+		if pm.ContainerPort < MinNodePort || pm.ContainerPort > MaxNodePort {
+			return fmt.Errorf("cluster.port_mappings: container_port %d is not a NodePort (expected %d-%d)",
+				pm.ContainerPort, MinNodePort, MaxNodePort)
+		}
+	}
+	return nil
+}
+
+// AirbyteConfig define onde o Airbyte é implantado e como acessar a sua API
+type AirbyteConfig struct {
+	Namespace string `yaml:"namespace"`
+	URL       string `yaml:"url"`
+	// ValuesFile é o values do chart; os values do brewctl prevalecem sobre ele
+	ValuesFile string `yaml:"values_file"`
+	// API força a API usada (legacy ou public); auto detecta pelo servidor
+	API  string      `yaml:"api"`
+	Auth AirbyteAuth `yaml:"auth"`
+}
+
+// AirbyteAuth define as credenciais da API do Airbyte. Type aceita none, basic,
+// bearer ou client_credentials; quando vazio, é inferido dos campos preenchidos.
+type AirbyteAuth struct {
+	Type         string `yaml:"type"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	Token        string `yaml:"token"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	TokenURL     string `yaml:"token_url"`
+}
+
+const (
+	APIAuto   = "auto"
+	APILegacy = "legacy"
+	APIPublic = "public"
+)
+
+const (
+	AuthNone              = "none"
+	AuthBasic             = "basic"
+	AuthBearer            = "bearer"
+	AuthClientCredentials = "client_credentials"
+)
+
+// MongoDBConfig define o MongoDB implantado pelo brewctl e como o Airbyte e a
+// CLI se conectam a ele. Namespace e ServiceName são usados tanto pelo deploy
+// quanto pelo destination do Airbyte, para que os dois lados concordem.
+type MongoDBConfig struct {
+	// Mode aceita manifest (StatefulSet do brewctl), helm (chart da Bitnami)
+	// ou external (nada é implantado; apenas a URI é validada)
+	Mode string `yaml:"mode"`
+	// ValuesFile é o values do chart usado no modo helm
+	ValuesFile  string `yaml:"values_file"`
+	Namespace   string `yaml:"namespace"`
+	ServiceName string `yaml:"service_name"`
+	Port        int    `yaml:"port"`
+	Database    string `yaml:"database"`
+	// URI usada pela CLI a partir da máquina local (agregações, status)
+	URI         string             `yaml:"uri"`
+	Destination MongoDBDestination `yaml:"destination"`
+
+	Image string `yaml:"image"`
+	// Replicas aceita 1 (standalone) ou 3 (replica set, necessário para change streams)
+	Replicas       int    `yaml:"replicas"`
+	ReplicaSetName string `yaml:"replica_set_name"`
+	// StorageClass vazio usa a storage class padrão do cluster
+	StorageSize  string           `yaml:"storage_size"`
+	StorageClass string           `yaml:"storage_class"`
+	Resources    MongoDBResources `yaml:"resources"`
+}
+
+// MonitoringConfig define onde Prometheus e Grafana são implantados
+type MonitoringConfig struct {
+	Namespace string `yaml:"namespace"`
+	// Values dos charts; os values do brewctl prevalecem sobre eles
+	PrometheusValuesFile string `yaml:"prometheus_values_file"`
+	GrafanaValuesFile    string `yaml:"grafana_values_file"`
+}
+
+// MongoDBResources define requests e limits do container do MongoDB
+type MongoDBResources struct {
+	CPURequest    string `yaml:"cpu_request"`
+	MemoryRequest string `yaml:"memory_request"`
+	CPULimit      string `yaml:"cpu_limit"`
+	MemoryLimit   string `yaml:"memory_limit"`
+}
+
+// MongoDBDestination define o destination MongoDB criado no Airbyte
+type MongoDBDestination struct {
+	// InstanceType aceita standalone, replica ou atlas
+	InstanceType string `yaml:"instance_type"`
+	// Host do standalone; vazio usa o DNS do service implantado pelo brewctl
+	Host string `yaml:"host"`
+	// ServerAddresses do replica set ("host1:27017,host2:27017"); vazio usa o service
+	ServerAddresses string `yaml:"server_addresses"`
+	ReplicaSet      string `yaml:"replica_set"`
+	ClusterURL      string `yaml:"cluster_url"`
+	// TLS só existe no standalone do conector; o atlas sempre usa TLS
+	TLS  bool        `yaml:"tls"`
+	Auth MongoDBAuth `yaml:"auth"`
+}
+
+// MongoDBAuth define a autenticação do destination. Type aceita none ou
+// login/password, os únicos tipos do conector destination-mongodb.
+type MongoDBAuth struct {
+	Type     string `yaml:"type"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+const (
+	MongoDBStandalone = "standalone"
+	MongoDBReplica    = "replica"
+	MongoDBAtlas      = "atlas"
+
+	MongoDBAuthNone          = "none"
+	MongoDBAuthLoginPassword = "login/password"
+
+	MongoDBModeManifest = "manifest"
+	MongoDBModeHelm     = "helm"
+	MongoDBModeExternal = "external"
+
+	// Values files padrão dos charts, relativos à raiz do repositório
+	DefaultMongoDBValuesFile    = "deployments/mongodb-values.yaml"
+	DefaultAirbyteValuesFile    = "deployments/airbyte-values.yaml"
+	DefaultPrometheusValuesFile = "deployments/prometheus-values.yaml"
+	DefaultGrafanaValuesFile    = "deployments/grafana-values.yaml"
+
+	// MongoDBRootUser é o usuário root criado pelo deploy; a senha fica no Secret
+	MongoDBRootUser = "root"
+)
+
+// DestinationsConfig define os destinations adicionais do Airbyte
+type DestinationsConfig struct {
+	File     FileDestination     `yaml:"file"`
+	Postgres PostgresDestination `yaml:"postgres"`
+}
+
+// FileDestination grava os dados em arquivos locais do Airbyte (sob /local no worker)
+type FileDestination struct {
+	// Format aceita jsonl ou csv
+	Format string `yaml:"format"`
+	Path   string `yaml:"path"`
+}
+
+// PostgresDestination define o destination Postgres
+type PostgresDestination struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Database string `yaml:"database"`
+	Schema   string `yaml:"schema"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// SSLMode aceita disable, allow, prefer, require, verify-ca ou verify-full
+	SSLMode string `yaml:"ssl_mode"`
+}
+
+const (
+	FileFormatJSONL = "jsonl"
+	FileFormatCSV   = "csv"
+)
+
+// Validate verifica a configuração do destination de arquivo
+func (f FileDestination) Validate() error {
+	if f.Format != FileFormatJSONL && f.Format != FileFormatCSV {
+		return fmt.Errorf("destinations.file.format: unknown format %q (expected jsonl or csv)", f.Format)
+	}
+	if !strings.HasPrefix(f.Path, "/local") {
+		return fmt.Errorf("destinations.file.path: %q must be under /local", f.Path)
+	}
+	return nil
+}
+
+// Validate verifica a configuração do destination Postgres
+func (p PostgresDestination) Validate() error {
+	if p.Host == "" || p.Database == "" || p.Username == "" {
+		return fmt.Errorf("destinations.postgres: host, database and username are required")
+	}
+	return nil
+}
+
+// ServiceHost retorna o DNS interno do service do MongoDB no cluster
+func (m MongoDBConfig) ServiceHost() string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", m.ServiceName, m.Namespace)
+}
+
+// HeadlessServiceName retorna o service headless usado pelo StatefulSet
+func (m MongoDBConfig) HeadlessServiceName() string {
+	return m.ServiceName + "-headless"
+}
+
+// CredentialsSecretName retorna o Secret com a credencial root gerada no deploy
+func (m MongoDBConfig) CredentialsSecretName() string {
+	return m.ServiceName + "-credentials"
+}
+
+// MemberHosts retorna o DNS estável de cada membro do StatefulSet
+func (m MongoDBConfig) MemberHosts() []string {
+	hosts := make([]string, m.Replicas)
+	for i := range hosts {
+		hosts[i] = fmt.Sprintf("%s-%d.%s.%s.svc.cluster.local:%d", m.ServiceName, i, m.HeadlessServiceName(), m.Namespace, m.Port)
+	}
+	return hosts
+}
+
+// ConnectionURI retorna a URI da CLI com as credenciais informadas, a menos que
+// a URI configurada já traga as suas
+func (m MongoDBConfig) ConnectionURI(username, password string) (string, error) {
+	if username == "" {
+		return m.URI, nil
+	}
+	u, err := url.Parse(m.URI)
+	if err != nil {
+		return "", fmt.Errorf("mongodb.uri: %v", err)
+	}
+	if u.User != nil {
+		return m.URI, nil
+	}
+	u.User = url.UserPassword(username, password)
+	query := u.Query()
+	if query.Get("authSource") == "" {
+		query.Set("authSource", "admin")
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// DestinationHost retorna o host do standalone usado pelo Airbyte
+func (m MongoDBConfig) DestinationHost() string {
+	if m.Destination.Host != "" {
+		return m.Destination.Host
+	}
+	return m.ServiceHost()
+}
+
+// DestinationServerAddresses retorna os membros do replica set usados pelo Airbyte
+func (m MongoDBConfig) DestinationServerAddresses() string {
+	if m.Destination.ServerAddresses != "" {
+		return m.Destination.ServerAddresses
+	}
+	if m.Replicas > 1 {
+		return strings.Join(m.MemberHosts(), ",")
+	}
+	return fmt.Sprintf("%s:%d", m.ServiceHost(), m.Port)
+}
+
+// Validate verifica a configuração do MongoDB e se o destination aponta para o
+// mesmo service/namespace que o deploy cria
+func (m MongoDBConfig) Validate() error {
+	switch m.Mode {
+	case MongoDBModeManifest, MongoDBModeHelm:
+	case MongoDBModeExternal:
+		if !strings.HasPrefix(m.URI, "mongodb://") && !strings.HasPrefix(m.URI, "mongodb+srv://") {
+			return fmt.Errorf("mongodb.uri: external mode requires a mongodb:// or mongodb+srv:// URI, got %q", m.URI)
+		}
+	default:
+		return fmt.Errorf("mongodb.mode: unknown mode %q (expected manifest, helm or external)", m.Mode)
+	}
+	if m.Namespace == "" || m.ServiceName == "" {
+		return fmt.Errorf("mongodb.namespace and mongodb.service_name must not be empty")
+	}
+	if m.Database == "" {
+		return fmt.Errorf("mongodb.database must not be empty")
+	}
+	if m.Replicas != 1 && m.Replicas != 3 {
+		return fmt.Errorf("mongodb.replicas: %d is not supported (expected 1 or 3)", m.Replicas)
+	}
+
+	dest := m.Destination
+	if m.Mode == MongoDBModeExternal {
+		// Sem deploy não há service no cluster para usar como host padrão
+		if (dest.InstanceType == MongoDBStandalone && dest.Host == "") ||
+			(dest.InstanceType == MongoDBReplica && dest.ServerAddresses == "") {
+			return fmt.Errorf("mongodb.destination: external mode requires host or server_addresses reachable from Airbyte")
+		}
+	}
+
+	switch dest.InstanceType {
+	case MongoDBStandalone:
+		if err := m.checkClusterHost(dest.Host); err != nil {
+			return err
+		}
+	case MongoDBReplica:
+		if dest.TLS {
+			return fmt.Errorf("mongodb.destination: the Airbyte MongoDB destination has no tls option for replica instances")
+		}
+		if dest.ReplicaSet == "" {
+			return fmt.Errorf("mongodb.destination: replica instance requires replica_set")
+		}
+		for _, addr := range strings.Split(dest.ServerAddresses, ",") {
+			host, _, _ := strings.Cut(strings.TrimSpace(addr), ":")
+			if err := m.checkClusterHost(host); err != nil {
+				return err
+			}
+		}
+	case MongoDBAtlas:
+		if dest.TLS {
+			return fmt.Errorf("mongodb.destination: atlas instances always use TLS; remove tls")
+		}
+		if dest.ClusterURL == "" {
+			return fmt.Errorf("mongodb.destination: atlas instance requires cluster_url")
+		}
+	default:
+		return fmt.Errorf("mongodb.destination: unknown instance_type %q (expected standalone, replica or atlas)", dest.InstanceType)
+	}
+
+	switch dest.Auth.Type {
+	case "", MongoDBAuthNone:
+	case MongoDBAuthLoginPassword:
+		if dest.Auth.Username == "" {
+			return fmt.Errorf("mongodb.destination.auth: login/password requires a username")
+		}
+	case "x509":
+		return fmt.Errorf("mongodb.destination.auth: x509 is not supported by the Airbyte MongoDB destination (expected none or login/password)")
+	default:
+		return fmt.Errorf("mongodb.destination.auth: unknown type %q (expected none or login/password)", dest.Auth.Type)
+	}
+	return nil
+}
+
+// checkClusterHost rejeita hosts internos do cluster que não correspondem ao
+// service implantado pelo brewctl
+func (m MongoDBConfig) checkClusterHost(host string) error {
+	if host == "" || !strings.HasSuffix(host, ".svc.cluster.local") {
+		return nil
+	}
+	// Aceita o service, o service headless e o DNS de cada membro do StatefulSet
+	for _, service := range []string{m.ServiceName, m.HeadlessServiceName()} {
+		suffix := service + "." + m.Namespace + ".svc.cluster.local"
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("mongodb.destination: host %s does not match the deployed MongoDB service %s", host, m.ServiceHost())
+}
+
+// Default retorna a configuração usada quando nada é informado
+func Default() *Config {
+	return &Config{
+		Cluster: ClusterConfig{
+			Name:          "brewctl-cluster",
+			ConfigFile:    DefaultKindConfigFile,
+			NodeImage:     "kindest/node:v1.27.3",
+			ControlPlanes: 1,
+			PortMappings: []PortMapping{
+				// container_port é o NodePort de cada serviço
+				{ContainerPort: 30800, HostPort: 8000},
+				{ContainerPort: 32000, HostPort: 3000},
+				{ContainerPort: 30090, HostPort: 9090},
+				{ContainerPort: 30017, HostPort: 27017},
+			},
+		},
+		Airbyte: AirbyteConfig{
+			Namespace:  "brewctl-ingest",
+			URL:        "http://localhost:8000",
+			ValuesFile: DefaultAirbyteValuesFile,
+			API:        APIAuto,
+		},
+		MongoDB: MongoDBConfig{
+			Mode:        MongoDBModeManifest,
+			ValuesFile:  DefaultMongoDBValuesFile,
+			Namespace:   "brewctl-data",
+			ServiceName: "mongodb",
+			Port:        27017,
+			Database:    "breweries_db",
+			URI:         "mongodb://localhost:27017/?directConnection=true",
+			Destination: MongoDBDestination{
+				InstanceType: MongoDBStandalone,
+				Auth:         MongoDBAuth{Type: MongoDBAuthLoginPassword, Username: MongoDBRootUser},
+			},
+			Image:          "mongo:6.0.5",
+			Replicas:       1,
+			ReplicaSetName: "rs0",
+			StorageSize:    "2Gi",
+			Resources: MongoDBResources{
+				CPURequest:    "250m",
+				MemoryRequest: "512Mi",
+				CPULimit:      "1",
+				MemoryLimit:   "1Gi",
+			},
+		},
+		Monitoring: MonitoringConfig{
+			Namespace:            "brewctl-monitoring",
+			PrometheusValuesFile: DefaultPrometheusValuesFile,
+			GrafanaValuesFile:    DefaultGrafanaValuesFile,
+		},
+		Destinations: DestinationsConfig{
+			File: FileDestination{
+				Format: FileFormatJSONL,
+				Path:   "/local/breweries",
+			},
+			Postgres: PostgresDestination{
+				Port:     5432,
+				Database: "breweries",
+				Schema:   "public",
+				SSLMode:  "disable",
+			},
+		},
+	}
+}
+
+// DefaultPath retorna o caminho padrão do arquivo de configuração
+func DefaultPath() string {
+	if path := os.Getenv("BREWCTL_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".brewctl", "config.yaml")
+}
+
+// Load carrega a configuração do arquivo em path (se existir) e aplica as
+// variáveis de ambiente. Com path vazio usa DefaultPath e tolera a ausência do arquivo.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+			}
+		case os.IsNotExist(err) && !explicit:
+		default:
+			return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+		}
+	}
+
+	cfg.applyEnv()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyEnv() {
+	setFromEnv(&c.Cluster.Name, "BREWCTL_CLUSTER_NAME")
+	setFromEnv(&c.Cluster.ConfigFile, "BREWCTL_KIND_CONFIG")
+	setFromEnv(&c.Cluster.NodeImage, "BREWCTL_KIND_NODE_IMAGE")
+
+	setFromEnv(&c.Airbyte.Namespace, "BREWCTL_AIRBYTE_NAMESPACE")
+	setFromEnv(&c.Airbyte.URL, "BREWCTL_AIRBYTE_URL")
+	setFromEnv(&c.Airbyte.API, "BREWCTL_AIRBYTE_API")
+	setFromEnv(&c.Airbyte.Auth.Type, "BREWCTL_AIRBYTE_AUTH_TYPE")
+	setFromEnv(&c.Airbyte.Auth.Username, "BREWCTL_AIRBYTE_USERNAME")
+	setFromEnv(&c.Airbyte.Auth.Password, "BREWCTL_AIRBYTE_PASSWORD")
+	setFromEnv(&c.Airbyte.Auth.Token, "BREWCTL_AIRBYTE_TOKEN")
+	setFromEnv(&c.Airbyte.Auth.ClientID, "BREWCTL_AIRBYTE_CLIENT_ID")
+	setFromEnv(&c.Airbyte.Auth.ClientSecret, "BREWCTL_AIRBYTE_CLIENT_SECRET")
+	setFromEnv(&c.Airbyte.Auth.TokenURL, "BREWCTL_AIRBYTE_TOKEN_URL")
+
+	setFromEnv(&c.MongoDB.Mode, "BREWCTL_MONGODB_MODE")
+	setFromEnv(&c.MongoDB.URI, "BREWCTL_MONGODB_URI")
+	setFromEnv(&c.MongoDB.Namespace, "BREWCTL_MONGODB_NAMESPACE")
+	setFromEnv(&c.MongoDB.Database, "BREWCTL_MONGODB_DATABASE")
+	setFromEnv(&c.MongoDB.Destination.Auth.Username, "BREWCTL_MONGODB_USERNAME")
+	setFromEnv(&c.MongoDB.Destination.Auth.Password, "BREWCTL_MONGODB_PASSWORD")
+
+	setFromEnv(&c.Monitoring.Namespace, "BREWCTL_MONITORING_NAMESPACE")
+
+	setFromEnv(&c.Destinations.Postgres.Host, "BREWCTL_POSTGRES_HOST")
+	setFromEnv(&c.Destinations.Postgres.Database, "BREWCTL_POSTGRES_DATABASE")
+	setFromEnv(&c.Destinations.Postgres.Username, "BREWCTL_POSTGRES_USERNAME")
+	setFromEnv(&c.Destinations.Postgres.Password, "BREWCTL_POSTGRES_PASSWORD")
+}
+
+func setFromEnv(field *string, key string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*field = value
+	}
+}
+
+// Validate verifica se a configuração é consistente
+func (c *Config) Validate() error {
+	if err := c.Cluster.Validate(); err != nil {
+		return err
+	}
+
+	if c.Airbyte.URL == "" {
+		return fmt.Errorf("airbyte.url must not be empty")
+	}
+	if c.Airbyte.Namespace == "" {
+		return fmt.Errorf("airbyte.namespace must not be empty")
+	}
+	if c.Monitoring.Namespace == "" {
+		return fmt.Errorf("monitoring.namespace must not be empty")
+	}
+
+	switch c.Airbyte.API {
+	case "", APIAuto, APILegacy, APIPublic:
+	default:
+		return fmt.Errorf("airbyte.api: unknown value %q (expected auto, legacy or public)", c.Airbyte.API)
+	}
+
+	auth := c.Airbyte.Auth
+	switch auth.ResolvedType() {
+	case AuthNone:
+	case AuthBasic:
+		if auth.Username == "" {
+			return fmt.Errorf("airbyte.auth: basic auth requires a username")
+		}
+	case AuthBearer:
+		if auth.Token == "" {
+			return fmt.Errorf("airbyte.auth: bearer auth requires a token")
+		}
+	case AuthClientCredentials:
+		if auth.ClientID == "" || auth.ClientSecret == "" {
+			return fmt.Errorf("airbyte.auth: client_credentials requires client_id and client_secret")
+		}
+	default:
+		return fmt.Errorf("airbyte.auth: unknown type %q (expected none, basic, bearer or client_credentials)", auth.Type)
+	}
+
+	if err := c.MongoDB.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Namespaces retorna os namespaces em que o brewctl implanta componentes, sem
+// repetições e sem o do MongoDB no modo external
+func (c *Config) Namespaces() []string {
+	candidates := []string{c.Airbyte.Namespace, c.Monitoring.Namespace}
+	if c.MongoDB.Mode != MongoDBModeExternal {
+		candidates = append([]string{c.MongoDB.Namespace}, candidates...)
+	}
+
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, ns := range candidates {
+		if !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// ResolvedType retorna o tipo de autenticação, inferindo-o dos campos quando Type está vazio
+func (a AirbyteAuth) ResolvedType() string {
+	if a.Type != "" {
+		return a.Type
+	}
+	switch {
+	case a.ClientID != "" || a.ClientSecret != "":
+		return AuthClientCredentials
+	case a.Token != "":
+		return AuthBearer
+	case a.Username != "":
+		return AuthBasic
+	}
+	return AuthNone
+}
@@ -0,0 +1,202 @@
+// Package images lista as imagens usadas pelos charts e manifests do brewctl e
+// as pré-carrega nos nós do Kind, para que o cluster suba sem acesso à rede.
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"brewctl/internal/kube/helm"
+)
+
+// Image é uma imagem de container e os componentes que a usam
+type Image struct {
+	Ref    string
+	UsedBy []string
+}
+
+// Collector junta as imagens renderizando os charts com helm template
+type Collector struct {
+	Helm   *helm.Manager
+	images map[string]map[string]bool
+}
+
+// NewCollector cria um Collector que usa o Manager informado
+func NewCollector(m *helm.Manager) *Collector {
+	return &Collector{Helm: m, images: map[string]map[string]bool{}}
+}
+
+// AddRelease renderiza o release e registra as imagens dos seus manifests
+func (c *Collector) AddRelease(ctx context.Context, r helm.Release) error {
+	manifests, err := c.Helm.Template(ctx, r)
+	if err != nil {
+		return err
+	}
+	refs, err := Extract(manifests)
+	if err != nil {
+		return fmt.Errorf("failed to read images of release %s: %v", r.Name, err)
+	}
+	c.Add(r.Name, refs...)
+	return nil
+}
+
+// Add registra imagens usadas por um componente
+func (c *Collector) Add(component string, refs ...string) {
+	for _, ref := range refs {
+		if c.images[ref] == nil {
+			c.images[ref] = map[string]bool{}
+		}
+		c.images[ref][component] = true
+	}
+}
+
+// Images retorna as imagens registradas, ordenadas pela referência
+func (c *Collector) Images() []Image {
+	var result []Image
+	for ref, components := range c.images {
+		image := Image{Ref: ref}
+		for component := range components {
+			image.UsedBy = append(image.UsedBy, component)
+		}
+		sort.Strings(image.UsedBy)
+		result = append(result, image)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Ref < result[j].Ref })
+	return result
+}
+
+// Refs retorna apenas as referências das imagens
+func Refs(images []Image) []string {
+	refs := make([]string, len(images))
+	for i, image := range images {
+		refs[i] = image.Ref
+	}
+	return refs
+}
+
+// Extract lê manifests YAML (vários documentos) e retorna os valores dos
+// campos image, sem repetições
+func Extract(manifests []byte) ([]string, error) {
+	seen := map[string]bool{}
+	var refs []string
+
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		walk(doc, func(ref string) {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		})
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+func walk(node interface{}, found func(string)) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if ref, ok := value.(string); ok && key == "image" && ref != "" {
+				found(ref)
+				continue
+			}
+			walk(value, found)
+		}
+	case []interface{}:
+		for _, item := range n {
+			walk(item, found)
+		}
+	}
+}
+
+// Missing retorna as imagens que não estão no cache local do Docker
+func Missing(refs []string) []string {
+	var missing []string
+	for _, ref := range refs {
+		if err := exec.Command("docker", "image", "inspect", ref).Run(); err != nil {
+			missing = append(missing, ref)
+		}
+	}
+	return missing
+}
+
+// Pull baixa as imagens para o cache local do Docker
+func Pull(refs []string) error {
+	for _, ref := range refs {
+		fmt.Printf("⬇️ Pulling %s...\n", ref)
+		cmd := exec.Command("docker", "pull", ref)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to pull %s: %v", ref, err)
+		}
+	}
+	return nil
+}
+
+// LoadFromDocker copia as imagens do cache local do Docker para os nós do cluster Kind
+func LoadFromDocker(cluster string, refs []string) error {
+	if missing := Missing(refs); len(missing) > 0 {
+		return fmt.Errorf("images not found in the local Docker cache (use --pull or an archive): %s", strings.Join(missing, ", "))
+	}
+
+	fmt.Printf("📦 Loading %d images into Kind cluster %s...\n", len(refs), cluster)
+	args := append([]string{"load", "docker-image", "--name", cluster}, refs...)
+	cmd := exec.Command("kind", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to load images into kind: %v", err)
+	}
+	return nil
+}
+
+// LoadArchive carrega nos nós do Kind as imagens de um tarball (docker save ou OCI)
+func LoadArchive(cluster, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("image archive: %v", err)
+	}
+
+	fmt.Printf("📦 Loading %s into Kind cluster %s...\n", path, cluster)
+	cmd := exec.Command("kind", "load", "image-archive", path, "--name", cluster)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to load image archive into kind: %v", err)
+	}
+	return nil
+}
+
+// Save grava as imagens do cache local do Docker em um único tarball
+func Save(refs []string, path string) error {
+	if missing := Missing(refs); len(missing) > 0 {
+		return fmt.Errorf("images not found in the local Docker cache (use --pull): %s", strings.Join(missing, ", "))
+	}
+
+	fmt.Printf("💾 Saving %d images to %s...\n", len(refs), path)
+	args := append([]string{"save", "--output", path}, refs...)
+	cmd := exec.Command("docker", args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to save images: %v", err)
+	}
+	return nil
+}
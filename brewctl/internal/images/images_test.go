@@ -0,0 +1,73 @@
+package images
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brewctl/internal/kube/helm"
+)
+
+const manifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: grafana
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init-chown-data
+          image: docker.io/library/busybox:1.31.1
+      containers:
+        - name: grafana
+          image: docker.io/grafana/grafana:10.2.2
+---
+# Source: grafana/templates/tests/test.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: grafana-test
+spec:
+  containers:
+    - name: grafana-test
+      image: docker.io/bats/bats:v1.4.1
+    - name: sidecar
+      image: docker.io/library/busybox:1.31.1
+---
+`
+
+type templateRunner struct{ output string }
+
+func (r templateRunner) Run(ctx context.Context, stdout io.Writer, args ...string) error {
+	if args[0] == "template" {
+		io.WriteString(stdout, r.output)
+	}
+	return nil
+}
+
+func TestExtract(t *testing.T) {
+	refs, err := Extract([]byte(manifests))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"docker.io/bats/bats:v1.4.1",
+		"docker.io/grafana/grafana:10.2.2",
+		"docker.io/library/busybox:1.31.1",
+	}, refs)
+}
+
+func TestCollector(t *testing.T) {
+	m := &helm.Manager{Runner: templateRunner{output: manifests}, Out: io.Discard}
+	c := NewCollector(m)
+
+	require.NoError(t, c.AddRelease(context.Background(), helm.Release{Name: "grafana", Chart: helm.Chart{Repo: "grafana", Name: "grafana"}}))
+	c.Add("mongodb", "mongo:6.0.5", "docker.io/library/busybox:1.31.1")
+
+	images := c.Images()
+	require.Len(t, images, 4)
+	assert.Equal(t, Image{Ref: "docker.io/library/busybox:1.31.1", UsedBy: []string{"grafana", "mongodb"}}, images[2])
+	assert.Equal(t, "mongo:6.0.5", Refs(images)[3])
+}
@@ -0,0 +1,392 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	batchv1ac "k8s.io/client-go/applyconfigurations/batch/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// FieldManager identifica o brewctl como dono dos campos aplicados via server-side apply
+const FieldManager = "brewctl"
+
+// HelmResourcePolicyAnnotation com o valor keep faz o Helm manter o recurso no uninstall
+const HelmResourcePolicyAnnotation = "helm.sh/resource-policy"
+
+// PartOfLabel marca os namespaces criados pelo brewctl
+const PartOfLabel = "app.kubernetes.io/part-of"
+
+// Client encapsula o client-go usado para aplicar recursos e acompanhar o cluster
+type Client struct {
+	Clientset kubernetes.Interface
+	// RESTConfig é nil quando o Client é criado a partir de um clientset fake
+	RESTConfig   *rest.Config
+	PollInterval time.Duration
+}
+
+// NewClient cria um Client a partir do kubeconfig padrão (KUBECONFIG ou
+// ~/.kube/config). kubeContext vazio usa o contexto atual.
+func NewClient(kubeContext string) (*Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+	}
+
+	client := NewClientFromClientset(clientset)
+	client.RESTConfig = restConfig
+	return client, nil
+}
+
+// NewClientFromClientset cria um Client sobre um clientset existente (ex.: fake nos testes)
+func NewClientFromClientset(clientset kubernetes.Interface) *Client {
+	return &Client{
+		Clientset:    clientset,
+		PollInterval: 2 * time.Second,
+	}
+}
+
+// Apply aplica os recursos via server-side apply, na ordem informada
+func (c *Client) Apply(ctx context.Context, objects ...interface{}) error {
+	opts := metav1.ApplyOptions{FieldManager: FieldManager, Force: true}
+
+	for _, obj := range objects {
+		var err error
+		var kind, name string
+
+		switch o := obj.(type) {
+		case *corev1ac.NamespaceApplyConfiguration:
+			kind, name = "namespace", *o.Name
+			_, err = c.Clientset.CoreV1().Namespaces().Apply(ctx, o, opts)
+		case *corev1ac.SecretApplyConfiguration:
+			kind, name = "secret", *o.Name
+			_, err = c.Clientset.CoreV1().Secrets(*o.Namespace).Apply(ctx, o, opts)
+		case *corev1ac.ServiceApplyConfiguration:
+			kind, name = "service", *o.Name
+			_, err = c.Clientset.CoreV1().Services(*o.Namespace).Apply(ctx, o, opts)
+		case *appsv1ac.DeploymentApplyConfiguration:
+			kind, name = "deployment", *o.Name
+			_, err = c.Clientset.AppsV1().Deployments(*o.Namespace).Apply(ctx, o, opts)
+		case *appsv1ac.StatefulSetApplyConfiguration:
+			kind, name = "statefulset", *o.Name
+			_, err = c.Clientset.AppsV1().StatefulSets(*o.Namespace).Apply(ctx, o, opts)
+		case *batchv1ac.JobApplyConfiguration:
+			kind, name = "job", *o.Name
+			_, err = c.Clientset.BatchV1().Jobs(*o.Namespace).Apply(ctx, o, opts)
+		case *networkingv1ac.NetworkPolicyApplyConfiguration:
+			kind, name = "networkpolicy", *o.Name
+			_, err = c.Clientset.NetworkingV1().NetworkPolicies(*o.Namespace).Apply(ctx, o, opts)
+		default:
+			return fmt.Errorf("apply: unsupported object type %T", obj)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to apply %s %s: %v", kind, name, err)
+		}
+		fmt.Printf("  ✔ %s/%s applied\n", kind, name)
+	}
+	return nil
+}
+
+// EnsureNamespace cria o namespace, se necessário, com o label do brewctl
+func (c *Client) EnsureNamespace(ctx context.Context, name string) error {
+	return c.Apply(ctx, corev1ac.Namespace(name).WithLabels(map[string]string{PartOfLabel: FieldManager}))
+}
+
+// ignoreNotFound trata recursos inexistentes como sucesso em operações de remoção
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// WaitForDeploymentReady aguarda até que todas as réplicas do deployment estejam prontas
+func (c *Client) WaitForDeploymentReady(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, c.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		d, err := c.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return deploymentReady(d), nil
+	})
+	if err != nil {
+		return fmt.Errorf("deployment %s/%s not ready: %v", namespace, name, err)
+	}
+	return nil
+}
+
+func deploymentReady(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.ReadyReplicas == replicas
+}
+
+// WaitForStatefulSetReady aguarda até que todas as réplicas do StatefulSet estejam prontas
+func (c *Client) WaitForStatefulSetReady(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, c.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		s, err := c.Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		replicas := int32(1)
+		if s.Spec.Replicas != nil {
+			replicas = *s.Spec.Replicas
+		}
+		return s.Status.ObservedGeneration >= s.Generation &&
+			s.Status.UpdatedReplicas == replicas &&
+			s.Status.ReadyReplicas == replicas, nil
+	})
+	if err != nil {
+		return fmt.Errorf("statefulset %s/%s not ready: %v", namespace, name, err)
+	}
+	return nil
+}
+
+// WaitForJobComplete aguarda a conclusão do Job, falhando se ele falhar
+func (c *Client) WaitForJobComplete(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, c.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		job, err := c.Clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range job.Status.Conditions {
+			if cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch cond.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job failed: %s", cond.Message)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("job %s/%s did not complete: %v", namespace, name, err)
+	}
+	return nil
+}
+
+// WaitForPodsReady aguarda até que exista ao menos um pod com o seletor e todos estejam Ready
+func (c *Client) WaitForPodsReady(ctx context.Context, namespace, selector string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, c.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+		if len(pods.Items) == 0 {
+			return false, nil
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			// Pods de jobs concluídos não ficam Ready
+			if pod.Status.Phase == corev1.PodSucceeded {
+				continue
+			}
+			if !podReady(pod) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("pods %q in %s not ready: %v", selector, namespace, err)
+	}
+	return nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// NodeStatus resume o estado de um nó do cluster
+type NodeStatus struct {
+	Name           string
+	Roles          []string
+	Ready          bool
+	KubeletVersion string
+	InternalIP     string
+}
+
+// NodeStatuses lista os nós do cluster com o estado de cada um
+func (c *Client) NodeStatuses(ctx context.Context) ([]NodeStatus, error) {
+	nodes, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	statuses := make([]NodeStatus, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		status := NodeStatus{
+			Name:           node.Name,
+			KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		}
+		for label := range node.Labels {
+			if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok {
+				status.Roles = append(status.Roles, role)
+			}
+		}
+		sort.Strings(status.Roles)
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady {
+				status.Ready = cond.Status == corev1.ConditionTrue
+			}
+		}
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == corev1.NodeInternalIP {
+				status.InternalIP = addr.Address
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// CheckCluster verifica se a API responde e se todos os nós estão Ready
+func (c *Client) CheckCluster(ctx context.Context) ([]NodeStatus, error) {
+	if _, err := c.Clientset.Discovery().ServerVersion(); err != nil {
+		return nil, fmt.Errorf("kubernetes cluster not accessible: %v", err)
+	}
+
+	nodes, err := c.NodeStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster has no nodes")
+	}
+
+	var notReady []string
+	for _, n := range nodes {
+		if !n.Ready {
+			notReady = append(notReady, n.Name)
+		}
+	}
+	if len(notReady) > 0 {
+		return nodes, fmt.Errorf("nodes not ready: %s", strings.Join(notReady, ", "))
+	}
+	return nodes, nil
+}
+
+// WaitForNodesReady aguarda até que todos os nós do cluster estejam Ready
+func (c *Client) WaitForNodesReady(ctx context.Context, timeout time.Duration) error {
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, c.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		_, lastErr = c.CheckCluster(ctx)
+		return lastErr == nil, nil
+	})
+	if err != nil && lastErr != nil {
+		return fmt.Errorf("cluster not ready: %v", lastErr)
+	}
+	return err
+}
+
+// DeleteDeployment remove um deployment, ignorando se ele não existir
+func (c *Client) DeleteDeployment(ctx context.Context, namespace, name string) error {
+	return ignoreNotFound(c.Clientset.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+}
+
+// DeleteService remove um service, ignorando se ele não existir
+func (c *Client) DeleteService(ctx context.Context, namespace, name string) error {
+	return ignoreNotFound(c.Clientset.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+}
+
+// DeleteStatefulSet remove um statefulset, ignorando se ele não existir
+func (c *Client) DeleteStatefulSet(ctx context.Context, namespace, name string) error {
+	return ignoreNotFound(c.Clientset.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+}
+
+// DeleteJob remove um job e seus pods, ignorando se ele não existir
+func (c *Client) DeleteJob(ctx context.Context, namespace, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	return ignoreNotFound(c.Clientset.BatchV1().Jobs(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation}))
+}
+
+// DeleteSecret remove um secret, ignorando se ele não existir
+func (c *Client) DeleteSecret(ctx context.Context, namespace, name string) error {
+	return ignoreNotFound(c.Clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+}
+
+// KeepPVCs anota os PersistentVolumeClaims que casam com o seletor para que o
+// helm uninstall os preserve, retornando os anotados
+func (c *Client) KeepPVCs(ctx context.Context, namespace, selector string) ([]string, error) {
+	pvcs, err := c.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %v", err)
+	}
+
+	patch := []byte(`{"metadata":{"annotations":{"` + HelmResourcePolicyAnnotation + `":"keep"}}}`)
+	var kept []string
+	for _, pvc := range pvcs.Items {
+		if _, err := c.Clientset.CoreV1().PersistentVolumeClaims(namespace).Patch(ctx, pvc.Name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: FieldManager}); err != nil {
+			return kept, fmt.Errorf("failed to annotate PVC %s: %v", pvc.Name, err)
+		}
+		kept = append(kept, pvc.Name)
+	}
+	return kept, nil
+}
+
+// DeletePVCs remove os PersistentVolumeClaims que casam com o seletor e cujo
+// nome começa com namePrefix (vazio aceita qualquer nome), retornando os removidos
+func (c *Client) DeletePVCs(ctx context.Context, namespace, selector, namePrefix string) ([]string, error) {
+	pvcs, err := c.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %v", err)
+	}
+
+	var deleted []string
+	for _, pvc := range pvcs.Items {
+		if !strings.HasPrefix(pvc.Name, namePrefix) {
+			continue
+		}
+		if err := ignoreNotFound(c.Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})); err != nil {
+			return deleted, fmt.Errorf("failed to delete PVC %s: %v", pvc.Name, err)
+		}
+		fmt.Printf("  ✔ persistentvolumeclaim/%s deleted\n", pvc.Name)
+		deleted = append(deleted, pvc.Name)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,154 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestClient(objects ...runtime.Object) *Client {
+	client := NewClientFromClientset(fake.NewClientset(objects...))
+	client.PollInterval = 10 * time.Millisecond
+	return client
+}
+
+func TestWaitForDeploymentReady(t *testing.T) {
+	replicas := int32(2)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, ReadyReplicas: 1},
+	}
+	client := newTestClient(dep)
+	ctx := context.Background()
+
+	err := client.WaitForDeploymentReady(ctx, "default", "web", 50*time.Millisecond)
+	assert.Error(t, err)
+
+	dep.Status.ReadyReplicas = 2
+	_, err = client.Clientset.AppsV1().Deployments("default").UpdateStatus(ctx, dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.NoError(t, client.WaitForDeploymentReady(ctx, "default", "web", time.Second))
+}
+
+func TestWaitForPodsReady(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "airbyte"}},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	ctx := context.Background()
+
+	client := newTestClient(
+		pod("server", corev1.PodRunning, corev1.ConditionTrue),
+		pod("bootloader", corev1.PodSucceeded, corev1.ConditionFalse),
+	)
+	assert.NoError(t, client.WaitForPodsReady(ctx, "default", "app=airbyte", time.Second))
+
+	client = newTestClient(pod("worker", corev1.PodRunning, corev1.ConditionFalse))
+	assert.Error(t, client.WaitForPodsReady(ctx, "default", "app=airbyte", 50*time.Millisecond))
+
+	client = newTestClient()
+	assert.Error(t, client.WaitForPodsReady(ctx, "default", "app=airbyte", 50*time.Millisecond))
+}
+
+func TestCheckCluster(t *testing.T) {
+	node := func(name string, ready corev1.ConditionStatus, labels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+				Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "172.18.0.2"}},
+				NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.27.3"},
+			},
+		}
+	}
+	ctx := context.Background()
+
+	client := newTestClient(node("brewctl-cluster-control-plane", corev1.ConditionTrue,
+		map[string]string{"node-role.kubernetes.io/control-plane": ""}))
+	nodes, err := client.CheckCluster(ctx)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, []string{"control-plane"}, nodes[0].Roles)
+	assert.Equal(t, "172.18.0.2", nodes[0].InternalIP)
+	assert.Equal(t, "v1.27.3", nodes[0].KubeletVersion)
+
+	client = newTestClient(node("brewctl-cluster-worker", corev1.ConditionFalse, nil))
+	_, err = client.CheckCluster(ctx)
+	assert.ErrorContains(t, err, "brewctl-cluster-worker")
+
+	_, err = newTestClient().CheckCluster(ctx)
+	assert.ErrorContains(t, err, "no nodes")
+}
+
+func TestResolveServicePod(t *testing.T) {
+	ctx := context.Background()
+	labels := map[string]string{"app": "grafana"}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "brewctl-monitoring"},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("grafana")}},
+		},
+	}
+	pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "brewctl-monitoring", Labels: labels},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "grafana",
+				Ports: []corev1.ContainerPort{{Name: "grafana", ContainerPort: 3000}},
+			}}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	client := newTestClient(svc, pod("grafana-a", corev1.ConditionFalse), pod("grafana-b", corev1.ConditionTrue))
+
+	name, port, err := client.ResolveServicePod(ctx, "brewctl-monitoring", "grafana", 80)
+	require.NoError(t, err)
+	assert.Equal(t, "grafana-b", name)
+	assert.Equal(t, 3000, port)
+
+	_, _, err = client.ResolveServicePod(ctx, "brewctl-monitoring", "grafana", 8080)
+	assert.ErrorContains(t, err, "does not expose port 8080")
+}
+
+func TestKeepPVCs(t *testing.T) {
+	ctx := context.Background()
+	pvc := func(name, instance string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "brewctl-monitoring",
+			Labels:    map[string]string{"app.kubernetes.io/instance": instance},
+		}}
+	}
+	client := newTestClient(pvc("grafana", "grafana"), pvc("prometheus-server", "prometheus"))
+
+	kept, err := client.KeepPVCs(ctx, "brewctl-monitoring", "app.kubernetes.io/instance=grafana")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grafana"}, kept)
+
+	grafana, err := client.Clientset.CoreV1().PersistentVolumeClaims("brewctl-monitoring").Get(ctx, "grafana", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "keep", grafana.Annotations[HelmResourcePolicyAnnotation])
+
+	prometheus, err := client.Clientset.CoreV1().PersistentVolumeClaims("brewctl-monitoring").Get(ctx, "prometheus-server", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, prometheus.Annotations)
+}
@@ -0,0 +1,319 @@
+// Package helm gerencia os releases Helm do brewctl por meio do binário helm.
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrReleaseNotFound indica que o release não está instalado no namespace
+var ErrReleaseNotFound = errors.New("release not found")
+
+// Chart identifica um chart em um repositório com versão fixada
+type Chart struct {
+	Repo    string
+	RepoURL string
+	Name    string
+	Version string
+}
+
+// Ref retorna a referência usada pelo helm (repo/chart)
+func (c Chart) Ref() string {
+	return c.Repo + "/" + c.Name
+}
+
+// OptionalValuesFiles retorna o values file para Release.ValuesFiles. O arquivo
+// padrão é opcional, para que o brewctl funcione fora da raiz do repositório;
+// um arquivo informado pelo usuário é sempre passado ao helm.
+func OptionalValuesFiles(path, defaultPath string) []string {
+	if path == "" {
+		return nil
+	}
+	if path == defaultPath {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	return []string{path}
+}
+
+// Release descreve um release instalado com upgrade --install. Values usa
+// chaves no formato do --set ("server.service.type") e tem precedência sobre
+// os ValuesFiles, que são aplicados na ordem informada.
+type Release struct {
+	Name        string
+	Namespace   string
+	Chart       Chart
+	ValuesFiles []string
+	Values      map[string]interface{}
+	Timeout     time.Duration
+}
+
+// Status resume o estado de um release
+type Status struct {
+	Name         string
+	Namespace    string
+	Revision     int
+	Status       string
+	Chart        string
+	ChartVersion string
+	AppVersion   string
+	Updated      time.Time
+	Description  string
+}
+
+// Runner executa o helm com os argumentos informados, escrevendo a saída
+// padrão em stdout
+type Runner interface {
+	Run(ctx context.Context, stdout io.Writer, args ...string) error
+}
+
+// ExecRunner executa o binário helm do PATH
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, stdout io.Writer, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		if strings.Contains(msg, "release: not found") {
+			return ErrReleaseNotFound
+		}
+		return fmt.Errorf("helm %s: %s", args[0], msg)
+	}
+	return nil
+}
+
+// Manager instala e consulta releases de forma idempotente
+type Manager struct {
+	Runner Runner
+	// Out recebe a saída das operações longas (install, rollback, uninstall)
+	Out io.Writer
+
+	updatedRepos map[string]bool
+}
+
+// NewManager cria um Manager que executa o helm do PATH
+func NewManager() *Manager {
+	return &Manager{Runner: ExecRunner{}, Out: os.Stdout}
+}
+
+// AddRepo adiciona (ou atualiza a URL de) um repositório e atualiza seu
+// índice uma única vez por Manager
+func (m *Manager) AddRepo(ctx context.Context, name, url string) error {
+	if m.updatedRepos[name] {
+		return nil
+	}
+	if err := m.Runner.Run(ctx, io.Discard, "repo", "add", name, url, "--force-update"); err != nil {
+		return fmt.Errorf("failed to add %s repo: %v", name, err)
+	}
+	if err := m.Runner.Run(ctx, io.Discard, "repo", "update", name); err != nil {
+		return fmt.Errorf("failed to update %s repo: %v", name, err)
+	}
+
+	if m.updatedRepos == nil {
+		m.updatedRepos = map[string]bool{}
+	}
+	m.updatedRepos[name] = true
+	return nil
+}
+
+// UpgradeInstall instala o release ou o atualiza se já existir
+func (m *Manager) UpgradeInstall(ctx context.Context, r Release) error {
+	if err := m.AddRepo(ctx, r.Chart.Repo, r.Chart.RepoURL); err != nil {
+		return err
+	}
+
+	args, cleanup, err := chartArgs(r, []string{"upgrade", "--install"}, "--create-namespace")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if r.Timeout > 0 {
+		args = append(args, "--wait", "--timeout", r.Timeout.String())
+	}
+
+	if err := m.Runner.Run(ctx, m.Out, args...); err != nil {
+		return fmt.Errorf("failed to install release %s: %v", r.Name, err)
+	}
+	return nil
+}
+
+// Template renderiza os manifests do release sem instalá-lo
+func (m *Manager) Template(ctx context.Context, r Release) ([]byte, error) {
+	if err := m.AddRepo(ctx, r.Chart.Repo, r.Chart.RepoURL); err != nil {
+		return nil, err
+	}
+
+	args, cleanup, err := chartArgs(r, []string{"template"})
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var out bytes.Buffer
+	if err := m.Runner.Run(ctx, &out, args...); err != nil {
+		return nil, fmt.Errorf("failed to render release %s: %v", r.Name, err)
+	}
+	return out.Bytes(), nil
+}
+
+// chartArgs monta os argumentos comuns a upgrade e template: release, chart,
+// namespace, versão e values. cleanup remove o values file temporário.
+func chartArgs(r Release, command []string, flags ...string) ([]string, func(), error) {
+	args := append(command, r.Name, r.Chart.Ref(), "--namespace", r.Namespace)
+	args = append(args, flags...)
+	if r.Chart.Version != "" {
+		args = append(args, "--version", r.Chart.Version)
+	}
+	for _, file := range r.ValuesFiles {
+		args = append(args, "--values", file)
+	}
+
+	if len(r.Values) == 0 {
+		return args, func() {}, nil
+	}
+	overrides, err := writeValues(r.Values)
+	if err != nil {
+		return nil, nil, err
+	}
+	args = append(args, "--values", overrides)
+	return args, func() { os.Remove(overrides) }, nil
+}
+
+// Status consulta o estado do release; retorna ErrReleaseNotFound se não existir
+func (m *Manager) Status(ctx context.Context, name, namespace string) (*Status, error) {
+	var out bytes.Buffer
+	if err := m.Runner.Run(ctx, &out, "status", name, "--namespace", namespace, "--output", "json"); err != nil {
+		if errors.Is(err, ErrReleaseNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get status of release %s: %v", name, err)
+	}
+
+	var payload struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Version   int    `json:"version"`
+		Info      struct {
+			Status       string    `json:"status"`
+			LastDeployed time.Time `json:"last_deployed"`
+			Description  string    `json:"description"`
+		} `json:"info"`
+		Chart struct {
+			Metadata struct {
+				Name       string `json:"name"`
+				Version    string `json:"version"`
+				AppVersion string `json:"appVersion"`
+			} `json:"metadata"`
+		} `json:"chart"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse status of release %s: %v", name, err)
+	}
+
+	return &Status{
+		Name:         payload.Name,
+		Namespace:    payload.Namespace,
+		Revision:     payload.Version,
+		Status:       payload.Info.Status,
+		Chart:        payload.Chart.Metadata.Name,
+		ChartVersion: payload.Chart.Metadata.Version,
+		AppVersion:   payload.Chart.Metadata.AppVersion,
+		Updated:      payload.Info.LastDeployed,
+		Description:  payload.Info.Description,
+	}, nil
+}
+
+// Rollback volta o release para a revisão informada (0 = revisão anterior)
+func (m *Manager) Rollback(ctx context.Context, name, namespace string, revision int, timeout time.Duration) error {
+	args := []string{"rollback", name}
+	if revision > 0 {
+		args = append(args, strconv.Itoa(revision))
+	}
+	args = append(args, "--namespace", namespace)
+	if timeout > 0 {
+		args = append(args, "--wait", "--timeout", timeout.String())
+	}
+
+	if err := m.Runner.Run(ctx, m.Out, args...); err != nil {
+		if errors.Is(err, ErrReleaseNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to roll back release %s: %v", name, err)
+	}
+	return nil
+}
+
+// Uninstall remove o release; um release inexistente não é erro
+func (m *Manager) Uninstall(ctx context.Context, name, namespace string) error {
+	err := m.Runner.Run(ctx, m.Out, "uninstall", name, "--namespace", namespace, "--wait")
+	if err != nil && !errors.Is(err, ErrReleaseNotFound) {
+		return fmt.Errorf("failed to uninstall release %s: %v", name, err)
+	}
+	return nil
+}
+
+// writeValues grava os overrides em um values file temporário
+func writeValues(values map[string]interface{}) (string, error) {
+	data, err := yaml.Marshal(nestValues(values))
+	if err != nil {
+		return "", fmt.Errorf("failed to render helm values: %v", err)
+	}
+
+	f, err := os.CreateTemp("", "brewctl-values-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to write helm values: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write helm values: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// nestValues converte chaves no formato do --set em mapas aninhados
+func nestValues(values map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nested := map[string]interface{}{}
+	for _, key := range keys {
+		parts := strings.Split(key, ".")
+		node := nested
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = values[key]
+	}
+	return nested
+}
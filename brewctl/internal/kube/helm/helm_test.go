@@ -0,0 +1,130 @@
+package helm
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeRunner registra os comandos e devolve a saída configurada por subcomando
+type fakeRunner struct {
+	calls   [][]string
+	outputs map[string]string
+	errs    map[string]error
+	// values guarda o conteúdo do último values file de overrides
+	values string
+}
+
+func (f *fakeRunner) Run(ctx context.Context, stdout io.Writer, args ...string) error {
+	f.calls = append(f.calls, args)
+	for i, arg := range args {
+		if arg == "--values" && strings.Contains(args[i+1], "brewctl-values-") {
+			data, _ := os.ReadFile(args[i+1])
+			f.values = string(data)
+		}
+	}
+	if err := f.errs[args[0]]; err != nil {
+		return err
+	}
+	io.WriteString(stdout, f.outputs[args[0]])
+	return nil
+}
+
+func newTestManager(runner *fakeRunner) *Manager {
+	return &Manager{Runner: runner, Out: io.Discard}
+}
+
+func TestUpgradeInstall(t *testing.T) {
+	runner := &fakeRunner{}
+	m := newTestManager(runner)
+
+	release := Release{
+		Name:        "grafana",
+		Namespace:   "monitoring",
+		Chart:       Chart{Repo: "grafana", RepoURL: "https://grafana.github.io/helm-charts", Name: "grafana", Version: "7.0.11"},
+		ValuesFiles: []string{"deployments/grafana.yaml"},
+		Values: map[string]interface{}{
+			"service.type":     "NodePort",
+			"service.nodePort": 32000,
+			"adminPassword":    "admin",
+		},
+		Timeout: 5 * time.Minute,
+	}
+	require.NoError(t, m.UpgradeInstall(context.Background(), release))
+	require.NoError(t, m.UpgradeInstall(context.Background(), release))
+
+	// O repositório é atualizado apenas uma vez por Manager
+	require.Len(t, runner.calls, 4)
+	assert.Equal(t, []string{"repo", "add", "grafana", "https://grafana.github.io/helm-charts", "--force-update"}, runner.calls[0])
+	assert.Equal(t, []string{"repo", "update", "grafana"}, runner.calls[1])
+
+	args := runner.calls[2]
+	assert.Equal(t, []string{"upgrade", "--install", "grafana", "grafana/grafana", "--namespace", "monitoring", "--create-namespace", "--version", "7.0.11", "--values", "deployments/grafana.yaml", "--values"}, args[:12])
+	assert.Equal(t, []string{"--wait", "--timeout", "5m0s"}, args[len(args)-3:])
+
+	var values map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(runner.values), &values))
+	assert.Equal(t, map[string]interface{}{
+		"adminPassword": "admin",
+		"service":       map[string]interface{}{"type": "NodePort", "nodePort": 32000},
+	}, values)
+
+	// O arquivo temporário de overrides é removido
+	_, err := os.Stat(args[12])
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStatus(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{"status": `{
+		"name": "airbyte", "namespace": "default", "version": 3,
+		"info": {"status": "deployed", "last_deployed": "2024-10-01T12:00:00Z", "description": "Upgrade complete"},
+		"chart": {"metadata": {"name": "airbyte", "version": "1.1.0", "appVersion": "1.1.0"}}
+	}`}}
+	m := newTestManager(runner)
+
+	status, err := m.Status(context.Background(), "airbyte", "default")
+	require.NoError(t, err)
+	assert.Equal(t, 3, status.Revision)
+	assert.Equal(t, "deployed", status.Status)
+	assert.Equal(t, "1.1.0", status.ChartVersion)
+	assert.Equal(t, []string{"status", "airbyte", "--namespace", "default", "--output", "json"}, runner.calls[0])
+
+	runner.errs = map[string]error{"status": ErrReleaseNotFound}
+	_, err = m.Status(context.Background(), "airbyte", "default")
+	assert.ErrorIs(t, err, ErrReleaseNotFound)
+}
+
+func TestRollbackAndUninstall(t *testing.T) {
+	runner := &fakeRunner{errs: map[string]error{"uninstall": ErrReleaseNotFound}}
+	m := newTestManager(runner)
+
+	require.NoError(t, m.Rollback(context.Background(), "prometheus", "default", 0, time.Minute))
+	require.NoError(t, m.Rollback(context.Background(), "prometheus", "default", 2, 0))
+	assert.Equal(t, []string{"rollback", "prometheus", "--namespace", "default", "--wait", "--timeout", "1m0s"}, runner.calls[0])
+	assert.Equal(t, []string{"rollback", "prometheus", "2", "--namespace", "default"}, runner.calls[1])
+
+	// Remover um release que não existe não é erro
+	assert.NoError(t, m.Uninstall(context.Background(), "prometheus", "default"))
+}
+
+func TestOptionalValuesFiles(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(existing, []byte("replicas: 1\n"), 0644))
+	missing := filepath.Join(dir, "missing.yaml")
+
+	assert.Equal(t, []string{existing}, OptionalValuesFiles(existing, existing))
+	// O arquivo padrão ausente usa os defaults do chart
+	assert.Nil(t, OptionalValuesFiles(missing, missing))
+	// Um arquivo informado pelo usuário chega ao helm, que aponta o erro
+	assert.Equal(t, []string{missing}, OptionalValuesFiles(missing, existing))
+	assert.Nil(t, OptionalValuesFiles("", existing))
+}
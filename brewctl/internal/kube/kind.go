@@ -1,44 +1,253 @@
 package kube
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 	"time"
+
+	"brewctl/internal/config"
+
+	"gopkg.in/yaml.v3"
 )
 
-func CreateKindCluster() error {
-	fmt.Println("🔧 Creating Kind Kubernetes cluster...")
-
-	// ✅ USAR CONFIGURAÇÃO ALTERNATIVA (sem portas 80/443)
-	kindConfig := `kind: Cluster
-apiVersion: kind.x-k8s.io/v1alpha4
-name: brewctl-cluster
-nodes:
-- role: control-plane
-  extraPortMappings:
-  - containerPort: 8000
-    hostPort: 8000
-    protocol: TCP
-  - containerPort: 3000
-    hostPort: 3000
-    protocol: TCP  
-  - containerPort: 9090
-    hostPort: 9090
-    protocol: TCP
-  - containerPort: 27017
-    hostPort: 27017
-    protocol: TCP`
-
-	configPath := filepath.Join(os.TempDir(), "kind-config-brewctl.yaml")
-	if err := os.WriteFile(configPath, []byte(kindConfig), 0644); err != nil {
+// KindCluster é a configuração de cluster aceita pelo `kind create cluster --config`.
+// Campos que o brewctl não usa (featureGates, runtimeConfig,
+// containerdConfigPatches...) ficam em Extra e chegam intactos ao kind.
+type KindCluster struct {
+	Kind       string                 `yaml:"kind"`
+	APIVersion string                 `yaml:"apiVersion"`
+	Name       string                 `yaml:"name"`
+	Nodes      []KindNode             `yaml:"nodes"`
+	Networking map[string]interface{} `yaml:"networking,omitempty"`
+	Extra      map[string]interface{} `yaml:",inline"`
+}
+
+// KindNode descreve um nó do cluster Kind; Extra guarda os demais campos do nó
+type KindNode struct {
+	Role                 string                   `yaml:"role"`
+	Image                string                   `yaml:"image,omitempty"`
+	Labels               map[string]string        `yaml:"labels,omitempty"`
+	KubeadmConfigPatches []string                 `yaml:"kubeadmConfigPatches,omitempty"`
+	ExtraPortMappings    []KindPortMapping        `yaml:"extraPortMappings,omitempty"`
+	ExtraMounts          []map[string]interface{} `yaml:"extraMounts,omitempty"`
+	Extra                map[string]interface{}   `yaml:",inline"`
+}
+
+// KindPortMapping expõe uma porta do nó no host
+type KindPortMapping struct {
+	ContainerPort int    `yaml:"containerPort"`
+	HostPort      int    `yaml:"hostPort"`
+	ListenAddress string `yaml:"listenAddress,omitempty"`
+	Protocol      string `yaml:"protocol,omitempty"`
+}
+
+// kindPortMapping converte um port_mapping do brewctl config
+func kindPortMapping(pm config.PortMapping) KindPortMapping {
+	return KindPortMapping{ContainerPort: pm.ContainerPort, HostPort: pm.HostPort, Protocol: "TCP"}
+}
+
+const ingressReadyPatch = `kind: InitConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    node-labels: "ingress-ready=true"
+`
+
+// LoadKindConfig monta a configuração do cluster a partir do arquivo indicado
+// em cfg.ConfigFile ou, na ausência dele, da topologia declarada no brewctl config.
+// O nome do cluster e a imagem dos nós sempre vêm do brewctl config; as portas,
+// de port_mappings quando o arquivo não declara extraPortMappings.
+func LoadKindConfig(cfg config.ClusterConfig) (*KindCluster, error) {
+	var cluster *KindCluster
+
+	data, err := readKindConfigFile(cfg.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		cluster = &KindCluster{}
+		if err := yaml.Unmarshal(data, cluster); err != nil {
+			return nil, fmt.Errorf("failed to parse kind config %s: %v", cfg.ConfigFile, err)
+		}
+	} else {
+		cluster = generateKindConfig(cfg)
+	}
+
+	cluster.Kind = "Cluster"
+	cluster.APIVersion = "kind.x-k8s.io/v1alpha4"
+	cluster.Name = cfg.Name
+
+	if len(cluster.Nodes) == 0 {
+		return nil, fmt.Errorf("kind config for %s declares no nodes", cfg.Name)
+	}
+	if data != nil {
+		if err := applyPortMappings(cluster, cfg); err != nil {
+			return nil, err
+		}
+	}
+	for i := range cluster.Nodes {
+		if cluster.Nodes[i].Image == "" {
+			cluster.Nodes[i].Image = cfg.NodeImage
+		}
+	}
+	return cluster, nil
+}
+
+// applyPortMappings leva os port_mappings do brewctl config para um arquivo
+// sem extraPortMappings, no primeiro control-plane. Se o arquivo declara
+// portas, cada port_mapping precisa estar entre elas, para que as duas fontes
+// não divirjam em silêncio; portas a mais no arquivo são aceitas.
+func applyPortMappings(cluster *KindCluster, cfg config.ClusterConfig) error {
+	if len(cluster.HostPorts()) == 0 {
+		node := &cluster.Nodes[0]
+		for i := range cluster.Nodes {
+			if cluster.Nodes[i].Role == "control-plane" {
+				node = &cluster.Nodes[i]
+				break
+			}
+		}
+		for _, pm := range cfg.PortMappings {
+			node.ExtraPortMappings = append(node.ExtraPortMappings, kindPortMapping(pm))
+		}
+		return nil
+	}
+
+	declared := map[[2]int]bool{}
+	for _, node := range cluster.Nodes {
+		for _, pm := range node.ExtraPortMappings {
+			declared[[2]int{pm.ContainerPort, pm.HostPort}] = true
+		}
+	}
+	var missing []string
+	for _, pm := range cfg.PortMappings {
+		if !declared[[2]int{pm.ContainerPort, pm.HostPort}] {
+			missing = append(missing, fmt.Sprintf("%d->%d", pm.ContainerPort, pm.HostPort))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("cluster.port_mappings %s are not in the extraPortMappings of %s; "+
+			"declare the ports in one place (remove extraPortMappings from the file or align port_mappings with it)",
+			strings.Join(missing, ", "), cfg.ConfigFile)
+	}
+	return nil
+}
+
+// readKindConfigFile lê o arquivo de configuração do Kind. O arquivo padrão
+// do repositório é opcional: fora do diretório do projeto a topologia é gerada.
+func readKindConfigFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && path == config.DefaultKindConfigFile {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kind config %s: %v", path, err)
+	}
+	return data, nil
+}
+
+func generateKindConfig(cfg config.ClusterConfig) *KindCluster {
+	cluster := &KindCluster{}
+
+	for i := 0; i < cfg.ControlPlanes; i++ {
+		node := KindNode{Role: "control-plane"}
+		// Apenas o primeiro control-plane recebe o ingress e as portas do host
+		if i == 0 {
+			node.KubeadmConfigPatches = []string{ingressReadyPatch}
+			for _, pm := range cfg.PortMappings {
+				node.ExtraPortMappings = append(node.ExtraPortMappings, kindPortMapping(pm))
+			}
+		}
+		cluster.Nodes = append(cluster.Nodes, node)
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		cluster.Nodes = append(cluster.Nodes, KindNode{Role: "worker"})
+	}
+	return cluster
+}
+
+// HostPorts retorna as portas do host mapeadas pelo cluster
+func (k *KindCluster) HostPorts() []int {
+	var ports []int
+	for _, node := range k.Nodes {
+		for _, pm := range node.ExtraPortMappings {
+			ports = append(ports, pm.HostPort)
+		}
+	}
+	return ports
+}
+
+// ListKindClusters lista os clusters Kind existentes
+func ListKindClusters() ([]string, error) {
+	out, err := exec.Command("kind", "get", "clusters").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kind clusters: %v", err)
+	}
+
+	var clusters []string
+	for _, line := range strings.Split(string(out), "\n") {
+		// Sem clusters o kind imprime "No kind clusters found." no stderr
+		if line = strings.TrimSpace(line); line != "" {
+			clusters = append(clusters, line)
+		}
+	}
+	return clusters, nil
+}
+
+// KindClusterExists indica se já existe um cluster Kind com o nome informado
+func KindClusterExists(name string) (bool, error) {
+	clusters, err := ListKindClusters()
+	if err != nil {
+		return false, err
+	}
+	for _, c := range clusters {
+		if c == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateKindCluster cria o cluster descrito na configuração. É idempotente:
+// se o cluster já existir, apenas verifica se ele está acessível.
+func CreateKindCluster(cfg config.ClusterConfig) error {
+	exists, err := KindClusterExists(cfg.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Printf("ℹ️ Kind cluster %s already exists, skipping creation\n", cfg.Name)
+		if err := CheckClusterStatus(KindContext(cfg.Name)); err != nil {
+			return fmt.Errorf("cluster verification failed: %v", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("🔧 Creating Kind Kubernetes cluster %s...\n", cfg.Name)
+
+	data, err := RenderKindConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(os.TempDir(), fmt.Sprintf("kind-config-%s.yaml", cfg.Name))
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
 		return fmt.Errorf("failed to write kind config: %v", err)
 	}
 	defer os.Remove(configPath)
 
-	// Resto do código permanece igual...
-	cmd := exec.Command("kind", "create", "cluster", "--config", configPath)
+	cmd := exec.Command("kind", "create", "cluster", "--config", configPath, "--wait", "120s")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -48,10 +257,16 @@ nodes:
 
 	// Wait for cluster to be ready
 	fmt.Println("⏳ Waiting for cluster to be ready...")
-	time.Sleep(45 * time.Second)
+	client, err := NewClient(KindContext(cfg.Name))
+	if err != nil {
+		return err
+	}
+	if err := client.WaitForNodesReady(context.Background(), 2*time.Minute); err != nil {
+		return err
+	}
 
 	// Verify cluster
-	if err := CheckClusterStatus(); err != nil {
+	if err := CheckClusterStatus(KindContext(cfg.Name)); err != nil {
 		return fmt.Errorf("cluster verification failed: %v", err)
 	}
 
@@ -59,21 +274,90 @@ nodes:
 	return nil
 }
 
-func CheckClusterStatus() error {
-	// Check cluster info
-	cmd := exec.Command("kubectl", "cluster-info")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("kubernetes cluster not accessible: %v", err)
+// DeleteKindCluster remove o cluster Kind, se existir
+func DeleteKindCluster(name string) error {
+	exists, err := KindClusterExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		fmt.Printf("ℹ️ Kind cluster %s does not exist\n", name)
+		return nil
 	}
 
-	// Check nodes
-	cmd = exec.Command("kubectl", "get", "nodes", "-o", "wide")
+	fmt.Printf("🗑️ Deleting Kind cluster %s...\n", name)
+	cmd := exec.Command("kind", "delete", "cluster", "--name", name)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to get nodes: %v", err)
+		return fmt.Errorf("failed to delete kind cluster: %v", err)
 	}
 
+	fmt.Println("✅ Kind cluster deleted")
 	return nil
 }
+
+// RecreateKindCluster apaga e cria novamente o cluster
+func RecreateKindCluster(cfg config.ClusterConfig) error {
+	if err := DeleteKindCluster(cfg.Name); err != nil {
+		return err
+	}
+	return CreateKindCluster(cfg)
+}
+
+// RenderKindConfig retorna o YAML usado para criar o cluster
+func RenderKindConfig(cfg config.ClusterConfig) (string, error) {
+	cluster, err := LoadKindConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+	return renderKindCluster(cluster)
+}
+
+func renderKindCluster(cluster *KindCluster) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cluster); err != nil {
+		return "", fmt.Errorf("failed to render kind config: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// KindContext retorna o contexto do kubeconfig criado pelo Kind para o cluster
+func KindContext(name string) string {
+	return "kind-" + name
+}
+
+// CheckClusterStatus verifica o cluster do contexto informado (vazio usa o
+// contexto atual) e imprime o estado dos nós
+func CheckClusterStatus(kubeContext string) error {
+	client, err := NewClient(kubeContext)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := client.CheckCluster(context.Background())
+	printNodeStatuses(os.Stdout, nodes)
+	return err
+}
+
+func printNodeStatuses(w io.Writer, nodes []NodeStatus) {
+	if len(nodes) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tROLES\tVERSION\tINTERNAL-IP")
+	for _, n := range nodes {
+		status := "Ready"
+		if !n.Ready {
+			status = "NotReady"
+		}
+		roles := strings.Join(n.Roles, ",")
+		if roles == "" {
+			roles = "<none>"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", n.Name, status, roles, n.KubeletVersion, n.InternalIP)
+	}
+	tw.Flush()
+}
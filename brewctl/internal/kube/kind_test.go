@@ -0,0 +1,184 @@
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"brewctl/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func testClusterConfig() config.ClusterConfig {
+	return config.ClusterConfig{
+		Name:          "test-cluster",
+		NodeImage:     "kindest/node:v1.27.3",
+		ControlPlanes: 2,
+		Workers:       1,
+		PortMappings: []config.PortMapping{
+			{ContainerPort: 30800, HostPort: 8000},
+			{ContainerPort: 30017, HostPort: 27017},
+		},
+	}
+}
+
+func TestGenerateKindConfig(t *testing.T) {
+	cluster := generateKindConfig(testClusterConfig())
+
+	require.Len(t, cluster.Nodes, 3)
+	assert.Equal(t, "control-plane", cluster.Nodes[0].Role)
+	assert.Equal(t, "control-plane", cluster.Nodes[1].Role)
+	assert.Equal(t, "worker", cluster.Nodes[2].Role)
+
+	// Apenas o primeiro control-plane recebe o ingress e as portas do host
+	assert.Equal(t, []string{ingressReadyPatch}, cluster.Nodes[0].KubeadmConfigPatches)
+	assert.Equal(t, []KindPortMapping{
+		{ContainerPort: 30800, HostPort: 8000, Protocol: "TCP"},
+		{ContainerPort: 30017, HostPort: 27017, Protocol: "TCP"},
+	}, cluster.Nodes[0].ExtraPortMappings)
+	assert.Empty(t, cluster.Nodes[1].ExtraPortMappings)
+	assert.Empty(t, cluster.Nodes[2].KubeadmConfigPatches)
+	assert.Equal(t, []int{8000, 27017}, cluster.HostPorts())
+}
+
+func TestLoadKindConfigGenerated(t *testing.T) {
+	cfg := testClusterConfig()
+	// O arquivo padrão é opcional: fora do repositório a topologia é gerada
+	cfg.ConfigFile = config.DefaultKindConfigFile
+
+	cluster, err := LoadKindConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "Cluster", cluster.Kind)
+	assert.Equal(t, "kind.x-k8s.io/v1alpha4", cluster.APIVersion)
+	assert.Equal(t, "test-cluster", cluster.Name)
+	require.Len(t, cluster.Nodes, 3)
+	for _, node := range cluster.Nodes {
+		assert.Equal(t, "kindest/node:v1.27.3", node.Image)
+	}
+}
+
+func TestLoadKindConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kind.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+name: ignored
+featureGates:
+  InPlacePodVerticalScaling: true
+runtimeConfig:
+  api/alpha: "false"
+containerdConfigPatches:
+- |-
+  [plugins."io.containerd.grpc.v1.cri".registry]
+kubeadmConfigPatches:
+- |
+  kind: ClusterConfiguration
+nodes:
+- role: control-plane
+  image: kindest/node:v1.29.0
+  kubeadmConfigPatchesJSON6902:
+  - group: kubeadm.k8s.io
+  extraPortMappings:
+  - containerPort: 80
+    hostPort: 8080
+  - containerPort: 30800
+    hostPort: 8000
+  - containerPort: 30017
+    hostPort: 27017
+- role: worker
+`), 0644))
+
+	cfg := testClusterConfig()
+	cfg.ConfigFile = path
+
+	cluster, err := LoadKindConfig(cfg)
+	require.NoError(t, err)
+	// O nome vem sempre do brewctl config; a imagem só quando o nó não declara uma
+	assert.Equal(t, "test-cluster", cluster.Name)
+	require.Len(t, cluster.Nodes, 2)
+	assert.Equal(t, "kindest/node:v1.29.0", cluster.Nodes[0].Image)
+	assert.Equal(t, "kindest/node:v1.27.3", cluster.Nodes[1].Image)
+	// Portas a mais no arquivo são aceitas
+	assert.Equal(t, []int{8080, 8000, 27017}, cluster.HostPorts())
+
+	// Campos que o brewctl não conhece chegam ao kind
+	out, err := yaml.Marshal(cluster)
+	require.NoError(t, err)
+	var written map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out, &written))
+	for _, key := range []string{"featureGates", "runtimeConfig", "containerdConfigPatches", "kubeadmConfigPatches"} {
+		assert.Contains(t, written, key)
+	}
+	assert.Equal(t, map[string]interface{}{"InPlacePodVerticalScaling": true}, written["featureGates"])
+	node := written["nodes"].([]interface{})[0].(map[string]interface{})
+	assert.Contains(t, node, "kubeadmConfigPatchesJSON6902")
+}
+
+func TestLoadKindConfigFilePortMappings(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	// Sem extraPortMappings no arquivo, o primeiro control-plane recebe os port_mappings
+	cfg := testClusterConfig()
+	cfg.ConfigFile = write("no-ports.yaml", "nodes:\n- role: worker\n- role: control-plane\n")
+	cluster, err := LoadKindConfig(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, cluster.Nodes[0].ExtraPortMappings)
+	assert.Equal(t, []KindPortMapping{
+		{ContainerPort: 30800, HostPort: 8000, Protocol: "TCP"},
+		{ContainerPort: 30017, HostPort: 27017, Protocol: "TCP"},
+	}, cluster.Nodes[1].ExtraPortMappings)
+
+	// Portas nas duas fontes que divergem são um erro
+	cfg.ConfigFile = write("other-ports.yaml", `nodes:
+- role: control-plane
+  extraPortMappings:
+  - containerPort: 30800
+    hostPort: 18000
+`)
+	_, err = LoadKindConfig(cfg)
+	assert.ErrorContains(t, err, "cluster.port_mappings 30800->8000, 30017->27017 are not in the extraPortMappings")
+
+	// Sem port_mappings no brewctl config vale o arquivo
+	cfg.PortMappings = nil
+	cluster, err = LoadKindConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []int{18000}, cluster.HostPorts())
+}
+
+func TestLoadKindConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, os.WriteFile(empty, []byte("kind: Cluster\nnodes: []\n"), 0644))
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("nodes: {"), 0644))
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"missing custom file", filepath.Join(dir, "missing.yaml"), "failed to read kind config"},
+		{"invalid yaml", invalid, "failed to parse kind config"},
+		{"no nodes", empty, "declares no nodes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testClusterConfig()
+			cfg.ConfigFile = tt.file
+			_, err := LoadKindConfig(cfg)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestKindContext(t *testing.T) {
+	assert.Equal(t, "kind-brewctl-cluster", KindContext("brewctl-cluster"))
+}
@@ -0,0 +1,115 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"brewctl/internal/config"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LegacyNamespace é o namespace em que versões anteriores do brewctl
+// implantavam todos os componentes
+const LegacyNamespace = "default"
+
+// Tipos de LegacyResource
+const (
+	LegacyHelmRelease = "helm release"
+	LegacyStatefulSet = "statefulset"
+	LegacyDeployment  = "deployment"
+	LegacyService     = "service"
+	LegacySecret      = "secret"
+	LegacyPVC         = "persistentvolumeclaim"
+)
+
+// LegacyResource é um recurso do brewctl que ficou no LegacyNamespace
+type LegacyResource struct {
+	Kind string
+	Name string
+}
+
+func (r LegacyResource) String() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, LegacyNamespace, r.Name)
+}
+
+// FindLegacyResources procura no LegacyNamespace os releases informados e os
+// recursos do MongoDB que o brewctl criava lá. Componentes configurados para o
+// próprio LegacyNamespace são ignorados.
+func (c *Client) FindLegacyResources(ctx context.Context, releases []string, mongo config.MongoDBConfig) ([]LegacyResource, error) {
+	var found []LegacyResource
+
+	// O Helm 3 guarda cada revisão de um release em um Secret com esses labels
+	for _, name := range releases {
+		secrets, err := c.Clientset.CoreV1().Secrets(LegacyNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "owner=helm,name=" + name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Helm releases in %s: %v", LegacyNamespace, err)
+		}
+		if len(secrets.Items) > 0 {
+			found = append(found, LegacyResource{Kind: LegacyHelmRelease, Name: name})
+		}
+	}
+
+	if mongo.Namespace == LegacyNamespace || mongo.Mode == config.MongoDBModeExternal {
+		return found, nil
+	}
+
+	checks := []struct {
+		kind string
+		name string
+		get  func(name string) error
+	}{
+		{LegacyStatefulSet, mongo.ServiceName, func(name string) error {
+			_, err := c.Clientset.AppsV1().StatefulSets(LegacyNamespace).Get(ctx, name, metav1.GetOptions{})
+			return err
+		}},
+		{LegacyDeployment, mongo.ServiceName, func(name string) error {
+			_, err := c.Clientset.AppsV1().Deployments(LegacyNamespace).Get(ctx, name, metav1.GetOptions{})
+			return err
+		}},
+		{LegacyService, mongo.ServiceName, func(name string) error {
+			_, err := c.Clientset.CoreV1().Services(LegacyNamespace).Get(ctx, name, metav1.GetOptions{})
+			return err
+		}},
+		{LegacyService, mongo.HeadlessServiceName(), func(name string) error {
+			_, err := c.Clientset.CoreV1().Services(LegacyNamespace).Get(ctx, name, metav1.GetOptions{})
+			return err
+		}},
+		{LegacySecret, mongo.CredentialsSecretName(), func(name string) error {
+			_, err := c.Clientset.CoreV1().Secrets(LegacyNamespace).Get(ctx, name, metav1.GetOptions{})
+			return err
+		}},
+	}
+	for _, check := range checks {
+		err := check.get(check.name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s %s/%s: %v", check.kind, LegacyNamespace, check.name, err)
+		}
+		found = append(found, LegacyResource{Kind: check.kind, Name: check.name})
+	}
+
+	pvcs, err := c.Clientset.CoreV1().PersistentVolumeClaims(LegacyNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=" + mongo.ServiceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs in %s: %v", LegacyNamespace, err)
+	}
+	for _, pvc := range pvcs.Items {
+		found = append(found, LegacyResource{Kind: LegacyPVC, Name: pvc.Name})
+	}
+	return found, nil
+}
+
+// RemoveLegacyMongoDB remove do LegacyNamespace os recursos do MongoDB criados
+// pelo modo manifest. Com keepData, PVCs e o Secret de credenciais ficam.
+func (c *Client) RemoveLegacyMongoDB(ctx context.Context, mongo config.MongoDBConfig, keepData bool) error {
+	legacy := mongo
+	legacy.Namespace = LegacyNamespace
+	return teardownMongoDB(ctx, c, legacy, keepData)
+}
@@ -0,0 +1,57 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"brewctl/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindLegacyResources(t *testing.T) {
+	ctx := context.Background()
+	meta := func(name string, labels map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: LegacyNamespace, Labels: labels}
+	}
+	client := newTestClient(
+		&corev1.Secret{ObjectMeta: meta("sh.helm.release.v1.grafana.v1", map[string]string{"owner": "helm", "name": "grafana"})},
+		&corev1.Secret{ObjectMeta: meta("sh.helm.release.v1.other.v1", map[string]string{"owner": "helm", "name": "other"})},
+		&appsv1.StatefulSet{ObjectMeta: meta("mongodb", map[string]string{"app": "mongodb"})},
+		&corev1.Service{ObjectMeta: meta("mongodb", map[string]string{"app": "mongodb"})},
+		&corev1.Secret{ObjectMeta: meta("mongodb-credentials", map[string]string{"app": "mongodb"})},
+		&corev1.PersistentVolumeClaim{ObjectMeta: meta("data-mongodb-0", map[string]string{"app": "mongodb"})},
+		// Recursos nos namespaces novos não são legados
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "mongodb", Namespace: "brewctl-data"}},
+	)
+
+	mongo := config.Default().MongoDB
+	found, err := client.FindLegacyResources(ctx, []string{"airbyte", "grafana", "prometheus"}, mongo)
+	require.NoError(t, err)
+	assert.Equal(t, []LegacyResource{
+		{Kind: LegacyHelmRelease, Name: "grafana"},
+		{Kind: LegacyStatefulSet, Name: "mongodb"},
+		{Kind: LegacyService, Name: "mongodb"},
+		{Kind: LegacySecret, Name: "mongodb-credentials"},
+		{Kind: LegacyPVC, Name: "data-mongodb-0"},
+	}, found)
+	assert.Equal(t, "helm release default/grafana", found[0].String())
+
+	// Com o MongoDB configurado no próprio namespace default nada dele é legado
+	mongo.Namespace = LegacyNamespace
+	found, err = client.FindLegacyResources(ctx, nil, mongo)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	require.NoError(t, client.RemoveLegacyMongoDB(ctx, config.Default().MongoDB, true))
+	found, err = client.FindLegacyResources(ctx, nil, config.Default().MongoDB)
+	require.NoError(t, err)
+	assert.Equal(t, []LegacyResource{
+		{Kind: LegacySecret, Name: "mongodb-credentials"},
+		{Kind: LegacyPVC, Name: "data-mongodb-0"},
+	}, found)
+}
@@ -0,0 +1,459 @@
+package kube
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	batchv1ac "k8s.io/client-go/applyconfigurations/batch/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+
+	"brewctl/internal/config"
+)
+
+const (
+	mongoDBNodePort = 30017
+	mongoDBDataPath = "/data/db"
+	mongoDBKeyfile  = "/etc/mongodb-keyfile/keyfile"
+
+	// Chaves do Secret de credenciais, lidas diretamente pela imagem oficial
+	mongoDBUsernameKey = "MONGO_INITDB_ROOT_USERNAME"
+	mongoDBPasswordKey = "MONGO_INITDB_ROOT_PASSWORD"
+	mongoDBKeyfileKey  = "keyfile"
+
+	// Chaves do Secret usadas pelo chart da Bitnami (auth.existingSecret)
+	bitnamiRootPasswordKey = "mongodb-root-password"
+	bitnamiReplicaSetKey   = "mongodb-replica-set-key"
+
+	mongoDBReadyTimeout = 5 * time.Minute
+)
+
+// DeployMongoDB implanta o MongoDB conforme mongodb.mode: manifest aplica o
+// StatefulSet do brewctl e helm instala o chart da Bitnami
+func DeployMongoDB(cfg config.MongoDBConfig) error {
+	if cfg.Mode == config.MongoDBModeExternal {
+		return fmt.Errorf("mongodb.mode is external: nothing to deploy")
+	}
+
+	client, err := NewClient("")
+	if err != nil {
+		return err
+	}
+
+	if cfg.Mode == config.MongoDBModeHelm {
+		fmt.Println("📦 Implantando MongoDB via Helm (Bitnami)...")
+		return implantarMongoDBHelm(context.Background(), client, cfg)
+	}
+
+	fmt.Println("📦 Implantando MongoDB Community Edition...")
+	return implantarMongoDB(context.Background(), client, cfg)
+}
+
+func implantarMongoDB(ctx context.Context, client *Client, cfg config.MongoDBConfig) error {
+	if err := applyMongoDB(ctx, client, cfg); err != nil {
+		return fmt.Errorf("falha na implantação do MongoDB: %v", err)
+	}
+
+	fmt.Println("⏳ Aguardando o MongoDB ficar pronto...")
+	if err := client.WaitForStatefulSetReady(ctx, cfg.Namespace, cfg.ServiceName, mongoDBReadyTimeout); err != nil {
+		return fmt.Errorf("falha na implantação do MongoDB: %v", err)
+	}
+
+	if cfg.Replicas > 1 {
+		fmt.Printf("🔁 Inicializando o replica set %s...\n", cfg.ReplicaSetName)
+		if err := client.Apply(ctx, mongoDBReplicaSetJob(cfg)); err != nil {
+			return fmt.Errorf("falha ao inicializar o replica set: %v", err)
+		}
+		if err := client.WaitForJobComplete(ctx, cfg.Namespace, mongoDBReplicaSetJobName(cfg), mongoDBReadyTimeout); err != nil {
+			return fmt.Errorf("falha ao inicializar o replica set: %v", err)
+		}
+	}
+
+	fmt.Printf("✅ MongoDB implantado com sucesso em %s!\n", cfg.ServiceHost())
+	return nil
+}
+
+// applyMongoDB aplica os recursos do MongoDB sem apagar dados existentes: o
+// Secret só é gerado se ainda não existir e o PVC do StatefulSet é preservado
+func applyMongoDB(ctx context.Context, client *Client, cfg config.MongoDBConfig) error {
+	if err := client.EnsureNamespace(ctx, cfg.Namespace); err != nil {
+		return err
+	}
+
+	// Versões anteriores implantavam um Deployment sem volume, que disputaria
+	// o seletor do service com o StatefulSet. Os que ficaram no namespace
+	// default são removidos pelo cleanup-legacy.
+	if err := client.DeleteDeployment(ctx, cfg.Namespace, cfg.ServiceName); err != nil {
+		return err
+	}
+
+	if err := ensureMongoDBSecret(ctx, client, cfg); err != nil {
+		return err
+	}
+
+	statefulSet, err := mongoDBStatefulSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	return client.Apply(ctx, mongoDBHeadlessService(cfg), mongoDBService(cfg), statefulSet)
+}
+
+// ensureMongoDBSecret gera a credencial root e o keyfile do replica set na
+// primeira implantação. Um Secret existente nunca tem valores sobrescritos;
+// apenas chaves ausentes são completadas a partir das já existentes.
+func ensureMongoDBSecret(ctx context.Context, client *Client, cfg config.MongoDBConfig) error {
+	secrets := client.Clientset.CoreV1().Secrets(cfg.Namespace)
+
+	secret, err := secrets.Get(ctx, cfg.CredentialsSecretName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to read secret %s: %v", cfg.CredentialsSecretName(), err)
+	}
+	exists := err == nil
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cfg.CredentialsSecretName(),
+				Namespace: cfg.Namespace,
+				Labels:    mongoDBLabels(cfg),
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+	}
+
+	changed, err := completeMongoDBSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !exists:
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create secret %s: %v", secret.Name, err)
+		}
+		fmt.Printf("🔑 Generated MongoDB root credentials in secret %s/%s\n", cfg.Namespace, secret.Name)
+	case changed:
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update secret %s: %v", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// completeMongoDBSecret preenche as chaves ausentes do Secret, tanto as lidas
+// pela imagem oficial quanto as esperadas pelo chart da Bitnami
+func completeMongoDBSecret(secret *corev1.Secret) (bool, error) {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	changed := false
+	set := func(key string, value func() (string, error)) error {
+		if len(secret.Data[key]) > 0 {
+			return nil
+		}
+		v, err := value()
+		if err != nil {
+			return err
+		}
+		secret.Data[key] = []byte(v)
+		changed = true
+		return nil
+	}
+	copyOf := func(key string, n int) func() (string, error) {
+		return func() (string, error) {
+			if v := secret.Data[key]; len(v) > 0 {
+				return string(v), nil
+			}
+			return randomString(n)
+		}
+	}
+
+	steps := []struct {
+		key   string
+		value func() (string, error)
+	}{
+		{mongoDBUsernameKey, func() (string, error) { return config.MongoDBRootUser, nil }},
+		{mongoDBPasswordKey, copyOf(bitnamiRootPasswordKey, 24)},
+		{mongoDBKeyfileKey, copyOf(bitnamiReplicaSetKey, 48)},
+		{bitnamiRootPasswordKey, copyOf(mongoDBPasswordKey, 24)},
+		{bitnamiReplicaSetKey, copyOf(mongoDBKeyfileKey, 48)},
+	}
+	for _, step := range steps {
+		if err := set(step.key, step.value); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// randomString gera n bytes aleatórios codificados em base64 sem padding
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random secret: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// MongoDBCredentials lê a credencial root gerada pelo deploy
+func MongoDBCredentials(cfg config.MongoDBConfig) (string, string, error) {
+	client, err := NewClient("")
+	if err != nil {
+		return "", "", err
+	}
+	return mongoDBCredentials(context.Background(), client, cfg)
+}
+
+func mongoDBCredentials(ctx context.Context, client *Client, cfg config.MongoDBConfig) (string, string, error) {
+	secret, err := client.Clientset.CoreV1().Secrets(cfg.Namespace).Get(ctx, cfg.CredentialsSecretName(), metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read MongoDB credentials from secret %s/%s: %v", cfg.Namespace, cfg.CredentialsSecretName(), err)
+	}
+	return string(secret.Data[mongoDBUsernameKey]), string(secret.Data[mongoDBPasswordKey]), nil
+}
+
+// mongoDBLabels são os labels usados no seletor do StatefulSet e dos services
+func mongoDBLabels(cfg config.MongoDBConfig) map[string]string {
+	return map[string]string{"app": cfg.ServiceName}
+}
+
+// mongoDBSecretEnv expõe uma chave do Secret de credenciais como variável de ambiente
+func mongoDBSecretEnv(cfg config.MongoDBConfig, key string) *corev1ac.EnvVarApplyConfiguration {
+	return corev1ac.EnvVar().WithName(key).WithValueFrom(corev1ac.EnvVarSource().
+		WithSecretKeyRef(corev1ac.SecretKeySelector().WithName(cfg.CredentialsSecretName()).WithKey(key)))
+}
+
+// mongoDBResources converte os requests e limits configurados
+func mongoDBResources(cfg config.MongoDBConfig) (*corev1ac.ResourceRequirementsApplyConfiguration, error) {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+
+	r := cfg.Resources
+	for _, q := range []struct {
+		field string
+		value string
+		list  corev1.ResourceList
+		name  corev1.ResourceName
+	}{
+		{"cpu_request", r.CPURequest, requests, corev1.ResourceCPU},
+		{"memory_request", r.MemoryRequest, requests, corev1.ResourceMemory},
+		{"cpu_limit", r.CPULimit, limits, corev1.ResourceCPU},
+		{"memory_limit", r.MemoryLimit, limits, corev1.ResourceMemory},
+	} {
+		if q.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return nil, fmt.Errorf("mongodb.resources.%s: invalid quantity %q", q.field, q.value)
+		}
+		q.list[q.name] = quantity
+	}
+
+	return corev1ac.ResourceRequirements().WithRequests(requests).WithLimits(limits), nil
+}
+
+// mongoDBStatefulSet monta o StatefulSet do MongoDB com volume persistente
+func mongoDBStatefulSet(cfg config.MongoDBConfig) (*appsv1ac.StatefulSetApplyConfiguration, error) {
+	labels := mongoDBLabels(cfg)
+
+	storage, err := resource.ParseQuantity(cfg.StorageSize)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb.storage_size: invalid quantity %q", cfg.StorageSize)
+	}
+	resources, err := mongoDBResources(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	container := corev1ac.Container().
+		WithName("mongodb").
+		WithImage(cfg.Image).
+		WithPorts(corev1ac.ContainerPort().WithName("mongodb").WithContainerPort(27017)).
+		WithEnv(
+			corev1ac.EnvVar().WithName("MONGO_INITDB_DATABASE").WithValue(cfg.Database),
+			mongoDBSecretEnv(cfg, mongoDBUsernameKey),
+			mongoDBSecretEnv(cfg, mongoDBPasswordKey),
+		).
+		WithVolumeMounts(corev1ac.VolumeMount().WithName("data").WithMountPath(mongoDBDataPath)).
+		WithResources(resources).
+		WithReadinessProbe(corev1ac.Probe().
+			WithExec(corev1ac.ExecAction().WithCommand("mongosh", "--quiet", "--eval", "db.adminCommand('ping')")).
+			WithInitialDelaySeconds(10).
+			WithPeriodSeconds(10).
+			WithTimeoutSeconds(5)).
+		WithLivenessProbe(corev1ac.Probe().
+			WithTCPSocket(corev1ac.TCPSocketAction().WithPort(intstr.FromString("mongodb"))).
+			WithInitialDelaySeconds(30).
+			WithPeriodSeconds(20).
+			WithFailureThreshold(6))
+
+	podSpec := corev1ac.PodSpec()
+
+	if cfg.Replicas > 1 {
+		// O mongod exige que o keyfile pertença ao usuário mongodb (999) com
+		// permissão 400, o que um volume de Secret não garante
+		container.
+			WithArgs("--replSet", cfg.ReplicaSetName, "--keyFile", mongoDBKeyfile, "--bind_ip_all").
+			WithVolumeMounts(corev1ac.VolumeMount().WithName("keyfile").WithMountPath("/etc/mongodb-keyfile"))
+
+		podSpec.
+			WithInitContainers(corev1ac.Container().
+				WithName("keyfile").
+				WithImage(cfg.Image).
+				WithCommand("sh", "-c", "cp /secret/keyfile "+mongoDBKeyfile+" && chmod 400 "+mongoDBKeyfile+" && chown 999:999 "+mongoDBKeyfile).
+				WithVolumeMounts(
+					corev1ac.VolumeMount().WithName("keyfile-secret").WithMountPath("/secret"),
+					corev1ac.VolumeMount().WithName("keyfile").WithMountPath("/etc/mongodb-keyfile"),
+				)).
+			WithVolumes(
+				corev1ac.Volume().WithName("keyfile-secret").WithSecret(corev1ac.SecretVolumeSource().
+					WithSecretName(cfg.CredentialsSecretName()).
+					WithItems(corev1ac.KeyToPath().WithKey(mongoDBKeyfileKey).WithPath("keyfile"))),
+				corev1ac.Volume().WithName("keyfile").WithEmptyDir(corev1ac.EmptyDirVolumeSource()),
+			)
+	}
+	podSpec.WithContainers(container)
+
+	claim := corev1ac.PersistentVolumeClaimSpec().
+		WithAccessModes(corev1.ReadWriteOnce).
+		WithResources(corev1ac.VolumeResourceRequirements().
+			WithRequests(corev1.ResourceList{corev1.ResourceStorage: storage}))
+	if cfg.StorageClass != "" {
+		claim.WithStorageClassName(cfg.StorageClass)
+	}
+
+	return appsv1ac.StatefulSet(cfg.ServiceName, cfg.Namespace).
+		WithLabels(labels).
+		WithSpec(appsv1ac.StatefulSetSpec().
+			WithServiceName(cfg.HeadlessServiceName()).
+			WithReplicas(int32(cfg.Replicas)).
+			WithPodManagementPolicy("Parallel").
+			WithSelector(metav1ac.LabelSelector().WithMatchLabels(labels)).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labels).
+				// Usado pelos scripts de health check
+				WithLabels(map[string]string{"app.kubernetes.io/name": "mongodb"}).
+				WithSpec(podSpec)).
+			WithVolumeClaimTemplates(corev1ac.PersistentVolumeClaim("data", "").
+				WithLabels(labels).
+				WithSpec(claim))), nil
+}
+
+// mongoDBHeadlessService dá a cada membro do StatefulSet um DNS estável
+func mongoDBHeadlessService(cfg config.MongoDBConfig) *corev1ac.ServiceApplyConfiguration {
+	return corev1ac.Service(cfg.HeadlessServiceName(), cfg.Namespace).
+		WithLabels(mongoDBLabels(cfg)).
+		WithSpec(corev1ac.ServiceSpec().
+			WithClusterIP("None").
+			WithPublishNotReadyAddresses(true).
+			WithSelector(mongoDBLabels(cfg)).
+			WithPorts(corev1ac.ServicePort().
+				WithName("mongodb").
+				WithPort(27017).
+				WithTargetPort(intstr.FromString("mongodb"))))
+}
+
+// mongoDBService monta o Service NodePort que expõe o MongoDB. Com replica
+// set, ele aponta apenas para o membro 0, que tem prioridade para ser primário.
+func mongoDBService(cfg config.MongoDBConfig) *corev1ac.ServiceApplyConfiguration {
+	selector := mongoDBLabels(cfg)
+	if cfg.Replicas > 1 {
+		selector["statefulset.kubernetes.io/pod-name"] = cfg.ServiceName + "-0"
+	}
+
+	return corev1ac.Service(cfg.ServiceName, cfg.Namespace).
+		WithLabels(mongoDBLabels(cfg)).
+		WithSpec(corev1ac.ServiceSpec().
+			WithType(corev1.ServiceTypeNodePort).
+			WithSelector(selector).
+			WithPorts(corev1ac.ServicePort().
+				WithName("mongodb").
+				WithPort(int32(cfg.Port)).
+				WithTargetPort(intstr.FromString("mongodb")).
+				WithNodePort(mongoDBNodePort)))
+}
+
+func mongoDBReplicaSetJobName(cfg config.MongoDBConfig) string {
+	return cfg.ServiceName + "-rs-init"
+}
+
+// mongoDBReplicaSetScript inicia o replica set apenas se ainda não houver
+// configuração e aguarda a eleição do primário
+func mongoDBReplicaSetScript(cfg config.MongoDBConfig) string {
+	members := make([]string, 0, cfg.Replicas)
+	for i, host := range cfg.MemberHosts() {
+		priority := 1
+		if i == 0 {
+			priority = 2
+		}
+		members = append(members, fmt.Sprintf("{_id: %d, host: %q, priority: %d}", i, host, priority))
+	}
+
+	return fmt.Sprintf(`try { rs.status() } catch (e) {
+  rs.initiate({_id: %q, members: [%s]})
+}
+while (!db.hello().isWritablePrimary) { sleep(1000) }`, cfg.ReplicaSetName, strings.Join(members, ", "))
+}
+
+// mongoDBReplicaSetJob monta o Job idempotente que executa rs.initiate no membro 0
+func mongoDBReplicaSetJob(cfg config.MongoDBConfig) *batchv1ac.JobApplyConfiguration {
+	return batchv1ac.Job(mongoDBReplicaSetJobName(cfg), cfg.Namespace).
+		WithLabels(mongoDBLabels(cfg)).
+		WithSpec(batchv1ac.JobSpec().
+			WithBackoffLimit(10).
+			WithTTLSecondsAfterFinished(300).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithSpec(corev1ac.PodSpec().
+					WithRestartPolicy(corev1.RestartPolicyOnFailure).
+					WithContainers(corev1ac.Container().
+						WithName("rs-init").
+						WithImage(cfg.Image).
+						WithEnv(mongoDBSecretEnv(cfg, mongoDBUsernameKey), mongoDBSecretEnv(cfg, mongoDBPasswordKey)).
+						WithCommand("mongosh",
+							"--host", cfg.MemberHosts()[0],
+							"--username", "$("+mongoDBUsernameKey+")",
+							"--password", "$("+mongoDBPasswordKey+")",
+							"--authenticationDatabase", "admin",
+							"--quiet",
+							"--eval", mongoDBReplicaSetScript(cfg))))))
+}
+
+// MongoDBImages retorna as imagens usadas pelos manifests do modo manifest
+// (StatefulSet, init container do keyfile e Job do replica set)
+func MongoDBImages(cfg config.MongoDBConfig) []string {
+	if cfg.Mode != config.MongoDBModeManifest {
+		return nil
+	}
+	return []string{cfg.Image}
+}
+
+// CheckMongoDBService confirma que o service configurado para o destination
+// do Airbyte existe no cluster
+func CheckMongoDBService(cfg config.MongoDBConfig) error {
+	client, err := NewClient("")
+	if err != nil {
+		return err
+	}
+	return checkMongoDBService(context.Background(), client, cfg)
+}
+
+func checkMongoDBService(ctx context.Context, client *Client, cfg config.MongoDBConfig) error {
+	_, err := client.Clientset.CoreV1().Services(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("MongoDB service %s not found in namespace %s: %v",
+			cfg.ServiceName, cfg.Namespace, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"brewctl/internal/config"
+	"brewctl/internal/kube/helm"
+)
+
+// MongoDBChart é o chart da Bitnami usado no modo helm, com versão fixada
+var MongoDBChart = helm.Chart{
+	Repo:    "bitnami",
+	RepoURL: "https://charts.bitnami.com/bitnami",
+	Name:    "mongodb",
+	Version: "14.4.0",
+}
+
+// MongoDBRelease descreve o release do chart da Bitnami. Os values garantem
+// que o release concorde com a configuração do brewctl (service, credenciais,
+// réplicas) independentemente do values file.
+func MongoDBRelease(cfg config.MongoDBConfig) helm.Release {
+	values := map[string]interface{}{
+		// Faz o service se chamar ServiceName, como no modo manifest
+		"fullnameOverride":          cfg.ServiceName,
+		"auth.enabled":              true,
+		"auth.rootUser":             config.MongoDBRootUser,
+		"auth.existingSecret":       cfg.CredentialsSecretName(),
+		"service.ports.mongodb":     cfg.Port,
+		"service.nodePorts.mongodb": mongoDBNodePort,
+		"persistence.size":          cfg.StorageSize,
+		// Mesmo label do modo manifest, usado para selecionar os pods
+		"podLabels.app": cfg.ServiceName,
+	}
+	for key, value := range map[string]string{
+		"resources.requests.cpu":    cfg.Resources.CPURequest,
+		"resources.requests.memory": cfg.Resources.MemoryRequest,
+		"resources.limits.cpu":      cfg.Resources.CPULimit,
+		"resources.limits.memory":   cfg.Resources.MemoryLimit,
+	} {
+		if value != "" {
+			values[key] = value
+		}
+	}
+	if cfg.StorageClass != "" {
+		values["global.storageClass"] = cfg.StorageClass
+	}
+	if cfg.Replicas > 1 {
+		values["architecture"] = "replicaset"
+		values["replicaCount"] = cfg.Replicas
+		values["replicaSetName"] = cfg.ReplicaSetName
+	}
+
+	return helm.Release{
+		Name:      cfg.ServiceName,
+		Namespace: cfg.Namespace,
+		Chart:     MongoDBChart,
+		Values:    values,
+		Timeout:   10 * time.Minute,
+	}
+}
+
+// mongoDBValuesFile retorna o values file a usar; o arquivo padrão é opcional
+// para que o brewctl funcione fora da raiz do repositório
+func mongoDBValuesFile(cfg config.MongoDBConfig) (string, error) {
+	if cfg.ValuesFile == "" {
+		return "", nil
+	}
+	if _, err := os.Stat(cfg.ValuesFile); err != nil {
+		if os.IsNotExist(err) && cfg.ValuesFile == config.DefaultMongoDBValuesFile {
+			fmt.Printf("ℹ️ %s not found, using chart defaults\n", cfg.ValuesFile)
+			return "", nil
+		}
+		return "", fmt.Errorf("mongodb.values_file: %v", err)
+	}
+	return cfg.ValuesFile, nil
+}
+
+func implantarMongoDBHelm(ctx context.Context, client *Client, cfg config.MongoDBConfig) error {
+	release := MongoDBRelease(cfg)
+
+	valuesFile, err := mongoDBValuesFile(cfg)
+	if err != nil {
+		return err
+	}
+	if valuesFile != "" {
+		release.ValuesFiles = []string{valuesFile}
+	}
+
+	if err := client.EnsureNamespace(ctx, cfg.Namespace); err != nil {
+		return err
+	}
+
+	// O chart lê a senha root e o keyfile do mesmo Secret usado no modo manifest
+	if err := ensureMongoDBSecret(ctx, client, cfg); err != nil {
+		return err
+	}
+
+	if err := helm.NewManager().UpgradeInstall(ctx, release); err != nil {
+		return fmt.Errorf("falha na implantação do MongoDB via Helm: %v", err)
+	}
+
+	if cfg.Replicas > 1 {
+		fmt.Println("ℹ️ The Bitnami replicaset architecture does not expose a NodePort; use the headless service from inside the cluster")
+	}
+
+	fmt.Printf("✅ MongoDB implantado com sucesso em %s!\n", cfg.ServiceHost())
+	return nil
+}
@@ -0,0 +1,171 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"brewctl/internal/config"
+)
+
+func TestApplyMongoDBStatefulSet(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().MongoDB
+
+	// Deployment legado de versões anteriores deve ser substituído
+	legacy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: cfg.ServiceName, Namespace: cfg.Namespace}}
+	client := newTestClient(legacy)
+
+	require.NoError(t, applyMongoDB(ctx, client, cfg))
+
+	_, err := client.Clientset.AppsV1().Deployments(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+	assert.Error(t, err)
+
+	sts, err := client.Clientset.AppsV1().StatefulSets(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *sts.Spec.Replicas)
+	assert.Equal(t, cfg.HeadlessServiceName(), sts.Spec.ServiceName)
+	require.Len(t, sts.Spec.VolumeClaimTemplates, 1)
+	assert.Equal(t, "2Gi", sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String())
+
+	container := sts.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "mongo:6.0.5", container.Image)
+	assert.NotNil(t, container.ReadinessProbe)
+	assert.NotNil(t, container.LivenessProbe)
+	assert.Equal(t, "1Gi", container.Resources.Limits.Memory().String())
+	assert.Empty(t, container.Args)
+	assert.Empty(t, sts.Spec.Template.Spec.InitContainers)
+
+	headless, err := client.Clientset.CoreV1().Services(cfg.Namespace).Get(ctx, cfg.HeadlessServiceName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ClusterIPNone, headless.Spec.ClusterIP)
+
+	svc, err := client.Clientset.CoreV1().Services(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ServiceTypeNodePort, svc.Spec.Type)
+	assert.Equal(t, int32(mongoDBNodePort), svc.Spec.Ports[0].NodePort)
+
+	assert.NoError(t, checkMongoDBService(ctx, client, cfg))
+}
+
+func TestApplyMongoDBKeepsCredentials(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().MongoDB
+	client := newTestClient()
+
+	require.NoError(t, applyMongoDB(ctx, client, cfg))
+	user, password, err := mongoDBCredentials(ctx, client, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, config.MongoDBRootUser, user)
+	assert.NotEmpty(t, password)
+
+	// Reaplicar não pode trocar a senha de um banco já inicializado
+	require.NoError(t, applyMongoDB(ctx, client, cfg))
+	_, again, err := mongoDBCredentials(ctx, client, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, password, again)
+}
+
+func TestApplyMongoDBReplicaSet(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().MongoDB
+	cfg.Replicas = 3
+	client := newTestClient()
+
+	require.NoError(t, applyMongoDB(ctx, client, cfg))
+
+	sts, err := client.Clientset.AppsV1().StatefulSets(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *sts.Spec.Replicas)
+	assert.Contains(t, sts.Spec.Template.Spec.Containers[0].Args, "--replSet")
+	assert.Len(t, sts.Spec.Template.Spec.InitContainers, 1)
+
+	svc, err := client.Clientset.CoreV1().Services(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "mongodb-0", svc.Spec.Selector["statefulset.kubernetes.io/pod-name"])
+
+	script := mongoDBReplicaSetScript(cfg)
+	assert.Contains(t, script, `"mongodb-2.mongodb-headless.brewctl-data.svc.cluster.local:27017"`)
+	assert.Contains(t, script, `rs.initiate({_id: "rs0"`)
+}
+
+func TestMongoDBStatefulSetInvalidResources(t *testing.T) {
+	cfg := config.Default().MongoDB
+	cfg.Resources.MemoryLimit = "lots"
+
+	_, err := mongoDBStatefulSet(cfg)
+	assert.ErrorContains(t, err, "memory_limit")
+}
+
+func TestCompleteMongoDBSecretKeepsExistingPassword(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{
+		mongoDBUsernameKey: []byte("root"),
+		mongoDBPasswordKey: []byte("existing"),
+	}}
+
+	changed, err := completeMongoDBSecret(secret)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "existing", string(secret.Data[mongoDBPasswordKey]))
+	assert.Equal(t, "existing", string(secret.Data[bitnamiRootPasswordKey]))
+	assert.Equal(t, secret.Data[mongoDBKeyfileKey], secret.Data[bitnamiReplicaSetKey])
+
+	changed, err = completeMongoDBSecret(secret)
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestMongoDBRelease(t *testing.T) {
+	cfg := config.Default().MongoDB
+	cfg.Replicas = 3
+
+	release := MongoDBRelease(cfg)
+	assert.Equal(t, "mongodb", release.Name)
+	assert.Equal(t, "14.4.0", release.Chart.Version)
+	assert.Equal(t, "mongodb-credentials", release.Values["auth.existingSecret"])
+	assert.Equal(t, mongoDBNodePort, release.Values["service.nodePorts.mongodb"])
+	assert.Equal(t, "replicaset", release.Values["architecture"])
+	assert.Equal(t, "1Gi", release.Values["resources.limits.memory"])
+
+	release = MongoDBRelease(config.Default().MongoDB)
+	assert.NotContains(t, release.Values, "architecture")
+}
+
+func TestTeardownMongoDB(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().MongoDB
+
+	for _, keepData := range []bool{false, true} {
+		pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name: "data-mongodb-0", Namespace: cfg.Namespace, Labels: mongoDBLabels(cfg),
+		}}
+		other := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-volume-db", Namespace: cfg.Namespace}}
+		client := newTestClient(pvc, other)
+		require.NoError(t, applyMongoDB(ctx, client, cfg))
+
+		require.NoError(t, teardownMongoDB(ctx, client, cfg, keepData))
+
+		_, err := client.Clientset.AppsV1().StatefulSets(cfg.Namespace).Get(ctx, cfg.ServiceName, metav1.GetOptions{})
+		assert.Error(t, err)
+		services, err := client.Clientset.CoreV1().Services(cfg.Namespace).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, services.Items)
+
+		pvcs, err := client.Clientset.CoreV1().PersistentVolumeClaims(cfg.Namespace).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		_, secretErr := client.Clientset.CoreV1().Secrets(cfg.Namespace).Get(ctx, cfg.CredentialsSecretName(), metav1.GetOptions{})
+		if keepData {
+			assert.Len(t, pvcs.Items, 2)
+			assert.NoError(t, secretErr)
+		} else {
+			require.Len(t, pvcs.Items, 1)
+			assert.Equal(t, "airbyte-volume-db", pvcs.Items[0].Name)
+			assert.Error(t, secretErr)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+
+	"brewctl/internal/config"
+)
+
+// isolationPolicyName é a NetworkPolicy aplicada em cada namespace do brewctl
+const isolationPolicyName = "brewctl-isolation"
+
+// ApplyNetworkPolicies isola os namespaces do brewctl quando
+// cluster.network_policies está ativo e remove as políticas caso contrário
+func ApplyNetworkPolicies(cfg *config.Config) error {
+	client, err := NewClient("")
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if !cfg.Cluster.NetworkPolicies {
+		for _, ns := range cfg.Namespaces() {
+			err := client.Clientset.NetworkingV1().NetworkPolicies(ns).Delete(ctx, isolationPolicyName, metav1.DeleteOptions{})
+			if err := ignoreNotFound(err); err != nil {
+				return fmt.Errorf("failed to delete network policy in %s: %v", ns, err)
+			}
+		}
+		return nil
+	}
+
+	fmt.Println("🔒 Applying network policies...")
+	return applyNetworkPolicies(ctx, client, cfg.Namespaces())
+}
+
+func applyNetworkPolicies(ctx context.Context, client *Client, namespaces []string) error {
+	nodes, err := client.NodeStatuses(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range namespaces {
+		if err := client.EnsureNamespace(ctx, ns); err != nil {
+			return err
+		}
+		if err := client.Apply(ctx, isolationPolicy(ns, nodes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isolationPolicy aceita tráfego apenas dos namespaces do brewctl e dos nós,
+// por onde chegam as conexões dos NodePorts mapeados no host
+func isolationPolicy(namespace string, nodes []NodeStatus) *networkingv1ac.NetworkPolicyApplyConfiguration {
+	peers := []*networkingv1ac.NetworkPolicyPeerApplyConfiguration{
+		networkingv1ac.NetworkPolicyPeer().WithNamespaceSelector(
+			metav1ac.LabelSelector().WithMatchLabels(map[string]string{PartOfLabel: FieldManager})),
+	}
+	for _, node := range nodes {
+		if node.InternalIP != "" {
+			peers = append(peers, networkingv1ac.NetworkPolicyPeer().WithIPBlock(
+				networkingv1ac.IPBlock().WithCIDR(node.InternalIP+"/32")))
+		}
+	}
+
+	return networkingv1ac.NetworkPolicy(isolationPolicyName, namespace).
+		WithLabels(map[string]string{PartOfLabel: FieldManager}).
+		WithSpec(networkingv1ac.NetworkPolicySpec().
+			WithPodSelector(metav1ac.LabelSelector()).
+			WithPolicyTypes(networkingv1.PolicyTypeIngress).
+			WithIngress(networkingv1ac.NetworkPolicyIngressRule().WithFrom(peers...)))
+}
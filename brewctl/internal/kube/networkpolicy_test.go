@@ -0,0 +1,41 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"brewctl/internal/config"
+)
+
+func TestApplyNetworkPolicies(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "brewctl-cluster-control-plane"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "172.18.0.2"}},
+		},
+	}
+	client := newTestClient(node)
+	namespaces := config.Default().Namespaces()
+
+	require.NoError(t, applyNetworkPolicies(ctx, client, namespaces))
+
+	for _, name := range namespaces {
+		ns, err := client.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, FieldManager, ns.Labels[PartOfLabel])
+
+		policy, err := client.Clientset.NetworkingV1().NetworkPolicies(name).Get(ctx, isolationPolicyName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Len(t, policy.Spec.Ingress, 1)
+		peers := policy.Spec.Ingress[0].From
+		require.Len(t, peers, 2)
+		assert.Equal(t, FieldManager, peers[0].NamespaceSelector.MatchLabels[PartOfLabel])
+		assert.Equal(t, "172.18.0.2/32", peers[1].IPBlock.CIDR)
+	}
+}
@@ -0,0 +1,296 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"brewctl/internal/config"
+)
+
+// ForwardTarget é um service exposto na máquina local via port-forward
+type ForwardTarget struct {
+	Name        string
+	Namespace   string
+	Service     string
+	ServicePort int
+	LocalPort   int
+	// Scheme monta a URL exibida ao usuário (http, mongodb)
+	Scheme string
+}
+
+// URL retorna o endereço local do target
+func (t ForwardTarget) URL() string {
+	return t.urlOn(t.LocalPort)
+}
+
+func (t ForwardTarget) urlOn(port int) string {
+	return fmt.Sprintf("%s://localhost:%d", t.Scheme, port)
+}
+
+// WithPortOffset desloca a porta local de cada target
+func WithPortOffset(targets []ForwardTarget, offset int) []ForwardTarget {
+	shifted := make([]ForwardTarget, len(targets))
+	for i, t := range targets {
+		t.LocalPort += offset
+		shifted[i] = t
+	}
+	return shifted
+}
+
+// ForwardTargets retorna os targets conhecidos pelo brewctl, na ordem de exibição.
+// O MongoDB no modo external não tem service no cluster e fica de fora. As
+// portas locais são as preferidas: se estiverem ocupadas (por exemplo pelos
+// mapeamentos de porta do Kind), o PortForwarder usa uma porta livre.
+func ForwardTargets(cfg *config.Config) []ForwardTarget {
+	targets := []ForwardTarget{
+		// O webapp faz proxy de /api para o server, então atende UI e API
+		{Name: "airbyte", Namespace: cfg.Airbyte.Namespace, Service: "airbyte-airbyte-webapp-svc", ServicePort: 80, LocalPort: 8000, Scheme: "http"},
+		{Name: "grafana", Namespace: cfg.Monitoring.Namespace, Service: "grafana", ServicePort: 80, LocalPort: 3000, Scheme: "http"},
+		{Name: "prometheus", Namespace: cfg.Monitoring.Namespace, Service: "prometheus-server", ServicePort: 80, LocalPort: 9090, Scheme: "http"},
+	}
+	if cfg.MongoDB.Mode != config.MongoDBModeExternal {
+		targets = append(targets, ForwardTarget{
+			Name: "mongodb", Namespace: cfg.MongoDB.Namespace, Service: cfg.MongoDB.ServiceName,
+			ServicePort: cfg.MongoDB.Port, LocalPort: 27017, Scheme: "mongodb",
+		})
+	}
+	return targets
+}
+
+// SelectForwardTargets filtra os targets pelos nomes informados; "all" ou
+// nenhum nome seleciona todos
+func SelectForwardTargets(targets []ForwardTarget, names []string) ([]ForwardTarget, error) {
+	if len(names) == 0 || (len(names) == 1 && names[0] == "all") {
+		return targets, nil
+	}
+
+	byName := map[string]ForwardTarget{}
+	var known []string
+	for _, t := range targets {
+		byName[t.Name] = t
+		known = append(known, t.Name)
+	}
+
+	var selected []ForwardTarget
+	for _, name := range names {
+		t, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown port-forward target %q (expected all, %s)", name, strings.Join(known, ", "))
+		}
+		selected = append(selected, t)
+	}
+	return selected, nil
+}
+
+// PortForwarder mantém os port-forwards abertos, reconectando quando o pod
+// reinicia ou a conexão com o API server cai
+type PortForwarder struct {
+	Client *Client
+	Out    io.Writer
+	// RetryInterval é a espera entre tentativas de reconexão
+	RetryInterval time.Duration
+}
+
+// NewPortForwarder cria o PortForwarder para o client informado
+func NewPortForwarder(client *Client, out io.Writer) *PortForwarder {
+	return &PortForwarder{Client: client, Out: out, RetryInterval: 3 * time.Second}
+}
+
+// Run encaminha os targets até que ctx seja cancelado
+func (f *PortForwarder) Run(ctx context.Context, targets []ForwardTarget) error {
+	if f.Client.RESTConfig == nil {
+		return fmt.Errorf("port-forward requires a client created from a kubeconfig")
+	}
+
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func(t ForwardTarget) {
+			defer wg.Done()
+			f.forwardLoop(ctx, t)
+		}(t)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (f *PortForwarder) forwardLoop(ctx context.Context, t ForwardTarget) {
+	// onReady roda na goroutine do port-forward
+	var connected atomic.Bool
+	// local guarda a porta obtida na primeira conexão, reaproveitada nas
+	// reconexões para que a URL exibida continue valendo
+	var local atomic.Int64
+	local.Store(int64(t.LocalPort))
+	announced := false
+	for {
+		port := int(local.Load())
+		if !localPortAvailable(port) {
+			if !announced {
+				fmt.Fprintf(f.Out, "ℹ️ %s: local port %d is in use, picking a free one\n", t.Name, port)
+				announced = true
+			}
+			port = 0
+		}
+
+		err := f.forward(ctx, t, port, func(actual int) {
+			local.Store(int64(actual))
+			if connected.Swap(true) {
+				fmt.Fprintf(f.Out, "🔁 %s reconnected: %s\n", t.Name, t.urlOn(actual))
+			} else {
+				fmt.Fprintf(f.Out, "🌐 %s: %s\n", t.Name, t.urlOn(actual))
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(f.Out, "⚠️ %s: %v (retrying in %s)\n", t.Name, err, f.RetryInterval)
+		} else {
+			fmt.Fprintf(f.Out, "⚠️ %s: connection closed (retrying in %s)\n", t.Name, f.RetryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.RetryInterval):
+		}
+	}
+}
+
+// localPortAvailable indica se a porta pode ser aberta em localhost
+func localPortAvailable(port int) bool {
+	if port == 0 {
+		return true
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// forward abre um port-forward para um pod do service na porta local
+// informada (0 escolhe uma livre) e bloqueia até a conexão cair ou ctx ser
+// cancelado. onReady recebe a porta local efetivamente usada.
+func (f *PortForwarder) forward(ctx context.Context, t ForwardTarget, localPort int, onReady func(localPort int)) error {
+	pod, port, err := f.Client.ResolveServicePod(ctx, t.Namespace, t.Service, t.ServicePort)
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(f.Client.RESTConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create port-forward transport: %v", err)
+	}
+	url := f.Client.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(t.Namespace).Name(pod).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	// Cancelado quando ForwardPorts retorna, encerrando a goroutine abaixo
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	pf, err := portforward.NewOnAddresses(dialer, []string{"localhost"},
+		[]string{fmt.Sprintf("%d:%d", localPort, port)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return fmt.Errorf("failed to create port-forward: %v", err)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-ready:
+			actual := localPort
+			if ports, err := pf.GetPorts(); err == nil && len(ports) > 0 {
+				actual = int(ports[0].Local)
+			}
+			onReady(actual)
+			<-ctx.Done()
+		}
+		close(stop)
+	}()
+
+	return pf.ForwardPorts()
+}
+
+// ResolveServicePod escolhe um pod pronto do service e a porta do container
+// correspondente à porta do service
+func (c *Client) ResolveServicePod(ctx context.Context, namespace, service string, servicePort int) (string, int, error) {
+	svc, err := c.Clientset.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("service %s/%s: %v", namespace, service, err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service %s/%s has no selector", namespace, service)
+	}
+
+	var target *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if int(svc.Spec.Ports[i].Port) == servicePort {
+			target = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if target == nil {
+		return "", 0, fmt.Errorf("service %s/%s does not expose port %d", namespace, service, servicePort)
+	}
+
+	pods, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to list pods of service %s/%s: %v", namespace, service, err)
+	}
+	// Ordena por nome para preferir o membro 0 de um StatefulSet
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || !podReady(pod) {
+			continue
+		}
+		port, err := containerPort(pod, target)
+		if err != nil {
+			return "", 0, err
+		}
+		return pod.Name, port, nil
+	}
+	return "", 0, fmt.Errorf("no ready pod for service %s/%s", namespace, service)
+}
+
+// containerPort traduz o targetPort do service (número ou nome) para a porta do pod
+func containerPort(pod *corev1.Pod, port *corev1.ServicePort) (int, error) {
+	if port.TargetPort.IntValue() != 0 {
+		return port.TargetPort.IntValue(), nil
+	}
+
+	name := port.TargetPort.String()
+	if name == "" || name == "0" {
+		return int(port.Port), nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == name {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s has no container port named %s", pod.Name, name)
+}
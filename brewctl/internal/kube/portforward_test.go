@@ -0,0 +1,155 @@
+package kube
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"brewctl/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func targetNames(targets []ForwardTarget) []string {
+	var names []string
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestForwardTargets(t *testing.T) {
+	cfg := config.Default()
+	targets := ForwardTargets(cfg)
+	assert.Equal(t, []string{"airbyte", "grafana", "prometheus", "mongodb"}, targetNames(targets))
+	assert.Equal(t, "mongodb://localhost:27017", targets[3].URL())
+	assert.Equal(t, "brewctl-data", targets[3].Namespace)
+
+	cfg.MongoDB.Mode = config.MongoDBModeExternal
+	assert.Equal(t, []string{"airbyte", "grafana", "prometheus"}, targetNames(ForwardTargets(cfg)))
+}
+
+func TestSelectForwardTargets(t *testing.T) {
+	targets := ForwardTargets(config.Default())
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{"no args", nil, []string{"airbyte", "grafana", "prometheus", "mongodb"}, ""},
+		{"all", []string{"all"}, []string{"airbyte", "grafana", "prometheus", "mongodb"}, ""},
+		{"in the given order", []string{"mongodb", "grafana"}, []string{"mongodb", "grafana"}, ""},
+		{"unknown", []string{"grafana", "kibana"}, nil, `unknown port-forward target "kibana"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := SelectForwardTargets(targets, tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, targetNames(selected))
+		})
+	}
+}
+
+func TestWithPortOffset(t *testing.T) {
+	targets := ForwardTargets(config.Default())
+	shifted := WithPortOffset(targets, 10000)
+
+	assert.Equal(t, "http://localhost:18000", shifted[0].URL())
+	assert.Equal(t, "mongodb://localhost:37017", shifted[3].URL())
+	// Os targets originais não mudam
+	assert.Equal(t, 8000, targets[0].LocalPort)
+}
+
+func TestLocalPortAvailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	assert.False(t, localPortAvailable(port))
+	listener.Close()
+	assert.True(t, localPortAvailable(port))
+	assert.True(t, localPortAvailable(0))
+}
+
+func TestContainerPort(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-0"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "sidecar", Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9100}}},
+			{Name: "grafana", Ports: []corev1.ContainerPort{{Name: "grafana", ContainerPort: 3000}}},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		port    corev1.ServicePort
+		want    int
+		wantErr string
+	}{
+		{"numeric target port", corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8080)}, 8080, ""},
+		{"named target port", corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("grafana")}, 3000, ""},
+		{"named port in another container", corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("metrics")}, 9100, ""},
+		{"no target port uses the service port", corev1.ServicePort{Port: 27017}, 27017, ""},
+		{"unknown name", corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")}, 0, "no container port named http"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := containerPort(pod, &tt.port)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveServicePodErrors(t *testing.T) {
+	ctx := context.Background()
+	labels := map[string]string{"app": "mongodb"}
+	service := func(name string, selector map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "brewctl-data"},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+				Ports:    []corev1.ServicePort{{Port: 27017}},
+			},
+		}
+	}
+	notReady := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "mongodb-0", Namespace: "brewctl-data", Labels: labels},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	client := newTestClient(service("mongodb", labels), service("external", nil), notReady)
+
+	tests := []struct {
+		service string
+		wantErr string
+	}{
+		{"missing", "service brewctl-data/missing"},
+		{"external", "has no selector"},
+		{"mongodb", "no ready pod for service brewctl-data/mongodb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			_, _, err := client.ResolveServicePod(ctx, "brewctl-data", tt.service, 27017)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
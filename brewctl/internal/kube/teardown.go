@@ -0,0 +1,68 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"brewctl/internal/config"
+	"brewctl/internal/kube/helm"
+)
+
+// TeardownMongoDB remove o MongoDB implantado pelo brewctl. Com keepData os
+// PVCs e o Secret de credenciais são preservados, para que um novo deploy
+// reaproveite os dados com a mesma senha
+func TeardownMongoDB(cfg config.MongoDBConfig, keepData bool) error {
+	if cfg.Mode == config.MongoDBModeExternal {
+		fmt.Println("ℹ️ mongodb.mode is external: nothing to remove")
+		return nil
+	}
+
+	client, err := NewClient("")
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if cfg.Mode == config.MongoDBModeHelm {
+		fmt.Println("🗑️ Removing MongoDB Helm release...")
+		if err := helm.NewManager().Uninstall(ctx, cfg.ServiceName, cfg.Namespace); err != nil {
+			return err
+		}
+		return removeMongoDBData(ctx, client, cfg, "app.kubernetes.io/instance="+cfg.ServiceName, keepData)
+	}
+
+	fmt.Println("🗑️ Removing MongoDB...")
+	return teardownMongoDB(ctx, client, cfg, keepData)
+}
+
+func teardownMongoDB(ctx context.Context, client *Client, cfg config.MongoDBConfig, keepData bool) error {
+	if err := client.DeleteJob(ctx, cfg.Namespace, mongoDBReplicaSetJobName(cfg)); err != nil {
+		return err
+	}
+	if err := client.DeleteStatefulSet(ctx, cfg.Namespace, cfg.ServiceName); err != nil {
+		return err
+	}
+	// Deployment de versões anteriores ao StatefulSet
+	if err := client.DeleteDeployment(ctx, cfg.Namespace, cfg.ServiceName); err != nil {
+		return err
+	}
+	for _, name := range []string{cfg.ServiceName, cfg.HeadlessServiceName()} {
+		if err := client.DeleteService(ctx, cfg.Namespace, name); err != nil {
+			return err
+		}
+	}
+	return removeMongoDBData(ctx, client, cfg, "app="+cfg.ServiceName, keepData)
+}
+
+// removeMongoDBData apaga os PVCs do MongoDB e o Secret de credenciais
+func removeMongoDBData(ctx context.Context, client *Client, cfg config.MongoDBConfig, selector string, keepData bool) error {
+	if keepData {
+		fmt.Printf("ℹ️ Keeping MongoDB volumes and secret %s\n", cfg.CredentialsSecretName())
+		return nil
+	}
+
+	if _, err := client.DeletePVCs(ctx, cfg.Namespace, selector, ""); err != nil {
+		return err
+	}
+	return client.DeleteSecret(ctx, cfg.Namespace, cfg.CredentialsSecretName())
+}
@@ -2,173 +2,356 @@ package mongodb
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func (s *AggregationService) RunSilverLayerAggregation() error {
+// Coleções das camadas bronze, silver e gold
+const (
+	RawCollection   = "breweries_raw"
+	CleanCollection = "breweries_clean"
+	GoldCollection  = "breweries_aggregated"
+)
+
+// DefaultWatermarkOverlap é a janela relida abaixo do watermark anterior
+const DefaultWatermarkOverlap = 15 * time.Minute
+
+// SilverOptions controla a execução da camada silver
+type SilverOptions struct {
+	// Full reprocessa todo o bronze, ignorando o watermark da última execução
+	Full bool
+	// Overlap é a janela, pelo horário embutido no _id, relida abaixo do
+	// watermark anterior; zero usa DefaultWatermarkOverlap
+	Overlap time.Duration
+}
+
+// errBronzeIDNotObjectID indica que o bronze não tem _id ordenável pelo tempo
+var errBronzeIDNotObjectID = errors.New("incremental silver requires ObjectId _id values in breweries_raw (run with --full)")
+
+// RunSilverLayerAggregation limpa o bronze em breweries_clean. No modo
+// incremental processa apenas os documentos com _id a partir do watermark da
+// última execução bem-sucedida registrada em pipeline_runs, menos a janela de
+// Overlap. O _id é gerado por quem grava o bronze e não é monotônico entre
+// writers: um documento que chega atrasado com _id menor que o watermark só é
+// processado se cair na janela. Reprocessar a janela é seguro: o $merge da
+// silver mantém a versão mais nova e não altera documentos sem mudança.
+func (s *AggregationService) RunSilverLayerAggregation(opts SilverOptions) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
 	fmt.Println("🔄 Running Silver Layer Aggregation...")
 
-	pipeline := mongo.Pipeline{
-		// ✅ CORREÇÃO: Todos os campos nomeados com bson.E
-		{{Key: "$project", Value: bson.D{
-			{Key: "_id", Value: 1},
-			{Key: "id", Value: 1},
-			{Key: "name", Value: 1},
-			{Key: "brewery_type", Value: 1},
-			{Key: "address_1", Value: 1},
-			{Key: "city", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$city"}}}}},
-			{Key: "state_province", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$state_province"}}}}},
-			{Key: "state", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$state"}}}}},
-			{Key: "country", Value: bson.D{
-				{Key: "$cond", Value: bson.D{
-					{Key: "if", Value: bson.D{{Key: "$eq", Value: bson.A{"$country", "United States"}}}},
-					{Key: "then", Value: "US"},
-					{Key: "else", Value: "$country"},
-				}},
-			}},
-			{Key: "postal_code", Value: 1},
-			{Key: "longitude", Value: bson.D{
-				{Key: "$convert", Value: bson.D{
-					{Key: "input", Value: "$longitude"},
-					{Key: "to", Value: "double"},
-					{Key: "onError", Value: nil},
-					{Key: "onNull", Value: nil},
-				}},
-			}},
-			{Key: "latitude", Value: bson.D{
-				{Key: "$convert", Value: bson.D{
-					{Key: "input", Value: "$latitude"},
-					{Key: "to", Value: "double"},
-					{Key: "onError", Value: nil},
-					{Key: "onNull", Value: nil},
-				}},
-			}},
-			{Key: "phone", Value: 1},
-			{Key: "website_url", Value: 1},
-			{Key: "street", Value: 1},
-			// Data quality metrics
-			{Key: "data_quality", Value: bson.D{
-				{Key: "has_coordinates", Value: bson.D{
-					{Key: "$and", Value: bson.A{
-						bson.D{{Key: "$ne", Value: bson.A{"$longitude", nil}}},
-						bson.D{{Key: "$ne", Value: bson.A{"$latitude", nil}}},
-					}},
-				}},
-				{Key: "has_website", Value: bson.D{{Key: "$ne", Value: bson.A{"$website_url", nil}}}},
-				{Key: "has_phone", Value: bson.D{{Key: "$ne", Value: bson.A{"$phone", nil}}}},
-				{Key: "completeness_score", Value: bson.D{
-					{Key: "$divide", Value: bson.A{
-						bson.D{{Key: "$size", Value: bson.D{
-							{Key: "$filter", Value: bson.D{
-								{Key: "input", Value: bson.A{"$name", "$brewery_type", "$city", "$state", "$country"}},
-								{Key: "as", Value: "field"},
-								{Key: "cond", Value: bson.D{{Key: "$ne", Value: bson.A{"$$field", nil}}}},
-							}},
-						}}},
-						5,
-					}},
-				}},
-			}},
-			{Key: "ingestion_date", Value: time.Now()},
-			{Key: "last_updated", Value: time.Now()},
-		}}},
-
-		// ✅ CORREÇÃO: Merge corrigido
-		{{Key: "$merge", Value: bson.D{
-			{Key: "into", Value: "breweries_clean"},
-			{Key: "on", Value: "_id"},
-			{Key: "whenMatched", Value: "replace"},
-			{Key: "whenNotMatched", Value: "insert"},
-		}}},
-	}
-
-	// ✅ CORREÇÃO: Execução corrigida
-	_, err := s.DB.Collection("breweries_raw").Aggregate(ctx, pipeline)
-	if err != nil {
-		return fmt.Errorf("silver aggregation failed: %v", err)
+	if opts.Overlap < 0 {
+		return fmt.Errorf("watermark overlap must not be negative: %s", opts.Overlap)
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.qualityModel().Validate(); err != nil {
+		return err
+	}
+
+	run := &PipelineRun{Layer: LayerSilver, Mode: RunModeIncremental}
+	if opts.Full {
+		run.Mode = RunModeFull
+	} else {
+		last, err := s.LastSuccessfulRun(ctx, LayerSilver)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			run.PreviousWatermark = last.Watermark
+		}
 	}
 
-	// Check if any documents were processed
-	count, err := s.DB.Collection("breweries_clean").CountDocuments(ctx, bson.D{})
+	watermark, err := s.bronzeWatermark(ctx)
+	switch {
+	case errors.Is(err, errBronzeIDNotObjectID) && opts.Full:
+	case err != nil:
+		return err
+	}
+	run.Watermark = watermark
+	if run.Watermark == nil {
+		run.Watermark = run.PreviousWatermark
+	}
+
+	if err := s.startRun(ctx, run); err != nil {
+		return err
+	}
+	overlap := opts.Overlap
+	if overlap == 0 {
+		overlap = DefaultWatermarkOverlap
+	}
+	runErr := s.runSilver(ctx, run, watermarkFilter(run.PreviousWatermark, watermark, overlap))
+	if err := s.finishRun(ctx, run, runErr); err != nil && runErr == nil {
+		runErr = err
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	count, err := s.DB.Collection(CleanCollection).CountDocuments(ctx, bson.D{})
 	if err != nil {
 		return fmt.Errorf("failed to count documents: %v", err)
 	}
 
-	fmt.Printf("✅ Silver layer completed. Processed documents: %d\n", count)
+	fmt.Printf("✅ Silver layer completed (%s). Bronze documents processed: %d, silver documents: %d\n", run.Mode, run.Processed, count)
 	return nil
 }
 
-// ✅ IMPLEMENTAÇÃO: Gold Layer Aggregation faltante
-// ✅ ADICIONAR: RunGoldLayerAggregation faltante
+func (s *AggregationService) runSilver(ctx context.Context, run *PipelineRun, filter bson.D) error {
+	raw := s.DB.Collection(RawCollection)
+
+	processed, err := raw.CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to count bronze documents: %v", err)
+	}
+	run.Processed = processed
+	if processed == 0 {
+		fmt.Println("ℹ️ No new bronze documents since the last run")
+		return nil
+	}
+
+	if _, err := raw.Aggregate(ctx, SilverPipeline(filter, s.qualityModel())); err != nil {
+		return fmt.Errorf("silver aggregation failed: %v", err)
+	}
+	return nil
+}
+
+// bronzeWatermark retorna o maior _id do bronze, ou nil se a coleção estiver vazia
+func (s *AggregationService) bronzeWatermark(ctx context.Context) (*primitive.ObjectID, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+
+	var doc struct {
+		ID interface{} `bson:"_id"`
+	}
+	err := s.DB.Collection(RawCollection).FindOne(ctx, bson.D{}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bronze watermark: %v", err)
+	}
+
+	id, ok := doc.ID.(primitive.ObjectID)
+	if !ok {
+		return nil, errBronzeIDNotObjectID
+	}
+	return &id, nil
+}
+
+// firstObjectIDAt é o menor ObjectID gerado no segundo de t
+func firstObjectIDAt(t time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()))
+	return id
+}
+
+// watermarkFilter seleciona o bronze a partir do início do segundo que fica
+// overlap antes do watermark anterior até o atual (inclusivo); documentos
+// inseridos durante a execução ficam para a próxima
+func watermarkFilter(previous, current *primitive.ObjectID, overlap time.Duration) bson.D {
+	var bounds bson.D
+	if previous != nil {
+		bounds = append(bounds, bson.E{Key: "$gte", Value: firstObjectIDAt(previous.Timestamp().Add(-overlap))})
+	}
+	if current != nil {
+		bounds = append(bounds, bson.E{Key: "$lte", Value: *current})
+	}
+	if len(bounds) == 0 {
+		return bson.D{}
+	}
+	return bson.D{{Key: "_id", Value: bounds}}
+}
+
+// RunGoldLayerAggregation reconstrói a camada gold como um snapshot: o
+// resultado é gravado em uma coleção temporária que substitui
+// breweries_aggregated via renameCollection, então combinações que sumiram da
+// silver não sobrevivem e leitores nunca veem um build parcial.
 func (s *AggregationService) RunGoldLayerAggregation() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
 	fmt.Println("🔄 Running Gold Layer Aggregation...")
 
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{
-			{Key: "data_quality.completeness_score", Value: bson.D{{Key: "$gte", Value: 0.6}}},
-		}}},
-		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: bson.D{
-				{Key: "country", Value: "$country"},
-				{Key: "state", Value: "$state"},
-				{Key: "brewery_type", Value: "$brewery_type"},
-			}},
-			{Key: "total_breweries", Value: bson.D{{Key: "$sum", Value: 1}}},
-			{Key: "breweries_with_website", Value: bson.D{
-				{Key: "$sum", Value: bson.D{
-					{Key: "$cond", Value: bson.A{bson.D{{Key: "$ne", Value: bson.A{"$website_url", nil}}}, 1, 0}},
-				}},
-			}},
-			{Key: "breweries_with_phone", Value: bson.D{
-				{Key: "$sum", Value: bson.D{
-					{Key: "$cond", Value: bson.A{bson.D{{Key: "$ne", Value: bson.A{"$phone", nil}}}, 1, 0}},
-				}},
-			}},
-			{Key: "breweries_with_coordinates", Value: bson.D{
-				{Key: "$sum", Value: bson.D{
-					{Key: "$cond", Value: bson.A{"$data_quality.has_coordinates", 1, 0}},
-				}},
-			}},
-		}}},
-		{{Key: "$project", Value: bson.D{
-			{Key: "_id", Value: 0},
-			{Key: "country", Value: "$_id.country"},
-			{Key: "state", Value: "$_id.state"},
-			{Key: "brewery_type", Value: "$_id.brewery_type"},
-			{Key: "total_breweries", Value: 1},
-			{Key: "breweries_with_website", Value: 1},
-			{Key: "breweries_with_phone", Value: 1},
-			{Key: "breweries_with_coordinates", Value: 1},
-		}}},
-		{{Key: "$merge", Value: bson.D{
-			{Key: "into", Value: "breweries_aggregated"},
-			{Key: "on", Value: bson.A{"country", "state", "brewery_type"}},
-			{Key: "whenMatched", Value: "replace"},
-			{Key: "whenNotMatched", Value: "insert"},
-		}}},
-	}
-
-	_, err := s.DB.Collection("breweries_clean").Aggregate(ctx, pipeline)
-	if err != nil {
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.qualityModel().Validate(); err != nil {
+		return err
+	}
+
+	run := &PipelineRun{Layer: LayerGold, Mode: RunModeFull, BuildID: primitive.NewObjectID().Hex()}
+	if err := s.startRun(ctx, run); err != nil {
+		return err
+	}
+	runErr := s.buildGold(ctx, run)
+	if err := s.finishRun(ctx, run, runErr); err != nil && runErr == nil {
+		runErr = err
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	fmt.Printf("✅ Gold layer completed. Build %s: %d aggregated records\n", run.BuildID, run.Processed)
+	return nil
+}
+
+// goldBuildPrefix é o prefixo das coleções temporárias dos builds da gold
+const goldBuildPrefix = GoldCollection + "_build_"
+
+func (s *AggregationService) buildGold(ctx context.Context, run *PipelineRun) error {
+	if err := s.dropStaleGoldBuilds(ctx); err != nil {
+		return err
+	}
+	tmp := s.DB.Collection(goldBuildPrefix + run.BuildID)
+
+	// renameCollection com dropTarget descarta validator e índices da gold
+	// atual, então o build nasce com os mesmos antes de receber o $out
+	if err := s.cloneCollection(ctx, GoldCollection, tmp.Name()); err != nil {
+		tmp.Drop(ctx)
+		return err
+	}
+
+	if _, err := s.DB.Collection(CleanCollection).Aggregate(ctx, GoldPipeline(run.BuildID, s.qualityModel().MinScore, tmp.Name())); err != nil {
+		tmp.Drop(ctx)
 		return fmt.Errorf("gold aggregation failed: %v", err)
 	}
 
-	count, err := s.DB.Collection("breweries_aggregated").CountDocuments(ctx, bson.D{})
+	count, err := tmp.CountDocuments(ctx, bson.D{})
 	if err != nil {
+		tmp.Drop(ctx)
 		return fmt.Errorf("failed to count aggregated documents: %v", err)
 	}
+	run.Processed = count
+
+	if count == 0 {
+		// Sem resultados o $out pode não deixar a coleção do build
+		exists, err := collectionExists(ctx, s.DB, tmp.Name())
+		if err != nil {
+			tmp.Drop(ctx)
+			return err
+		}
+		if !exists {
+			if err := s.cloneCollection(ctx, GoldCollection, tmp.Name()); err != nil {
+				tmp.Drop(ctx)
+				return err
+			}
+		}
+	}
+
+	if err := s.swapCollection(ctx, tmp.Name(), GoldCollection); err != nil {
+		tmp.Drop(ctx)
+		return err
+	}
+	return nil
+}
 
-	fmt.Printf("✅ Gold layer completed. Aggregated records: %d\n", count)
+// dropStaleGoldBuilds remove coleções de builds que não chegaram a substituir
+// a gold, como as de um processo interrompido no meio do build
+func (s *AggregationService) dropStaleGoldBuilds(ctx context.Context) error {
+	filter := bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(goldBuildPrefix)}}}}
+	names, err := s.DB.ListCollectionNames(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %v", err)
+	}
+	for _, name := range names {
+		if err := s.DB.Collection(name).Drop(ctx); err != nil {
+			return fmt.Errorf("failed to drop stale gold build %s: %v", name, err)
+		}
+		fmt.Printf("🧹 Dropped stale gold build %s\n", name)
+	}
+	return nil
+}
+
+// cloneCollection cria a coleção to vazia, com o validator e os índices de
+// from; se from não existir, to é criada sem opções
+func (s *AggregationService) cloneCollection(ctx context.Context, from, to string) error {
+	specs, err := s.DB.ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: from}})
+	if err != nil {
+		return fmt.Errorf("failed to read %s options: %v", from, err)
+	}
+
+	opts := options.CreateCollection()
+	if len(specs) > 0 {
+		var source struct {
+			Validator        bson.Raw `bson:"validator"`
+			ValidationLevel  string   `bson:"validationLevel"`
+			ValidationAction string   `bson:"validationAction"`
+		}
+		if err := bson.Unmarshal(specs[0].Options, &source); err != nil {
+			return fmt.Errorf("failed to read %s options: %v", from, err)
+		}
+		if source.Validator != nil {
+			opts.SetValidator(source.Validator)
+		}
+		if source.ValidationLevel != "" {
+			opts.SetValidationLevel(source.ValidationLevel)
+		}
+		if source.ValidationAction != "" {
+			opts.SetValidationAction(source.ValidationAction)
+		}
+	}
+	if err := s.DB.CreateCollection(ctx, to, opts); err != nil {
+		return fmt.Errorf("failed to create %s: %v", to, err)
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+
+	cursor, err := s.DB.Collection(from).Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list %s indexes: %v", from, err)
+	}
+	var indexes []bson.D
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return fmt.Errorf("failed to list %s indexes: %v", from, err)
+	}
+
+	var copies bson.A
+	for _, index := range indexes {
+		var spec bson.D
+		for _, field := range index {
+			// ns só aparece em servidores antigos e não é aceito pelo createIndexes
+			if field.Key != "ns" {
+				spec = append(spec, field)
+			}
+		}
+		if name, _ := index.Map()["name"].(string); name != "_id_" {
+			copies = append(copies, spec)
+		}
+	}
+	if len(copies) == 0 {
+		return nil
+	}
+
+	cmd := bson.D{{Key: "createIndexes", Value: to}, {Key: "indexes", Value: copies}}
+	if err := s.DB.RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("failed to copy %s indexes: %v", from, err)
+	}
+	return nil
+}
+
+// swapCollection substitui target pela coleção from em uma única operação
+func (s *AggregationService) swapCollection(ctx context.Context, from, target string) error {
+	cmd := bson.D{
+		{Key: "renameCollection", Value: s.DB.Name() + "." + from},
+		{Key: "to", Value: s.DB.Name() + "." + target},
+		{Key: "dropTarget", Value: true},
+	}
+	if err := s.Client.Database("admin").RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("failed to replace %s: %v", target, err)
+	}
 	return nil
 }
 
@@ -177,21 +360,7 @@ func (s *AggregationService) GetTopStates(limit int) ([]bson.M, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	pipeline := mongo.Pipeline{
-		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$state"},
-			{Key: "total_breweries", Value: bson.D{{Key: "$sum", Value: 1}}},
-		}}},
-		{{Key: "$sort", Value: bson.D{{Key: "total_breweries", Value: -1}}}},
-		{{Key: "$limit", Value: int64(limit)}},
-		{{Key: "$project", Value: bson.D{
-			{Key: "_id", Value: 0},
-			{Key: "state", Value: "$_id"},
-			{Key: "total_breweries", Value: 1},
-		}}},
-	}
-
-	cursor, err := s.DB.Collection("breweries_clean").Aggregate(ctx, pipeline)
+	cursor, err := s.DB.Collection(CleanCollection).Aggregate(ctx, TopStatesPipeline(limit))
 	if err != nil {
 		return nil, err
 	}
@@ -210,22 +379,7 @@ func (s *AggregationService) GetBreweryTypesDistribution() ([]bson.M, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	pipeline := mongo.Pipeline{
-		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$brewery_type"},
-			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
-			{Key: "states", Value: bson.D{{Key: "$addToSet", Value: "$state"}}},
-		}}},
-		{{Key: "$project", Value: bson.D{
-			{Key: "_id", Value: 0},
-			{Key: "brewery_type", Value: "$_id"},
-			{Key: "count", Value: 1},
-			{Key: "states_covered", Value: bson.D{{Key: "$size", Value: "$states"}}},
-		}}},
-		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
-	}
-
-	cursor, err := s.DB.Collection("breweries_clean").Aggregate(ctx, pipeline)
+	cursor, err := s.DB.Collection(CleanCollection).Aggregate(ctx, BreweryTypesPipeline())
 	if err != nil {
 		return nil, err
 	}
@@ -244,24 +398,7 @@ func (s *AggregationService) GetGeographicDistribution() ([]bson.M, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{
-			{Key: "data_quality.has_coordinates", Value: true},
-		}}},
-		{{Key: "$project", Value: bson.D{
-			{Key: "_id", Value: 0},
-			{Key: "name", Value: 1},
-			{Key: "brewery_type", Value: 1},
-			{Key: "city", Value: 1},
-			{Key: "state", Value: 1},
-			{Key: "country", Value: 1},
-			{Key: "longitude", Value: 1},
-			{Key: "latitude", Value: 1},
-		}}},
-		{{Key: "$limit", Value: 100}},
-	}
-
-	cursor, err := s.DB.Collection("breweries_clean").Aggregate(ctx, pipeline)
+	cursor, err := s.DB.Collection(CleanCollection).Aggregate(ctx, GeographicPipeline(100))
 	if err != nil {
 		return nil, err
 	}
@@ -2,8 +2,10 @@ package mongodb
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -72,3 +74,37 @@ func hasMethod(s interface{}, methodName string) bool {
 		return false
 	}
 }
+
+func TestWatermarkFilter(t *testing.T) {
+	previous := primitive.NewObjectIDFromTimestamp(time.Unix(1700000000, 0))
+	current := primitive.NewObjectIDFromTimestamp(time.Unix(1700003600, 0))
+
+	assert.Equal(t, bson.D{}, watermarkFilter(nil, nil, DefaultWatermarkOverlap))
+	assert.Equal(t, bson.D{{Key: "_id", Value: bson.D{{Key: "$lte", Value: current}}}},
+		watermarkFilter(nil, &current, DefaultWatermarkOverlap))
+	assert.Equal(t, bson.D{{Key: "_id", Value: bson.D{
+		{Key: "$gte", Value: firstObjectIDAt(time.Unix(1700000000-900, 0))},
+		{Key: "$lte", Value: current},
+	}}}, watermarkFilter(&previous, &current, DefaultWatermarkOverlap))
+}
+
+func TestWatermarkFilterLateArrival(t *testing.T) {
+	// O watermark veio de um writer com relógio adiantado; outro writer grava
+	// depois um documento com _id menor
+	previous, err := primitive.ObjectIDFromHex("6553f100ffffffffffffffff")
+	require.NoError(t, err)
+	current := primitive.NewObjectIDFromTimestamp(previous.Timestamp().Add(time.Hour))
+	late := primitive.NewObjectIDFromTimestamp(previous.Timestamp().Add(-time.Minute))
+	tooLate := primitive.NewObjectIDFromTimestamp(previous.Timestamp().Add(-time.Hour))
+
+	bounds := watermarkFilter(&previous, &current, DefaultWatermarkOverlap)[0].Value.(bson.D)
+	from := bounds[0].Value.(primitive.ObjectID)
+	assert.Equal(t, "$gte", bounds[0].Key)
+
+	inWindow := func(id primitive.ObjectID) bool {
+		return from.Hex() <= id.Hex() && id.Hex() <= current.Hex()
+	}
+	assert.True(t, inWindow(late), "late arrival inside the overlap window")
+	assert.True(t, inWindow(previous), "the previous watermark is read again")
+	assert.False(t, inWindow(tooLate), "arrival older than the overlap window")
+}
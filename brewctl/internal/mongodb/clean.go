@@ -0,0 +1,222 @@
+package mongodb
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Clean é o documento que a silver grava para um documento do bronze, na
+// ordem dos estágios do pipeline e sem os campos preenchidos pelo servidor
+// (_id, ingestion_date e last_updated)
+type Clean bson.D
+
+// silverCopiedFields são os campos que o $project copia do bronze sem
+// conversão; ausentes no bronze, ficam ausentes na silver
+var silverCopiedFields = []string{"name", "brewery_type", "address_1", "country", "postal_code", "phone", "website_url", "street"}
+
+// silverTrimmedFields são os campos que o $project copia com $trim
+var silverTrimmedFields = []string{"city", "state_province", "state"}
+
+// CleanBrewery aplica em Go a transformação da silver, com o modelo de
+// qualidade padrão, a um documento do bronze. Documentos que a silver
+// descarta (sem id textual) retornam um Clean nil.
+func CleanBrewery(raw bson.M) (Clean, QualityReport) {
+	return DefaultQualityModel().CleanBrewery(raw)
+}
+
+// CleanBrewery aplica a transformação da silver com este modelo de qualidade.
+// Versões repetidas de um id não são resolvidas aqui: a silver mantém a de
+// maior updated_at.
+func (m QualityModel) CleanBrewery(raw bson.M) (Clean, QualityReport) {
+	id, ok := raw["id"].(string)
+	if !ok {
+		return nil, QualityReport{}
+	}
+
+	// $project
+	doc := bson.M{"id": id}
+	for _, field := range silverCopiedFields {
+		if value, ok := raw[field]; ok {
+			doc[field] = value
+		}
+	}
+	for _, field := range silverTrimmedFields {
+		doc[field] = nil
+		if value, ok := raw[field].(string); ok {
+			doc[field] = trimSpace(value)
+		}
+	}
+	doc["longitude"] = toDouble(raw["longitude"])
+	doc["latitude"] = toDouble(raw["latitude"])
+	doc["updated_at"] = toDate(raw["updated_at"])
+
+	// Normalização: o país primeiro, porque as demais regras dependem dele
+	country := ""
+	doc["country"] = nil
+	if value, ok := raw["country"].(string); ok {
+		country = NormalizeCountry(value)
+		doc["country"] = country
+	}
+	zip5, zip4 := SplitZIP(country, toString(doc["postal_code"]))
+	doc["state_code"] = nilIfEmpty(NormalizeSubdivision(country, toString(doc["state"])))
+	doc["phone"] = nilIfEmpty(NormalizePhone(country, toString(doc["phone"])))
+	doc["website_url"] = nilIfEmpty(NormalizeWebsite(toString(doc["website_url"])))
+	doc["zip5"] = nilIfEmpty(zip5)
+	doc["zip4"] = nilIfEmpty(zip4)
+	if validCoordinates(doc["longitude"], doc["latitude"]) {
+		doc["location"] = bson.D{
+			{Key: "type", Value: "Point"},
+			{Key: "coordinates", Value: bson.A{doc["longitude"], doc["latitude"]}},
+		}
+	}
+
+	report := m.Evaluate(doc)
+	flags := bson.D{}
+	for _, rule := range m.Rules {
+		flags = append(flags, bson.E{Key: rule.Name, Value: report.Flags[rule.Name]})
+	}
+	quality := bson.D{
+		{Key: "flags", Value: flags},
+		{Key: "completeness_score", Value: report.CompletenessScore},
+	}
+	for _, name := range legacyFlags {
+		quality = append(quality, bson.E{Key: "has_" + name, Value: report.Flags[name]})
+	}
+	doc["data_quality"] = quality
+
+	clean := Clean{}
+	for _, field := range silverFieldOrder {
+		if value, ok := doc[field]; ok {
+			clean = append(clean, bson.E{Key: field, Value: value})
+		}
+	}
+	return clean, report
+}
+
+// silverFieldOrder é a ordem dos campos em Clean: a do $project seguida dos
+// campos criados pela normalização e pelo modelo de qualidade
+var silverFieldOrder = []string{
+	"id", "name", "brewery_type", "address_1", "city", "state_province", "state", "country",
+	"postal_code", "longitude", "latitude", "phone", "website_url", "street", "updated_at",
+	"state_code", "zip5", "zip4", "location", "data_quality",
+}
+
+func nilIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// toString segue o $convert para string usado pelo pipeline: null, ausente ou
+// não conversível vira ""
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case primitive.ObjectID:
+		return v.Hex()
+	case primitive.DateTime:
+		return v.Time().UTC().Format("2006-01-02T15:04:05.000Z")
+	case primitive.Decimal128:
+		return v.String()
+	default:
+		return ""
+	}
+}
+
+// toDouble segue o $convert para double do $project: valores inválidos viram nil
+func toDouble(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	case bool:
+		if v {
+			return 1.0
+		}
+		return 0.0
+	case primitive.DateTime:
+		return float64(v)
+	case primitive.Decimal128:
+		return parseDouble(v.String())
+	case string:
+		return parseDouble(v)
+	default:
+		return nil
+	}
+}
+
+// parseDouble aceita números em base 10, sem espaços, além de NaN e Infinity
+func parseDouble(s string) interface{} {
+	if s == "" || strings.ContainsAny(s, " _xXpP") {
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// silverDateLayouts são os formatos de data aceitos na conversão de updated_at
+var silverDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// toDate segue o $convert para date do $project: valores inválidos viram nil
+func toDate(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.DateTime:
+		return v
+	case time.Time:
+		return primitive.NewDateTimeFromTime(v)
+	case int64:
+		return primitive.DateTime(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return primitive.DateTime(int64(v))
+	case primitive.ObjectID:
+		return primitive.NewDateTimeFromTime(v.Timestamp())
+	case string:
+		for _, layout := range silverDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return primitive.NewDateTimeFromTime(t)
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
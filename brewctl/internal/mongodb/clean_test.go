@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// loadFixture lê um array de documentos em Extended JSON de testdata/integration
+func loadFixture(t *testing.T, name string) []bson.D {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "integration", name))
+	require.NoError(t, err)
+
+	// O Extended JSON só é lido como documento, então o array vai em um envelope
+	var envelope struct {
+		Docs []bson.D `bson:"docs"`
+	}
+	require.NoError(t, bson.UnmarshalExtJSON([]byte(`{"docs": `+string(data)+`}`), false, &envelope))
+	return envelope.Docs
+}
+
+// assertDocuments compara os documentos pelo Extended JSON relaxed, sem
+// depender da ordem dos campos
+func assertDocuments(t *testing.T, want, got []bson.D) {
+	t.Helper()
+	assert.Equal(t, relaxedJSON(t, want), relaxedJSON(t, got))
+}
+
+func relaxedJSON(t *testing.T, docs []bson.D) []interface{} {
+	t.Helper()
+
+	out := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		data, err := bson.MarshalExtJSON(doc, false, false)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &out[i]))
+	}
+	return out
+}
+
+// cleanLatest aplica CleanBrewery ao bronze, na ordem do _id, e, como a
+// silver, mantém por id a versão de maior updated_at; versões sem updated_at
+// válido contam como mais novas. O resultado segue a ordem dos ids.
+func cleanLatest(raws []bson.D) []bson.D {
+	latest := map[string]Clean{}
+	for _, raw := range raws {
+		clean, _ := CleanBrewery(raw.Map())
+		if clean == nil {
+			continue
+		}
+		doc := bson.D(clean).Map()
+		id := doc["id"].(string)
+		if current, ok := latest[id]; ok {
+			updatedAt, dated := doc["updated_at"].(primitive.DateTime)
+			currentUpdatedAt, currentDated := bson.D(current).Map()["updated_at"].(primitive.DateTime)
+			if dated && (!currentDated || updatedAt < currentUpdatedAt) {
+				continue
+			}
+		}
+		latest[id] = clean
+	}
+
+	ids := sortedKeys(latest)
+	docs := make([]bson.D, len(ids))
+	for i, id := range ids {
+		docs[i] = bson.D(latest[id])
+	}
+	return docs
+}
+
+func TestCleanBreweryMatchesSilverFixture(t *testing.T) {
+	// breweries_clean.json é a saída esperada do pipeline para o mesmo bronze
+	// nos testes de integração
+	assertDocuments(t, loadFixture(t, "breweries_clean.json"), cleanLatest(loadFixture(t, "breweries_raw.json")))
+}
+
+func TestCleanBrewery(t *testing.T) {
+	clean, report := CleanBrewery(bson.M{
+		"id":          "b1",
+		"name":        "  Rhinegeist  ",
+		"country":     " usa ",
+		"state":       " OHIO ",
+		"phone":       int64(5135550100),
+		"postal_code": int32(45202),
+		"longitude":   int32(-84),
+		"latitude":    "39.5",
+		"updated_at":  "not a date",
+	})
+	require.NotNil(t, clean)
+	doc := bson.D(clean).Map()
+
+	assert.Equal(t, "  Rhinegeist  ", doc["name"], "name is copied as is")
+	assert.Equal(t, "US", doc["country"])
+	assert.Equal(t, "OHIO", doc["state"])
+	assert.Equal(t, "OH", doc["state_code"])
+	assert.Equal(t, "+15135550100", doc["phone"])
+	assert.Equal(t, "45202", doc["zip5"])
+	assert.Nil(t, doc["zip4"])
+	assert.Nil(t, doc["website_url"])
+	assert.Nil(t, doc["updated_at"])
+	assert.Nil(t, doc["city"])
+	assert.NotContains(t, doc, "address_1", "missing bronze fields stay missing")
+	assert.Equal(t, -84.0, doc["longitude"])
+	assert.Equal(t, bson.D{
+		{Key: "type", Value: "Point"},
+		{Key: "coordinates", Value: bson.A{-84.0, 39.5}},
+	}, doc["location"])
+
+	// O relatório é o mesmo gravado em data_quality
+	quality := doc["data_quality"].(bson.D).Map()
+	assert.Equal(t, report.CompletenessScore, quality["completeness_score"])
+	assert.Equal(t, true, quality["has_coordinates"])
+	assert.Equal(t, false, quality["has_website"])
+	assert.Equal(t, report.Flags["phone"], quality["has_phone"])
+
+	// Telefones que não viram E.164 são mantidos e contam como presentes
+	clean, report = CleanBrewery(bson.M{"id": "b2", "name": "Bar", "country": "Kenya", "phone": " 1234 "})
+	require.NotNil(t, clean)
+	doc = bson.D(clean).Map()
+	assert.Equal(t, "KE", doc["country"])
+	assert.Equal(t, "1234", doc["phone"])
+	assert.True(t, report.Flags["phone"])
+
+	// A silver descarta documentos sem id textual
+	clean, report = CleanBrewery(bson.M{"id": int32(1), "name": "No id"})
+	assert.Nil(t, clean)
+	assert.Empty(t, report.Flags)
+}
+
+func TestCleanBreweryConversions(t *testing.T) {
+	tests := []struct {
+		value     interface{}
+		double    interface{}
+		formatted string
+	}{
+		{"-84.4137736", -84.4137736, "-84.4137736"},
+		{int32(7), 7.0, "7"},
+		{int64(5135550100), 5135550100.0, "5135550100"},
+		{5135550100.0, 5135550100.0, "5135550100"},
+		{1.5, 1.5, "1.5"},
+		{true, 1.0, "true"},
+		{" 1.5", nil, " 1.5"},
+		{"0x10", nil, "0x10"},
+		{"abc", nil, "abc"},
+		{nil, nil, ""},
+		{bson.A{1}, nil, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.double, toDouble(tt.value), "toDouble(%#v)", tt.value)
+		assert.Equal(t, tt.formatted, toString(tt.value), "toString(%#v)", tt.value)
+	}
+
+	assert.Equal(t, primitive.DateTime(1704067200000), toDate("2024-01-01T00:00:00Z"))
+	assert.Equal(t, primitive.DateTime(1704067200123), toDate("2024-01-01T00:00:00.123Z"))
+	assert.Equal(t, primitive.DateTime(1704067200000), toDate("2024-01-01"))
+	assert.Nil(t, toDate("yesterday"))
+	assert.Nil(t, toDate(int32(1)))
+}
@@ -12,9 +12,21 @@ import (
 type AggregationService struct {
 	Client *mongo.Client
 	DB     *mongo.Database
+	// Quality é o modelo de qualidade da silver; vazio usa DefaultQualityModel
+	Quality QualityModel
+	// AutoMigrate aplica as migrations pendentes antes das agregações em vez
+	// de falhar; um banco nunca migrado sempre as recebe
+	AutoMigrate bool
+
+	schemaChecked bool
 }
 
 func NewAggregationService(connectionString string) (*AggregationService, error) {
+	return NewAggregationServiceForDatabase(connectionString, "breweries_db")
+}
+
+// NewAggregationServiceForDatabase conecta ao MongoDB e usa o database informado
+func NewAggregationServiceForDatabase(connectionString, database string) (*AggregationService, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -28,7 +40,7 @@ func NewAggregationService(connectionString string) (*AggregationService, error)
 		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
 	}
 
-	db := client.Database("breweries_db")
+	db := client.Database(database)
 	return &AggregationService{
 		Client: client,
 		DB:     db,
@@ -0,0 +1,263 @@
+package mongodb
+
+// isoCountry é uma linha da ISO-3166-1: código alfa-2, código telefônico
+// internacional e os nomes aceitos, em minúsculas, começando pelo nome curto
+// da norma. Territórios sem código próprio usam o do país que os atende.
+type isoCountry struct {
+	code        string
+	callingCode string
+	names       []string
+}
+
+// isoCountries cobre todos os 249 códigos alfa-2 atribuídos pela ISO-3166-1
+var isoCountries = []isoCountry{
+	{"AD", "376", []string{"andorra"}},
+	{"AE", "971", []string{"united arab emirates", "uae"}},
+	{"AF", "93", []string{"afghanistan"}},
+	{"AG", "1", []string{"antigua and barbuda"}},
+	{"AI", "1", []string{"anguilla"}},
+	{"AL", "355", []string{"albania"}},
+	{"AM", "374", []string{"armenia"}},
+	{"AO", "244", []string{"angola"}},
+	{"AQ", "672", []string{"antarctica"}},
+	{"AR", "54", []string{"argentina"}},
+	{"AS", "1", []string{"american samoa"}},
+	{"AT", "43", []string{"austria", "österreich"}},
+	{"AU", "61", []string{"australia"}},
+	{"AW", "297", []string{"aruba"}},
+	{"AX", "358", []string{"åland islands", "aland islands"}},
+	{"AZ", "994", []string{"azerbaijan"}},
+	{"BA", "387", []string{"bosnia and herzegovina"}},
+	{"BB", "1", []string{"barbados"}},
+	{"BD", "880", []string{"bangladesh"}},
+	{"BE", "32", []string{"belgium"}},
+	{"BF", "226", []string{"burkina faso"}},
+	{"BG", "359", []string{"bulgaria"}},
+	{"BH", "973", []string{"bahrain"}},
+	{"BI", "257", []string{"burundi"}},
+	{"BJ", "229", []string{"benin"}},
+	{"BL", "590", []string{"saint barthélemy", "saint barthelemy"}},
+	{"BM", "1", []string{"bermuda"}},
+	{"BN", "673", []string{"brunei darussalam", "brunei"}},
+	{"BO", "591", []string{"bolivia (plurinational state of)", "bolivia"}},
+	{"BQ", "599", []string{"bonaire, sint eustatius and saba", "caribbean netherlands"}},
+	{"BR", "55", []string{"brazil", "brasil"}},
+	{"BS", "1", []string{"bahamas", "the bahamas"}},
+	{"BT", "975", []string{"bhutan"}},
+	{"BV", "47", []string{"bouvet island"}},
+	{"BW", "267", []string{"botswana"}},
+	{"BY", "375", []string{"belarus"}},
+	{"BZ", "501", []string{"belize"}},
+	{"CA", "1", []string{"canada"}},
+	{"CC", "61", []string{"cocos (keeling) islands", "cocos islands"}},
+	{"CD", "243", []string{"congo, democratic republic of the", "democratic republic of the congo", "dr congo"}},
+	{"CF", "236", []string{"central african republic"}},
+	{"CG", "242", []string{"congo", "republic of the congo"}},
+	{"CH", "41", []string{"switzerland"}},
+	{"CI", "225", []string{"côte d'ivoire", "cote d'ivoire", "ivory coast"}},
+	{"CK", "682", []string{"cook islands"}},
+	{"CL", "56", []string{"chile"}},
+	{"CM", "237", []string{"cameroon"}},
+	{"CN", "86", []string{"china"}},
+	{"CO", "57", []string{"colombia"}},
+	{"CR", "506", []string{"costa rica"}},
+	{"CU", "53", []string{"cuba"}},
+	{"CV", "238", []string{"cabo verde", "cape verde"}},
+	{"CW", "599", []string{"curaçao", "curacao"}},
+	{"CX", "61", []string{"christmas island"}},
+	{"CY", "357", []string{"cyprus"}},
+	{"CZ", "420", []string{"czechia", "czech republic"}},
+	{"DE", "49", []string{"germany", "deutschland"}},
+	{"DJ", "253", []string{"djibouti"}},
+	{"DK", "45", []string{"denmark"}},
+	{"DM", "1", []string{"dominica"}},
+	{"DO", "1", []string{"dominican republic"}},
+	{"DZ", "213", []string{"algeria"}},
+	{"EC", "593", []string{"ecuador"}},
+	{"EE", "372", []string{"estonia"}},
+	{"EG", "20", []string{"egypt"}},
+	{"EH", "212", []string{"western sahara"}},
+	{"ER", "291", []string{"eritrea"}},
+	{"ES", "34", []string{"spain"}},
+	{"ET", "251", []string{"ethiopia"}},
+	{"FI", "358", []string{"finland"}},
+	{"FJ", "679", []string{"fiji"}},
+	{"FK", "500", []string{"falkland islands (malvinas)", "falkland islands"}},
+	{"FM", "691", []string{"micronesia (federated states of)", "micronesia"}},
+	{"FO", "298", []string{"faroe islands"}},
+	{"FR", "33", []string{"france"}},
+	{"GA", "241", []string{"gabon"}},
+	{"GB", "44", []string{"united kingdom", "united kingdom of great britain and northern ireland", "uk", "great britain", "england", "scotland", "wales", "northern ireland"}},
+	{"GD", "1", []string{"grenada"}},
+	{"GE", "995", []string{"georgia"}},
+	{"GF", "594", []string{"french guiana"}},
+	{"GG", "44", []string{"guernsey"}},
+	{"GH", "233", []string{"ghana"}},
+	{"GI", "350", []string{"gibraltar"}},
+	{"GL", "299", []string{"greenland"}},
+	{"GM", "220", []string{"gambia", "the gambia"}},
+	{"GN", "224", []string{"guinea"}},
+	{"GP", "590", []string{"guadeloupe"}},
+	{"GQ", "240", []string{"equatorial guinea"}},
+	{"GR", "30", []string{"greece"}},
+	{"GS", "500", []string{"south georgia and the south sandwich islands"}},
+	{"GT", "502", []string{"guatemala"}},
+	{"GU", "1", []string{"guam"}},
+	{"GW", "245", []string{"guinea-bissau"}},
+	{"GY", "592", []string{"guyana"}},
+	{"HK", "852", []string{"hong kong"}},
+	{"HM", "672", []string{"heard island and mcdonald islands"}},
+	{"HN", "504", []string{"honduras"}},
+	{"HR", "385", []string{"croatia"}},
+	{"HT", "509", []string{"haiti"}},
+	{"HU", "36", []string{"hungary"}},
+	{"ID", "62", []string{"indonesia"}},
+	{"IE", "353", []string{"ireland", "republic of ireland"}},
+	{"IL", "972", []string{"israel"}},
+	{"IM", "44", []string{"isle of man"}},
+	{"IN", "91", []string{"india"}},
+	{"IO", "246", []string{"british indian ocean territory"}},
+	{"IQ", "964", []string{"iraq"}},
+	{"IR", "98", []string{"iran (islamic republic of)", "iran"}},
+	{"IS", "354", []string{"iceland"}},
+	{"IT", "39", []string{"italy"}},
+	{"JE", "44", []string{"jersey"}},
+	{"JM", "1", []string{"jamaica"}},
+	{"JO", "962", []string{"jordan"}},
+	{"JP", "81", []string{"japan"}},
+	{"KE", "254", []string{"kenya"}},
+	{"KG", "996", []string{"kyrgyzstan"}},
+	{"KH", "855", []string{"cambodia"}},
+	{"KI", "686", []string{"kiribati"}},
+	{"KM", "269", []string{"comoros"}},
+	{"KN", "1", []string{"saint kitts and nevis"}},
+	{"KP", "850", []string{"korea (democratic people's republic of)", "north korea"}},
+	{"KR", "82", []string{"korea, republic of", "south korea", "korea", "republic of korea"}},
+	{"KW", "965", []string{"kuwait"}},
+	{"KY", "1", []string{"cayman islands"}},
+	{"KZ", "7", []string{"kazakhstan"}},
+	{"LA", "856", []string{"lao people's democratic republic", "laos"}},
+	{"LB", "961", []string{"lebanon"}},
+	{"LC", "1", []string{"saint lucia"}},
+	{"LI", "423", []string{"liechtenstein"}},
+	{"LK", "94", []string{"sri lanka"}},
+	{"LR", "231", []string{"liberia"}},
+	{"LS", "266", []string{"lesotho"}},
+	{"LT", "370", []string{"lithuania"}},
+	{"LU", "352", []string{"luxembourg"}},
+	{"LV", "371", []string{"latvia"}},
+	{"LY", "218", []string{"libya"}},
+	{"MA", "212", []string{"morocco"}},
+	{"MC", "377", []string{"monaco"}},
+	{"MD", "373", []string{"moldova, republic of", "moldova"}},
+	{"ME", "382", []string{"montenegro"}},
+	{"MF", "590", []string{"saint martin (french part)", "saint martin"}},
+	{"MG", "261", []string{"madagascar"}},
+	{"MH", "692", []string{"marshall islands"}},
+	{"MK", "389", []string{"north macedonia", "macedonia"}},
+	{"ML", "223", []string{"mali"}},
+	{"MM", "95", []string{"myanmar", "burma"}},
+	{"MN", "976", []string{"mongolia"}},
+	{"MO", "853", []string{"macao", "macau"}},
+	{"MP", "1", []string{"northern mariana islands"}},
+	{"MQ", "596", []string{"martinique"}},
+	{"MR", "222", []string{"mauritania"}},
+	{"MS", "1", []string{"montserrat"}},
+	{"MT", "356", []string{"malta"}},
+	{"MU", "230", []string{"mauritius"}},
+	{"MV", "960", []string{"maldives"}},
+	{"MW", "265", []string{"malawi"}},
+	{"MX", "52", []string{"mexico", "méxico"}},
+	{"MY", "60", []string{"malaysia"}},
+	{"MZ", "258", []string{"mozambique"}},
+	{"NA", "264", []string{"namibia"}},
+	{"NC", "687", []string{"new caledonia"}},
+	{"NE", "227", []string{"niger"}},
+	{"NF", "672", []string{"norfolk island"}},
+	{"NG", "234", []string{"nigeria"}},
+	{"NI", "505", []string{"nicaragua"}},
+	{"NL", "31", []string{"netherlands", "the netherlands", "holland", "netherlands, kingdom of the"}},
+	{"NO", "47", []string{"norway"}},
+	{"NP", "977", []string{"nepal"}},
+	{"NR", "674", []string{"nauru"}},
+	{"NU", "683", []string{"niue"}},
+	{"NZ", "64", []string{"new zealand"}},
+	{"OM", "968", []string{"oman"}},
+	{"PA", "507", []string{"panama"}},
+	{"PE", "51", []string{"peru"}},
+	{"PF", "689", []string{"french polynesia"}},
+	{"PG", "675", []string{"papua new guinea"}},
+	{"PH", "63", []string{"philippines"}},
+	{"PK", "92", []string{"pakistan"}},
+	{"PL", "48", []string{"poland"}},
+	{"PM", "508", []string{"saint pierre and miquelon"}},
+	{"PN", "64", []string{"pitcairn", "pitcairn islands"}},
+	{"PR", "1", []string{"puerto rico"}},
+	{"PS", "970", []string{"palestine, state of", "palestine"}},
+	{"PT", "351", []string{"portugal"}},
+	{"PW", "680", []string{"palau"}},
+	{"PY", "595", []string{"paraguay"}},
+	{"QA", "974", []string{"qatar"}},
+	{"RE", "262", []string{"réunion", "reunion"}},
+	{"RO", "40", []string{"romania"}},
+	{"RS", "381", []string{"serbia"}},
+	{"RU", "7", []string{"russian federation", "russia"}},
+	{"RW", "250", []string{"rwanda"}},
+	{"SA", "966", []string{"saudi arabia"}},
+	{"SB", "677", []string{"solomon islands"}},
+	{"SC", "248", []string{"seychelles"}},
+	{"SD", "249", []string{"sudan"}},
+	{"SE", "46", []string{"sweden"}},
+	{"SG", "65", []string{"singapore"}},
+	{"SH", "290", []string{"saint helena, ascension and tristan da cunha", "saint helena"}},
+	{"SI", "386", []string{"slovenia"}},
+	{"SJ", "47", []string{"svalbard and jan mayen"}},
+	{"SK", "421", []string{"slovakia"}},
+	{"SL", "232", []string{"sierra leone"}},
+	{"SM", "378", []string{"san marino"}},
+	{"SN", "221", []string{"senegal"}},
+	{"SO", "252", []string{"somalia"}},
+	{"SR", "597", []string{"suriname"}},
+	{"SS", "211", []string{"south sudan"}},
+	{"ST", "239", []string{"sao tome and principe", "são tomé and príncipe"}},
+	{"SV", "503", []string{"el salvador"}},
+	{"SX", "1", []string{"sint maarten (dutch part)", "sint maarten"}},
+	{"SY", "963", []string{"syrian arab republic", "syria"}},
+	{"SZ", "268", []string{"eswatini", "swaziland"}},
+	{"TC", "1", []string{"turks and caicos islands"}},
+	{"TD", "235", []string{"chad"}},
+	{"TF", "262", []string{"french southern territories"}},
+	{"TG", "228", []string{"togo"}},
+	{"TH", "66", []string{"thailand"}},
+	{"TJ", "992", []string{"tajikistan"}},
+	{"TK", "690", []string{"tokelau"}},
+	{"TL", "670", []string{"timor-leste", "east timor"}},
+	{"TM", "993", []string{"turkmenistan"}},
+	{"TN", "216", []string{"tunisia"}},
+	{"TO", "676", []string{"tonga"}},
+	{"TR", "90", []string{"türkiye", "turkiye", "turkey"}},
+	{"TT", "1", []string{"trinidad and tobago"}},
+	{"TV", "688", []string{"tuvalu"}},
+	{"TW", "886", []string{"taiwan, province of china", "taiwan"}},
+	{"TZ", "255", []string{"tanzania, united republic of", "tanzania"}},
+	{"UA", "380", []string{"ukraine"}},
+	{"UG", "256", []string{"uganda"}},
+	{"UM", "1", []string{"united states minor outlying islands"}},
+	{"US", "1", []string{"united states", "united states of america", "usa", "u.s.", "u.s.a."}},
+	{"UY", "598", []string{"uruguay"}},
+	{"UZ", "998", []string{"uzbekistan"}},
+	{"VA", "39", []string{"holy see", "vatican city"}},
+	{"VC", "1", []string{"saint vincent and the grenadines"}},
+	{"VE", "58", []string{"venezuela (bolivarian republic of)", "venezuela"}},
+	{"VG", "1", []string{"virgin islands (british)", "british virgin islands"}},
+	{"VI", "1", []string{"virgin islands (u.s.)", "u.s. virgin islands", "us virgin islands"}},
+	{"VN", "84", []string{"viet nam", "vietnam"}},
+	{"VU", "678", []string{"vanuatu"}},
+	{"WF", "681", []string{"wallis and futuna"}},
+	{"WS", "685", []string{"samoa"}},
+	{"YE", "967", []string{"yemen"}},
+	{"YT", "262", []string{"mayotte"}},
+	{"ZA", "27", []string{"south africa"}},
+	{"ZM", "260", []string{"zambia"}},
+	{"ZW", "263", []string{"zimbabwe"}},
+}
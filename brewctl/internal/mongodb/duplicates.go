@@ -0,0 +1,204 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DuplicatesCollection guarda o relatório de prováveis duplicatas da silver
+const DuplicatesCollection = "breweries_duplicates"
+
+// DefaultDuplicateDistance é a distância máxima padrão, em metros, entre duas
+// cervejarias consideradas a mesma
+const DefaultDuplicateDistance = 250.0
+
+// BreweryRef são os campos da silver usados na resolução de entidades
+type BreweryRef struct {
+	ID         string   `bson:"id"`
+	Name       string   `bson:"name"`
+	PostalCode string   `bson:"postal_code"`
+	Longitude  *float64 `bson:"longitude"`
+	Latitude   *float64 `bson:"latitude"`
+}
+
+// DuplicateCandidate é um par de ids diferentes que provavelmente são a mesma
+// cervejaria. DistanceMeters fica vazio quando uma delas não tem coordenadas.
+type DuplicateCandidate struct {
+	IDs            []string  `bson:"ids"`
+	Names          []string  `bson:"names"`
+	NameKey        string    `bson:"name_key"`
+	PostalCode     string    `bson:"postal_code"`
+	DistanceMeters *float64  `bson:"distance_meters"`
+	DetectedAt     time.Time `bson:"detected_at"`
+}
+
+// nameStopWords são termos genéricos ignorados na comparação de nomes
+var nameStopWords = map[string]bool{
+	"the": true, "and": true, "co": true, "company": true, "llc": true, "inc": true,
+	"brewing": true, "brewery": true, "breweries": true, "brewpub": true, "brewhouse": true,
+	"beer": true, "works": true,
+}
+
+// normalizeName reduz o nome às palavras significativas, em minúsculas e sem
+// pontuação; "The Foo Brewing Co." e "Foo Brewery" viram "foo"
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var kept []string
+	for _, word := range words {
+		if !nameStopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	// Um nome feito só de termos genéricos é comparado por inteiro
+	if len(kept) == 0 {
+		kept = words
+	}
+	return strings.Join(kept, "")
+}
+
+// normalizePostalCode remove separadores e reduz códigos americanos ao ZIP5
+func normalizePostalCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	normalized := b.String()
+	if len(normalized) > 5 && isDigits(normalized[:5]) {
+		return normalized[:5]
+	}
+	return normalized
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// distanceMeters calcula a distância pela fórmula de haversine
+func distanceMeters(lon1, lat1, lon2, lat2 float64) float64 {
+	const earthRadius = 6371000.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// FindDuplicates agrupa as cervejarias por nome normalizado e código postal e
+// marca os pares de ids diferentes que estão a até maxDistance metros. Pares
+// em que falta coordenada são marcados apenas por nome e código postal.
+func FindDuplicates(breweries []BreweryRef, maxDistance float64) []DuplicateCandidate {
+	type key struct{ name, postal string }
+	groups := map[key][]BreweryRef{}
+	var keys []key
+	for _, b := range breweries {
+		k := key{normalizeName(b.Name), normalizePostalCode(b.PostalCode)}
+		if k.name == "" || k.postal == "" {
+			continue
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], b)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].postal != keys[j].postal {
+			return keys[i].postal < keys[j].postal
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	var candidates []DuplicateCandidate
+	for _, k := range keys {
+		group := groups[k]
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				a, b := group[i], group[j]
+				if a.ID == b.ID {
+					continue
+				}
+				candidate := DuplicateCandidate{
+					IDs:        []string{a.ID, b.ID},
+					Names:      []string{a.Name, b.Name},
+					NameKey:    k.name,
+					PostalCode: k.postal,
+				}
+				if a.Longitude != nil && a.Latitude != nil && b.Longitude != nil && b.Latitude != nil {
+					distance := distanceMeters(*a.Longitude, *a.Latitude, *b.Longitude, *b.Latitude)
+					if distance > maxDistance {
+						continue
+					}
+					candidate.DistanceMeters = &distance
+				}
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+// ResolveDuplicates procura prováveis duplicatas na silver e substitui o
+// relatório em breweries_duplicates
+func (s *AggregationService) ResolveDuplicates(maxDistance float64) ([]DuplicateCandidate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	fmt.Println("🔎 Looking for likely duplicate breweries...")
+
+	projection := bson.D{
+		{Key: "_id", Value: 0},
+		{Key: "id", Value: 1},
+		{Key: "name", Value: 1},
+		{Key: "postal_code", Value: 1},
+		{Key: "longitude", Value: 1},
+		{Key: "latitude", Value: 1},
+	}
+	cursor, err := s.DB.Collection(CleanCollection).Find(ctx, bson.D{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silver breweries: %v", err)
+	}
+	var breweries []BreweryRef
+	if err := cursor.All(ctx, &breweries); err != nil {
+		return nil, fmt.Errorf("failed to read silver breweries: %v", err)
+	}
+
+	candidates := FindDuplicates(breweries, maxDistance)
+	detectedAt := time.Now().UTC()
+
+	report := s.DB.Collection(DuplicatesCollection)
+	if _, err := report.DeleteMany(ctx, bson.D{}); err != nil {
+		return nil, fmt.Errorf("failed to clear %s: %v", DuplicatesCollection, err)
+	}
+	if len(candidates) > 0 {
+		docs := make([]interface{}, len(candidates))
+		for i := range candidates {
+			candidates[i].DetectedAt = detectedAt
+			docs[i] = candidates[i]
+		}
+		if _, err := report.InsertMany(ctx, docs); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", DuplicatesCollection, err)
+		}
+	}
+
+	fmt.Printf("✅ %d likely duplicate pairs written to %s\n", len(candidates), DuplicatesCollection)
+	return candidates, nil
+}
@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func coord(v float64) *float64 { return &v }
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"The Foo Brewing Co.":   "foo",
+		"Foo Brewery":           "foo",
+		"Foo's Beer Works, LLC": "foos",
+		"Brewing Company":       "brewingcompany",
+		"":                      "",
+	}
+	for name, expected := range tests {
+		assert.Equal(t, expected, normalizeName(name), name)
+	}
+}
+
+func TestNormalizePostalCode(t *testing.T) {
+	tests := map[string]string{
+		"97201-1234": "97201",
+		"97201":      "97201",
+		" v6b 1a1 ":  "V6B1A1",
+		"":           "",
+	}
+	for code, expected := range tests {
+		assert.Equal(t, expected, normalizePostalCode(code), code)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	breweries := []BreweryRef{
+		{ID: "a", Name: "The Foo Brewing Co.", PostalCode: "97201-1234", Longitude: coord(-122.6765), Latitude: coord(45.5231)},
+		// Mesmo lugar, ~50m de distância
+		{ID: "b", Name: "Foo Brewery", PostalCode: "97201", Longitude: coord(-122.6760), Latitude: coord(45.5233)},
+		// Mesmo nome e CEP, mas a vários quilômetros
+		{ID: "c", Name: "Foo Brewery", PostalCode: "97201", Longitude: coord(-122.60), Latitude: coord(45.52)},
+		// Sem coordenadas: marcado apenas por nome e CEP
+		{ID: "d", Name: "Bar Brewing", PostalCode: "10001"},
+		{ID: "e", Name: "Bar Brewpub", PostalCode: "10001-0001"},
+		// Mesmo id repetido não é duplicata entre entidades
+		{ID: "f", Name: "Baz", PostalCode: "20001"},
+		{ID: "f", Name: "Baz", PostalCode: "20001"},
+		// Sem código postal não há como comparar
+		{ID: "g", Name: "Qux"},
+		{ID: "h", Name: "Qux"},
+	}
+
+	candidates := FindDuplicates(breweries, DefaultDuplicateDistance)
+	require.Len(t, candidates, 2)
+
+	assert.Equal(t, []string{"d", "e"}, candidates[0].IDs)
+	assert.Nil(t, candidates[0].DistanceMeters)
+
+	assert.Equal(t, []string{"a", "b"}, candidates[1].IDs)
+	assert.Equal(t, "foo", candidates[1].NameKey)
+	assert.Equal(t, "97201", candidates[1].PostalCode)
+	require.NotNil(t, candidates[1].DistanceMeters)
+	assert.InDelta(t, 43, *candidates[1].DistanceMeters, 10)
+}
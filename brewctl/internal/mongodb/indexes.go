@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexSpec declara um índice de uma coleção das camadas
+type IndexSpec struct {
+	Collection string
+	Model      mongo.IndexModel
+}
+
+// Name retorna o nome declarado do índice
+func (i IndexSpec) Name() string {
+	if i.Model.Options == nil || i.Model.Options.Name == nil {
+		return ""
+	}
+	return *i.Model.Options.Name
+}
+
+// LayerIndexes declara os índices atuais de bronze, silver e gold. O bronze
+// recebe o mesmo id a cada sync em append, então só a silver tem id único; é
+// esse índice que o $merge da silver usa como chave.
+func LayerIndexes() []IndexSpec {
+	return []IndexSpec{
+		rawIDIndex,
+		cleanIDIndex,
+		locationIndex,
+		textIndex,
+		goldKeyIndex,
+	}
+}
+
+var (
+	rawIDIndex = IndexSpec{RawCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetName("id"),
+	}}
+	cleanIDIndex = IndexSpec{CleanCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetName("id_unique").SetUnique(true),
+	}}
+	locationIndex = IndexSpec{CleanCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+		Options: options.Index().SetName("location_2dsphere"),
+	}}
+	textIndex = IndexSpec{CleanCollection, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "city", Value: "text"},
+			{Key: "state", Value: "text"},
+		},
+		Options: options.Index().SetName("text_search").
+			SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "city", Value: 2}, {Key: "state", Value: 1}}),
+	}}
+	goldKeyIndex = IndexSpec{GoldCollection, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "country", Value: 1},
+			{Key: "state", Value: 1},
+			{Key: "brewery_type", Value: 1},
+		},
+		Options: options.Index().SetName("country_state_type_unique").SetUnique(true),
+	}}
+)
+
+// EnsureIndexes verifica as migrations, como as agregações, e cria os índices declarados
+// em LayerIndexes que estiverem faltando. É idempotente: índices já existentes
+// com a mesma definição são mantidos.
+func (s *AggregationService) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+	return createIndexes(ctx, s.DB, LayerIndexes())
+}
@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLayerIndexes(t *testing.T) {
+	byName := map[string]IndexSpec{}
+	for _, spec := range LayerIndexes() {
+		require.NotEmpty(t, spec.Name(), "indexes must be named so EnsureIndexes stays idempotent")
+		byName[spec.Collection+"."+spec.Name()] = spec
+	}
+
+	// O bronze repete ids a cada sync em append; a silver tem um documento por id
+	raw, ok := byName[RawCollection+".id"]
+	require.True(t, ok)
+	assert.Nil(t, raw.Model.Options.Unique)
+
+	// O $merge da silver exige um índice único completo (não parcial) em id
+	clean, ok := byName[CleanCollection+".id_unique"]
+	require.True(t, ok)
+	assert.True(t, *clean.Model.Options.Unique)
+	assert.Nil(t, clean.Model.Options.PartialFilterExpression)
+
+	geo := byName[CleanCollection+".location_2dsphere"]
+	assert.Equal(t, bson.D{{Key: "location", Value: "2dsphere"}}, geo.Model.Keys)
+
+	text := byName[CleanCollection+".text_search"]
+	require.NotNil(t, text.Model.Keys)
+	for _, key := range text.Model.Keys.(bson.D) {
+		assert.Equal(t, "text", key.Value)
+	}
+
+	gold, ok := byName[GoldCollection+".country_state_type_unique"]
+	require.True(t, ok)
+	assert.True(t, *gold.Model.Options.Unique)
+	assert.Equal(t, bson.D{
+		{Key: "country", Value: 1},
+		{Key: "state", Value: 1},
+		{Key: "brewery_type", Value: 1},
+	}, gold.Model.Keys)
+}
@@ -0,0 +1,520 @@
+//go:build integration
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Os testes de integração sobem um mongod local efêmero, com dados em um
+// diretório temporário. Execute com:
+//
+//	go test -tags integration ./internal/mongodb
+//
+// O binário é procurado em MONGOD_BIN e depois no PATH.
+
+// startMongod inicia um mongod em uma porta livre e retorna a URI de conexão;
+// o processo e os dados são descartados ao fim do teste
+func startMongod(t *testing.T) string {
+	t.Helper()
+
+	bin := os.Getenv("MONGOD_BIN")
+	if bin == "" {
+		var err error
+		if bin, err = exec.LookPath("mongod"); err != nil {
+			t.Skip("mongod not found; install MongoDB or set MONGOD_BIN")
+		}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	dir := t.TempDir()
+	cmd := exec.Command(bin,
+		"--dbpath", dir,
+		"--port", fmt.Sprint(port),
+		"--bind_ip", "127.0.0.1",
+		"--nounixsocket",
+		"--logpath", filepath.Join(dir, "mongod.log"),
+	)
+	require.NoError(t, cmd.Start(), "failed to start mongod")
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		<-exited
+	})
+
+	uri := fmt.Sprintf("mongodb://127.0.0.1:%d", port)
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		select {
+		case err := <-exited:
+			logs, _ := os.ReadFile(filepath.Join(dir, "mongod.log"))
+			require.FailNowf(t, "mongod exited", "%v\n%s", err, logs)
+		default:
+		}
+		if pingMongo(uri) == nil {
+			return uri
+		}
+		require.True(t, time.Now().Before(deadline), "mongod did not accept connections on %s", uri)
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func pingMongo(uri string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetServerSelectionTimeout(time.Second))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	return client.Ping(ctx, nil)
+}
+
+// newIntegrationService conecta a um mongod efêmero e grava o bronze de
+// testdata/integration/breweries_raw.json
+func newIntegrationService(t *testing.T) *AggregationService {
+	t.Helper()
+
+	service, err := NewAggregationServiceForDatabase(startMongod(t), "breweries_it")
+	require.NoError(t, err)
+	t.Cleanup(func() { service.Close() })
+
+	var docs []interface{}
+	for _, doc := range loadFixture(t, "breweries_raw.json") {
+		docs = append(docs, doc)
+	}
+	_, err = service.DB.Collection(RawCollection).InsertMany(context.Background(), docs)
+	require.NoError(t, err)
+	return service
+}
+
+// readCollection lê a coleção na ordem informada, sem os campos voláteis
+func readCollection(t *testing.T, service *AggregationService, collection string, sort bson.D, volatile ...string) []bson.D {
+	t.Helper()
+
+	projection := bson.D{}
+	for _, field := range volatile {
+		projection = append(projection, bson.E{Key: field, Value: 0})
+	}
+	opts := options.Find().SetSort(sort).SetProjection(projection)
+
+	ctx := context.Background()
+	cursor, err := service.DB.Collection(collection).Find(ctx, bson.D{}, opts)
+	require.NoError(t, err)
+	var docs []bson.D
+	require.NoError(t, cursor.All(ctx, &docs))
+	return docs
+}
+
+// silverDoc retorna o documento da silver com o id informado
+func silverDoc(t *testing.T, service *AggregationService, id string) bson.M {
+	t.Helper()
+
+	var doc bson.M
+	err := service.DB.Collection(CleanCollection).FindOne(context.Background(), bson.D{{Key: "id", Value: id}}).Decode(&doc)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestIntegrationSilverAndGold(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+
+	silver := readCollection(t, service, CleanCollection, bson.D{{Key: "id", Value: 1}},
+		"_id", "ingestion_date", "last_updated")
+	assertDocuments(t, loadFixture(t, "breweries_clean.json"), silver)
+
+	run, err := service.LastSuccessfulRun(ctx, LayerSilver)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, RunModeIncremental, run.Mode)
+	// O watermark cobre todo o bronze, inclusive o documento sem id textual
+	assert.Equal(t, int64(7), run.Processed)
+	assert.Equal(t, "660000000000000000000007", run.Watermark.Hex())
+
+	for _, id := range []string{"b1", "b2", "b4", "b5"} {
+		doc := silverDoc(t, service, id)
+		assert.IsType(t, primitive.ObjectID{}, doc["_id"])
+		assert.Equal(t, doc["ingestion_date"], doc["last_updated"], "first load of %s", id)
+	}
+
+	require.NoError(t, service.RunGoldLayerAggregation())
+
+	gold := readCollection(t, service, GoldCollection,
+		bson.D{{Key: "country", Value: 1}, {Key: "state", Value: 1}, {Key: "brewery_type", Value: 1}},
+		"_id", "build_id", "built_at")
+	assertDocuments(t, loadFixture(t, "breweries_aggregated.json"), gold)
+
+	build, err := service.LastSuccessfulRun(ctx, LayerGold)
+	require.NoError(t, err)
+	require.NotNil(t, build)
+	assert.Equal(t, int64(3), build.Processed)
+	count, err := service.DB.Collection(GoldCollection).CountDocuments(ctx, bson.D{{Key: "build_id", Value: build.BuildID}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// O build substitui a gold com os índices da anterior
+	cursor, err := service.DB.Collection(GoldCollection).Indexes().List(ctx)
+	require.NoError(t, err)
+	var indexes []bson.M
+	require.NoError(t, cursor.All(ctx, &indexes))
+	var names []string
+	for _, index := range indexes {
+		names = append(names, index["name"].(string))
+	}
+	assert.Contains(t, names, goldKeyIndex.Name())
+}
+
+func TestIntegrationGoldDropsStaleBuilds(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	// Um build interrompido deixou a coleção temporária para trás
+	stale := goldBuildPrefix + primitive.NewObjectID().Hex()
+	require.NoError(t, service.DB.CreateCollection(ctx, stale))
+
+	builds := func() []string {
+		filter := bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: "^" + goldBuildPrefix}}}}
+		names, err := service.DB.ListCollectionNames(ctx, filter)
+		require.NoError(t, err)
+		return names
+	}
+
+	// Sem silver o build fica vazio e também não deixa coleções
+	require.NoError(t, service.RunGoldLayerAggregation())
+	assert.Empty(t, builds())
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+	require.NoError(t, service.RunGoldLayerAggregation())
+	assert.Empty(t, builds())
+}
+
+func TestIntegrationIncrementalSilver(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+	b1 := silverDoc(t, service, "b1")
+	b2 := silverDoc(t, service, "b2")
+
+	// O próximo sync traz uma nova versão de b2, com coordenadas, e repete b1 sem mudanças
+	resynced := bson.D{{Key: "_id", Value: primitive.NewObjectID()}}
+	for _, field := range loadFixture(t, "breweries_raw.json")[0] {
+		if field.Key != "_id" {
+			resynced = append(resynced, field)
+		}
+	}
+	_, err := service.DB.Collection(RawCollection).InsertMany(ctx, []interface{}{
+		bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "id", Value: "b2"},
+			{Key: "name", Value: "Anchor Brewing"},
+			{Key: "brewery_type", Value: "regional"},
+			{Key: "address_1", Value: "1705 Mariposa St"},
+			{Key: "city", Value: "San Francisco"},
+			{Key: "state", Value: "california"},
+			{Key: "postal_code", Value: "94103"},
+			{Key: "country", Value: "United States "},
+			{Key: "longitude", Value: "-122.4005"},
+			{Key: "latitude", Value: "37.7634"},
+			{Key: "updated_at", Value: "2024-04-01T00:00:00Z"},
+		},
+		resynced,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+
+	run, err := service.LastSuccessfulRun(ctx, LayerSilver)
+	require.NoError(t, err)
+	// Os 7 documentos da fixture caem na janela de overlap e são relidos
+	assert.Equal(t, int64(9), run.Processed)
+
+	updated := silverDoc(t, service, "b2")
+	assert.Equal(t, b2["_id"], updated["_id"])
+	assert.Equal(t, b2["ingestion_date"], updated["ingestion_date"])
+	assert.Equal(t, -122.4005, updated["longitude"])
+	assert.Equal(t, bson.M{"type": "Point", "coordinates": bson.A{-122.4005, 37.7634}}, updated["location"])
+	assert.Equal(t, true, updated["data_quality"].(bson.M)["has_coordinates"])
+
+	// Sem mudança de conteúdo o documento fica como estava, inclusive o last_updated
+	assert.Equal(t, b1, silverDoc(t, service, "b1"))
+
+	count, err := service.DB.Collection(CleanCollection).CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
+
+func TestIntegrationSilverUndatedVersion(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	// Na fixture, a correção de b5 sem updated_at vence a versão datada do mesmo lote
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+	assert.Nil(t, silverDoc(t, service, "b5")["updated_at"])
+
+	version := func(name, updatedAt string) bson.D {
+		return bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "id", Value: "b1"},
+			{Key: "name", Value: name},
+			{Key: "country", Value: "United States"},
+			{Key: "updated_at", Value: updatedAt},
+		}
+	}
+
+	// Uma correção sem updated_at válido substitui a versão datada da silver
+	_, err := service.DB.Collection(RawCollection).InsertOne(ctx, version("Rhinegeist (corrected)", "n/a"))
+	require.NoError(t, err)
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+	b1 := silverDoc(t, service, "b1")
+	assert.Equal(t, "Rhinegeist (corrected)", b1["name"])
+	assert.Nil(t, b1["updated_at"])
+
+	// e continua valendo contra versões datadas que cheguem depois
+	_, err = service.DB.Collection(RawCollection).InsertOne(ctx, version("Rhinegeist (dated)", "2025-01-01T00:00:00Z"))
+	require.NoError(t, err)
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+	assert.Equal(t, "Rhinegeist (corrected)", silverDoc(t, service, "b1")["name"])
+
+	// Um --full chega ao mesmo resultado
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{Full: true}))
+	assert.Equal(t, "Rhinegeist (corrected)", silverDoc(t, service, "b1")["name"])
+}
+
+func TestIntegrationSilverLateArrival(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+	first, err := service.LastSuccessfulRun(ctx, LayerSilver)
+	require.NoError(t, err)
+
+	// Outro writer grava depois da execução documentos com _id menor que o watermark
+	brewery := func(id string, at time.Time) bson.D {
+		return bson.D{
+			{Key: "_id", Value: primitive.NewObjectIDFromTimestamp(at)},
+			{Key: "id", Value: id},
+			{Key: "name", Value: "Late Brewery " + id},
+			{Key: "brewery_type", Value: "micro"},
+			{Key: "city", Value: "Portland"},
+			{Key: "state", Value: "oregon"},
+			{Key: "country", Value: "United States"},
+			{Key: "updated_at", Value: "2024-04-01T00:00:00Z"},
+		}
+	}
+	watermark := first.Watermark.Timestamp()
+	_, err = service.DB.Collection(RawCollection).InsertMany(ctx, []interface{}{
+		brewery("late", watermark.Add(-time.Minute)),
+		brewery("too-late", watermark.Add(-time.Hour)),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+
+	run, err := service.LastSuccessfulRun(ctx, LayerSilver)
+	require.NoError(t, err)
+	assert.Equal(t, first.Watermark, run.Watermark)
+	assert.Equal(t, int64(8), run.Processed)
+	assert.Equal(t, "Late Brewery late", silverDoc(t, service, "late")["name"])
+
+	// Fora da janela de overlap só uma execução --full recupera o documento
+	count, err := service.DB.Collection(CleanCollection).CountDocuments(ctx, bson.D{{Key: "id", Value: "too-late"}})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{Full: true}))
+	assert.Equal(t, "Late Brewery too-late", silverDoc(t, service, "too-late")["name"])
+}
+
+// indexesByName lista os índices da coleção pelo nome
+func indexesByName(t *testing.T, service *AggregationService, collection string) map[string]bson.M {
+	t.Helper()
+
+	ctx := context.Background()
+	cursor, err := service.DB.Collection(collection).Indexes().List(ctx)
+	require.NoError(t, err)
+	var indexes []bson.M
+	require.NoError(t, cursor.All(ctx, &indexes))
+	byName := map[string]bson.M{}
+	for _, index := range indexes {
+		byName[index["name"].(string)] = index
+	}
+	return byName
+}
+
+func TestIntegrationMigrationsWithAppendedBronze(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	// Um sync em append repete os ids da Open Brewery DB no bronze
+	duplicate := bson.D{{Key: "_id", Value: primitive.NewObjectID()}}
+	for _, field := range loadFixture(t, "breweries_raw.json")[0] {
+		if field.Key != "_id" {
+			duplicate = append(duplicate, field)
+		}
+	}
+	_, err := service.DB.Collection(RawCollection).InsertOne(ctx, duplicate)
+	require.NoError(t, err)
+
+	migrator := NewMigrator(service.DB)
+	applied, err := migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(Migrations()))
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{}))
+
+	raw := indexesByName(t, service, RawCollection)
+	require.Contains(t, raw, rawIDIndex.Name())
+	assert.Nil(t, raw[rawIDIndex.Name()]["unique"])
+	clean := indexesByName(t, service, CleanCollection)
+	assert.Equal(t, true, clean[cleanIDIndex.Name()]["unique"])
+	assert.Nil(t, clean[cleanIDIndex.Name()]["partialFilterExpression"])
+
+	// Reverter a migration 4 restaura só o índice parcial da silver
+	_, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Contains(t, indexesByName(t, service, RawCollection), rawIDIndex.Name())
+	assert.NotNil(t, indexesByName(t, service, CleanCollection)[partialCleanIDIndex.Name()]["partialFilterExpression"])
+
+	// A próxima agregação não desfaz o rollback, exceto com AutoMigrate
+	service.schemaChecked = false
+	assert.ErrorContains(t, service.RunSilverLayerAggregation(SilverOptions{}), "1 pending migration(s) (versions [4])")
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	reverted, err := migrator.Down(ctx, len(Migrations()))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(Migrations())-1)
+	assert.NotContains(t, indexesByName(t, service, RawCollection), rawIDIndex.Name())
+
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+}
+
+func TestIntegrationKeySilverDedupesInPlace(t *testing.T) {
+	service, err := NewAggregationServiceForDatabase(startMongod(t), "breweries_it")
+	require.NoError(t, err)
+	t.Cleanup(func() { service.Close() })
+	ctx := context.Background()
+
+	// Silver de antes da chave por id: versões repetidas e documento sem id
+	older := primitive.NewObjectIDFromTimestamp(time.Unix(1700000000, 0))
+	newer := primitive.NewObjectIDFromTimestamp(time.Unix(1700000100, 0))
+	latest := primitive.NewObjectIDFromTimestamp(time.Unix(1700000200, 0))
+	_, err = service.DB.Collection(CleanCollection).InsertMany(ctx, []interface{}{
+		bson.D{{Key: "_id", Value: older}, {Key: "id", Value: "b1"}, {Key: "updated_at", Value: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}},
+		bson.D{{Key: "_id", Value: newer}, {Key: "id", Value: "b1"}, {Key: "updated_at", Value: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		bson.D{{Key: "_id", Value: latest}, {Key: "id", Value: "b2"}},
+		bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "name", Value: "no id"}},
+	})
+	require.NoError(t, err)
+
+	removed, err := dedupeSilverByID(ctx, service.DB)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed)
+
+	docs := readCollection(t, service, CleanCollection, bson.D{{Key: "id", Value: 1}})
+	require.Len(t, docs, 2)
+	assert.Equal(t, older, docs[0].Map()["_id"], "the version with the latest updated_at is kept")
+	assert.Equal(t, latest, docs[1].Map()["_id"])
+
+	// Com a silver já deduplicada o índice único é criado sem perder dados
+	require.NoError(t, keySilverOnBreweryID(ctx, service.DB))
+	count, err := service.DB.Collection(CleanCollection).CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestIntegrationCleanBreweryParity(t *testing.T) {
+	service := newIntegrationService(t)
+	ctx := context.Background()
+
+	// Além das fixtures, valores que exercitam as conversões e a normalização
+	extra := []interface{}{
+		bson.D{
+			{Key: "id", Value: "p1"},
+			{Key: "country", Value: "US"},
+			{Key: "phone", Value: int64(5135550100)},
+			{Key: "postal_code", Value: int32(45202)},
+			{Key: "longitude", Value: int32(-84)},
+			{Key: "latitude", Value: int32(39)},
+		},
+		bson.D{
+			{Key: "id", Value: "p2"},
+			{Key: "country", Value: "usa"},
+			{Key: "state", Value: "OHIO"},
+			{Key: "website_url", Value: "https://Foo.Example/Path?q=1"},
+			{Key: "updated_at", Value: "2024-05-01T12:30:00.123Z"},
+		},
+		bson.D{
+			{Key: "id", Value: "p3"},
+			{Key: "country", Value: "DE"},
+			{Key: "phone", Value: "0049 30 1234567"},
+			{Key: "postal_code", Value: "10115"},
+			{Key: "longitude", Value: "13.4"},
+			{Key: "latitude", Value: "not a number"},
+			{Key: "updated_at", Value: "yesterday"},
+		},
+		bson.D{
+			{Key: "id", Value: "p4"},
+			{Key: "name", Value: "\u00a0Stiegl\u00a0"},
+			{Key: "city", Value: "\tSalzburg\n"},
+			{Key: "country", Value: "Österreich"},
+			{Key: "phone", Value: "+43 662 83870"},
+		},
+		bson.D{
+			{Key: "id", Value: "p5"},
+			{Key: "country", Value: nil},
+			{Key: "state", Value: nil},
+			{Key: "longitude", Value: "200"},
+			{Key: "latitude", Value: "45"},
+			{Key: "website_url", Value: "   "},
+		},
+		bson.D{
+			{Key: "id", Value: "p6"},
+			{Key: "country", Value: " Viet Nam "},
+			{Key: "phone", Value: "028 3823 4567"},
+		},
+		bson.D{
+			{Key: "id", Value: "p7"},
+			{Key: "country", Value: "Kenya"},
+			{Key: "phone", Value: " ext. 12 "},
+		},
+	}
+	_, err := service.DB.Collection(RawCollection).InsertMany(ctx, extra)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RunSilverLayerAggregation(SilverOptions{Full: true}))
+
+	raws := loadFixture(t, "breweries_raw.json")
+	for _, doc := range extra {
+		raws = append(raws, doc.(bson.D))
+	}
+	silver := readCollection(t, service, CleanCollection, bson.D{{Key: "id", Value: 1}},
+		"_id", "ingestion_date", "last_updated")
+	assertDocuments(t, cleanLatest(raws), silver)
+}
@@ -0,0 +1,462 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SchemaMigrationsCollection registra as migrations aplicadas, uma por versão
+const SchemaMigrationsCollection = "schema_migrations"
+
+// Migration é uma mudança versionada de layout, validators ou índices das
+// coleções, aplicada em ordem de versão e revertida por Down.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationRecord é o registro de uma migration aplicada em schema_migrations
+type MigrationRecord struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// MigrationStatus combina uma migration conhecida com o seu registro, se aplicada
+type MigrationStatus struct {
+	Version     int
+	Description string
+	Applied     *MigrationRecord
+}
+
+// Migrations retorna as migrations do brewctl em ordem de versão
+func Migrations() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "create layer indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db, initialLayerIndexes())
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db, initialLayerIndexes())
+			},
+		},
+		{
+			Version:     2,
+			Description: "JSON Schema validator on " + CleanCollection,
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return setValidator(ctx, db, CleanCollection, CleanValidator())
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return removeValidator(ctx, db, CleanCollection)
+			},
+		},
+		{
+			Version:     3,
+			Description: "JSON Schema validator on " + GoldCollection,
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return setValidator(ctx, db, GoldCollection, GoldValidator())
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return removeValidator(ctx, db, GoldCollection)
+			},
+		},
+		{
+			Version:     4,
+			Description: "key silver on the brewery id",
+			Up:          keySilverOnBreweryID,
+			Down:        keySilverOnBronzeID,
+		},
+	}
+}
+
+// initialLayerIndexes são os índices criados pela migration 1. O bronze recebe
+// o mesmo id a cada sync em append, então o seu índice em id não é único.
+func initialLayerIndexes() []IndexSpec {
+	return []IndexSpec{
+		rawIDIndex,
+		partialCleanIDIndex,
+		locationIndex,
+		textIndex,
+		goldKeyIndex,
+	}
+}
+
+// partialCleanIDIndex é o id único da silver criado pela migration 1, restrito
+// aos documentos com id textual
+var partialCleanIDIndex = IndexSpec{CleanCollection, mongo.IndexModel{
+	Keys: bson.D{{Key: "id", Value: 1}},
+	Options: options.Index().SetName("id_unique").SetUnique(true).
+		SetPartialFilterExpression(bson.D{{Key: "id", Value: bson.D{{Key: "$type", Value: "string"}}}}),
+}}
+
+// keySilverOnBreweryID torna o id da silver uma chave única completa, exigida
+// pelo $merge. A silver é deduplicada no lugar antes do índice, então os dados
+// e o watermark das execuções anteriores continuam valendo.
+func keySilverOnBreweryID(ctx context.Context, db *mongo.Database) error {
+	removed, err := dedupeSilverByID(ctx, db)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		fmt.Printf("🧹 Removed %d duplicated or id-less documents from %s\n", removed, CleanCollection)
+	}
+
+	if err := dropIndexes(ctx, db, []IndexSpec{partialCleanIDIndex}); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db, []IndexSpec{cleanIDIndex})
+}
+
+// dedupeSilverByID deixa um documento por id na silver, o mais novo na ordem
+// de newestFirstStages, como o $merge da silver faria. Documentos sem id textual, que a silver atual não grava, são removidos.
+func dedupeSilverByID(ctx context.Context, db *mongo.Database) (int64, error) {
+	clean := db.Collection(CleanCollection)
+
+	withoutID, err := clean.DeleteMany(ctx, bson.D{{Key: "id", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$type", Value: "string"}}}}}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove documents without id from %s: %v", CleanCollection, err)
+	}
+	removed := withoutID.DeletedCount
+
+	cursor, err := clean.Aggregate(ctx, duplicateSilverIDsPipeline(), options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return removed, fmt.Errorf("failed to find duplicated ids in %s: %v", CleanCollection, err)
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var group struct {
+			IDs []interface{} `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return removed, fmt.Errorf("failed to read duplicated ids in %s: %v", CleanCollection, err)
+		}
+		// O primeiro _id é a versão mantida
+		result, err := clean.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: group.IDs[1:]}}}})
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove duplicated documents from %s: %v", CleanCollection, err)
+		}
+		removed += result.DeletedCount
+	}
+	if err := cursor.Err(); err != nil {
+		return removed, fmt.Errorf("failed to read duplicated ids in %s: %v", CleanCollection, err)
+	}
+	return removed, nil
+}
+
+// duplicateSilverIDsPipeline agrupa os _id da silver por id, do documento
+// mantido para os descartados, apenas para ids repetidos
+func duplicateSilverIDsPipeline() mongo.Pipeline {
+	return append(newestFirstStages(), mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$id"},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "ids.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+	}...)
+}
+
+// keySilverOnBronzeID restaura o id único parcial da silver da migration 1. A
+// silver continua deduplicada, então o índice pode ser recriado; a próxima
+// execução completa volta a gravar por _id do bronze.
+func keySilverOnBronzeID(ctx context.Context, db *mongo.Database) error {
+	if err := dropIndexes(ctx, db, []IndexSpec{cleanIDIndex}); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db, []IndexSpec{partialCleanIDIndex})
+}
+
+// ensureSchema garante que índices e validators estejam na versão esperada
+// pelo brewctl antes das agregações. Um banco nunca migrado, ou com
+// AutoMigrate, recebe as migrations pendentes; nos demais casos as pendências
+// são um erro, para que um `db migrate down` não seja desfeito pela próxima
+// agregação.
+func (s *AggregationService) ensureSchema(ctx context.Context) error {
+	if s.schemaChecked {
+		return nil
+	}
+
+	migrator := NewMigrator(s.DB)
+	initialized, err := collectionExists(ctx, s.DB, SchemaMigrationsCollection)
+	if err != nil {
+		return err
+	}
+	if s.AutoMigrate || !initialized {
+		if _, err := migrator.Up(ctx, 0); err != nil {
+			return err
+		}
+		s.schemaChecked = true
+		return nil
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("schema has %d pending migration(s) (versions %v); run `brewctl db migrate up` or pass --migrate",
+			len(pending), versionsOf(pending))
+	}
+	s.schemaChecked = true
+	return nil
+}
+
+// Migrator aplica e reverte migrations registrando-as em schema_migrations
+type Migrator struct {
+	DB         *mongo.Database
+	Migrations []Migration
+}
+
+// NewMigrator cria um Migrator com as migrations do brewctl
+func NewMigrator(db *mongo.Database) *Migrator {
+	return &Migrator{DB: db, Migrations: Migrations()}
+}
+
+// Status lista todas as migrations conhecidas e quando foram aplicadas
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := validateMigrations(m.Migrations); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+	for _, migration := range m.Migrations {
+		s := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			s.Applied = &record
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// Pending lista as migrations ainda não aplicadas, em ordem de versão
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	if err := validateMigrations(m.Migrations); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return planUp(m.Migrations, applied, 0), nil
+}
+
+// Up aplica as migrations pendentes até a versão target (0 aplica todas) e
+// retorna as que foram aplicadas
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	if err := validateMigrations(m.Migrations); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range planUp(m.Migrations, applied, target) {
+		fmt.Printf("⬆️ Applying migration %d: %s\n", migration.Version, migration.Description)
+		if err := migration.Up(ctx, m.DB); err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Description, err)
+		}
+		record := MigrationRecord{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now().UTC()}
+		if _, err := m.DB.Collection(SchemaMigrationsCollection).InsertOne(ctx, record); err != nil {
+			return done, fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverte as últimas steps migrations aplicadas, da mais recente para a
+// mais antiga, e retorna as que foram revertidas
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := validateMigrations(m.Migrations); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range planDown(m.Migrations, applied, steps) {
+		fmt.Printf("⬇️ Reverting migration %d: %s\n", migration.Version, migration.Description)
+		if err := migration.Down(ctx, m.DB); err != nil {
+			return done, fmt.Errorf("rollback of migration %d (%s) failed: %v", migration.Version, migration.Description, err)
+		}
+		if _, err := m.DB.Collection(SchemaMigrationsCollection).DeleteOne(ctx, bson.D{{Key: "_id", Value: migration.Version}}); err != nil {
+			return done, fmt.Errorf("failed to unrecord migration %d: %v", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]MigrationRecord, error) {
+	cursor, err := m.DB.Collection(SchemaMigrationsCollection).Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", SchemaMigrationsCollection, err)
+	}
+	var records []MigrationRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", SchemaMigrationsCollection, err)
+	}
+
+	applied := map[int]MigrationRecord{}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// validateMigrations exige versões positivas, únicas e em ordem crescente
+func validateMigrations(migrations []Migration) error {
+	last := 0
+	for _, migration := range migrations {
+		if migration.Version <= last {
+			return fmt.Errorf("migration %d is out of order (after %d)", migration.Version, last)
+		}
+		if migration.Up == nil || migration.Down == nil {
+			return fmt.Errorf("migration %d must define Up and Down", migration.Version)
+		}
+		last = migration.Version
+	}
+	return nil
+}
+
+// planUp seleciona as migrations pendentes até target (0 seleciona todas)
+func planUp(migrations []Migration, applied map[int]MigrationRecord, target int) []Migration {
+	var plan []Migration
+	for _, migration := range migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			plan = append(plan, migration)
+		}
+	}
+	return plan
+}
+
+func versionsOf(migrations []Migration) []int {
+	versions := make([]int, len(migrations))
+	for i, migration := range migrations {
+		versions[i] = migration.Version
+	}
+	return versions
+}
+
+// planDown seleciona as últimas steps migrations aplicadas, da mais recente
+// para a mais antiga
+func planDown(migrations []Migration, applied map[int]MigrationRecord, steps int) []Migration {
+	var plan []Migration
+	for i := len(migrations) - 1; i >= 0 && len(plan) < steps; i-- {
+		if _, ok := applied[migrations[i].Version]; ok {
+			plan = append(plan, migrations[i])
+		}
+	}
+	return plan
+}
+
+// createIndexes cria os índices declarados, agrupados por coleção
+func createIndexes(ctx context.Context, db *mongo.Database, specs []IndexSpec) error {
+	byCollection := map[string][]mongo.IndexModel{}
+	var collections []string
+	for _, spec := range specs {
+		if _, ok := byCollection[spec.Collection]; !ok {
+			collections = append(collections, spec.Collection)
+		}
+		byCollection[spec.Collection] = append(byCollection[spec.Collection], spec.Model)
+	}
+
+	for _, collection := range collections {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, byCollection[collection]); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %v", collection, err)
+		}
+	}
+	return nil
+}
+
+// dropIndexes remove os índices declarados, ignorando os que já não existem
+func dropIndexes(ctx context.Context, db *mongo.Database, specs []IndexSpec) error {
+	for _, spec := range specs {
+		_, err := db.Collection(spec.Collection).Indexes().DropOne(ctx, spec.Name())
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to drop index %s on %s: %v", spec.Name(), spec.Collection, err)
+		}
+	}
+	return nil
+}
+
+// setValidator aplica o validator à coleção, criando-a se ainda não existir.
+// validationLevel moderate não bloqueia updates de documentos antigos inválidos.
+func setValidator(ctx context.Context, db *mongo.Database, collection string, validator bson.D) error {
+	exists, err := collectionExists(ctx, db, collection)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		opts := options.CreateCollection().
+			SetValidator(validator).
+			SetValidationLevel("moderate").
+			SetValidationAction("error")
+		if err := db.CreateCollection(ctx, collection, opts); err != nil {
+			return fmt.Errorf("failed to create %s: %v", collection, err)
+		}
+		return nil
+	}
+
+	cmd := bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "moderate"},
+		{Key: "validationAction", Value: "error"},
+	}
+	if err := db.RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("failed to set validator on %s: %v", collection, err)
+	}
+	return nil
+}
+
+// removeValidator desliga a validação da coleção
+func removeValidator(ctx context.Context, db *mongo.Database, collection string) error {
+	cmd := bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: bson.D{}},
+		{Key: "validationLevel", Value: "off"},
+	}
+	if err := db.RunCommand(ctx, cmd).Err(); err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to remove validator from %s: %v", collection, err)
+	}
+	return nil
+}
+
+func collectionExists(ctx context.Context, db *mongo.Database, collection string) (bool, error) {
+	names, err := db.ListCollectionNames(ctx, bson.D{{Key: "name", Value: collection}})
+	if err != nil {
+		return false, fmt.Errorf("failed to list collections: %v", err)
+	}
+	return len(names) > 0, nil
+}
+
+// isNotFound reconhece NamespaceNotFound (26) e IndexNotFound (27)
+func isNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == 26 || cmdErr.Code == 27
+	}
+	return false
+}
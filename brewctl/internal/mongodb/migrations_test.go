@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func noop(context.Context, *mongo.Database) error { return nil }
+
+func testMigrations(versions ...int) []Migration {
+	var migrations []Migration
+	for _, v := range versions {
+		migrations = append(migrations, Migration{Version: v, Up: noop, Down: noop})
+	}
+	return migrations
+}
+
+func TestMigrationsAreValid(t *testing.T) {
+	require.NoError(t, validateMigrations(Migrations()))
+
+	assert.Error(t, validateMigrations(testMigrations(1, 3, 2)), "out of order")
+	assert.Error(t, validateMigrations(testMigrations(1, 1)), "duplicated version")
+	assert.Error(t, validateMigrations(testMigrations(0)), "versions start at 1")
+	assert.Error(t, validateMigrations([]Migration{{Version: 1, Up: noop}}), "missing Down")
+}
+
+func TestPlanUp(t *testing.T) {
+	migrations := testMigrations(1, 2, 3, 4)
+	applied := map[int]MigrationRecord{1: {Version: 1}, 3: {Version: 3}}
+
+	assert.Equal(t, []int{2, 4}, versionsOf(planUp(migrations, applied, 0)))
+	assert.Equal(t, []int{2}, versionsOf(planUp(migrations, applied, 3)))
+	assert.Empty(t, planUp(migrations, applied, 1))
+}
+
+func TestPlanDown(t *testing.T) {
+	migrations := testMigrations(1, 2, 3, 4)
+	applied := map[int]MigrationRecord{1: {Version: 1}, 2: {Version: 2}, 3: {Version: 3}}
+
+	assert.Equal(t, []int{3}, versionsOf(planDown(migrations, applied, 1)))
+	assert.Equal(t, []int{3, 2, 1}, versionsOf(planDown(migrations, applied, 10)))
+	assert.Empty(t, planDown(migrations, map[int]MigrationRecord{}, 1))
+}
+
+func TestInitialLayerIndexes(t *testing.T) {
+	// Com o bronze já carregado em append, um id único no bronze faria a
+	// migration 1 falhar com E11000 e bloquearia as agregações
+	for _, spec := range initialLayerIndexes() {
+		if spec.Collection == RawCollection {
+			assert.Nil(t, spec.Model.Options.Unique, "index %s on %s", spec.Name(), RawCollection)
+		}
+	}
+	assert.Contains(t, initialLayerIndexes(), rawIDIndex)
+}
+
+// requiredFields lê a lista required de um validator $jsonSchema
+func requiredFields(t *testing.T, validator bson.D) []string {
+	schema := validator.Map()["$jsonSchema"].(bson.D).Map()
+	var fields []string
+	for _, f := range schema["required"].(bson.A) {
+		fields = append(fields, f.(string))
+	}
+	return fields
+}
+
+func TestValidatorsMatchPipelines(t *testing.T) {
+	// Todo campo obrigatório no validator precisa ser gerado pelo pipeline
+	written := map[string]bool{}
+	for _, st := range SilverPipeline(bson.D{}, DefaultQualityModel()) {
+		if st[0].Key != "$project" && st[0].Key != "$set" {
+			continue
+		}
+		for _, field := range st[0].Value.(bson.D) {
+			written[strings.SplitN(field.Key, ".", 2)[0]] = true
+		}
+	}
+	for _, field := range requiredFields(t, CleanValidator()) {
+		assert.True(t, written[field], "silver must write %s", field)
+	}
+
+	gold := GoldPipeline("build-1", 0.6, "out")
+	goldProject := gold[len(gold)-2][0].Value.(bson.D).Map()
+	for _, field := range requiredFields(t, GoldValidator()) {
+		assert.Contains(t, goldProject, field, "gold must write %s", field)
+	}
+}
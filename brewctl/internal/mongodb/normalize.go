@@ -0,0 +1,227 @@
+package mongodb
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// As tabelas abaixo são a fonte única da normalização da silver: os estágios
+// do pipeline são gerados a partir delas (normalize_pipeline.go) e as funções
+// Normalize* aplicam as mesmas regras em Go.
+
+// countryAliases mapeia cada código ISO-3166 alfa-2 para os nomes, em
+// minúsculas, usados pelas fontes. Códigos alfa-2 válidos passam direto.
+var countryAliases = func() map[string][]string {
+	aliases := make(map[string][]string, len(isoCountries))
+	for _, c := range isoCountries {
+		aliases[c.code] = c.names
+	}
+	return aliases
+}()
+
+// subdivisionCodes mapeia, por país, o nome em minúsculas de cada estado ou
+// província para o seu código (USPS nos Estados Unidos)
+var subdivisionCodes = map[string]map[string]string{
+	"US": {
+		"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR", "california": "CA",
+		"colorado": "CO", "connecticut": "CT", "delaware": "DE", "district of columbia": "DC",
+		"washington dc": "DC", "washington d.c.": "DC", "florida": "FL", "georgia": "GA",
+		"hawaii": "HI", "idaho": "ID", "illinois": "IL", "indiana": "IN", "iowa": "IA",
+		"kansas": "KS", "kentucky": "KY", "louisiana": "LA", "maine": "ME", "maryland": "MD",
+		"massachusetts": "MA", "michigan": "MI", "minnesota": "MN", "mississippi": "MS",
+		"missouri": "MO", "montana": "MT", "nebraska": "NE", "nevada": "NV",
+		"new hampshire": "NH", "new jersey": "NJ", "new mexico": "NM", "new york": "NY",
+		"north carolina": "NC", "north dakota": "ND", "ohio": "OH", "oklahoma": "OK",
+		"oregon": "OR", "pennsylvania": "PA", "rhode island": "RI", "south carolina": "SC",
+		"south dakota": "SD", "tennessee": "TN", "texas": "TX", "utah": "UT", "vermont": "VT",
+		"virginia": "VA", "washington": "WA", "west virginia": "WV", "wisconsin": "WI",
+		"wyoming": "WY", "american samoa": "AS", "guam": "GU", "northern mariana islands": "MP",
+		"puerto rico": "PR", "u.s. virgin islands": "VI", "virgin islands": "VI",
+	},
+	"CA": {
+		"alberta": "AB", "british columbia": "BC", "manitoba": "MB", "new brunswick": "NB",
+		"newfoundland and labrador": "NL", "nova scotia": "NS", "northwest territories": "NT",
+		"nunavut": "NU", "ontario": "ON", "prince edward island": "PE", "quebec": "QC",
+		"québec": "QC", "saskatchewan": "SK", "yukon": "YT",
+	},
+	"AU": {
+		"australian capital territory": "ACT", "new south wales": "NSW", "northern territory": "NT",
+		"queensland": "QLD", "south australia": "SA", "tasmania": "TAS", "victoria": "VIC",
+		"western australia": "WA",
+	},
+}
+
+// callingCodes é o código telefônico internacional de cada país
+var callingCodes = func() map[string]string {
+	codes := make(map[string]string, len(isoCountries))
+	for _, c := range isoCountries {
+		codes[c.code] = c.callingCode
+	}
+	return codes
+}()
+
+// Expressões compartilhadas com o pipeline; a sintaxe é a mesma no Go e no MongoDB
+const (
+	websitePattern = `^\s*(?:([A-Za-z][A-Za-z0-9+.-]*)://)?([^/?#\s]+)(\S*)\s*$`
+	zipPattern     = `^\s*([0-9]{5})(?:[-\s]?([0-9]{4}))?\s*$`
+	alpha2Pattern  = `^[A-Za-z]{2}$`
+	// internationalPattern reconhece telefones já com o código do país
+	internationalPattern = `^\s*\+`
+)
+
+var (
+	websiteRegexp = regexp.MustCompile(websitePattern)
+	zipRegexp     = regexp.MustCompile(zipPattern)
+	alpha2Regexp  = regexp.MustCompile(alpha2Pattern)
+	digitsRegexp  = regexp.MustCompile(`[0-9]`)
+
+	internationalRegexp = regexp.MustCompile(internationalPattern)
+)
+
+// sortedKeys retorna as chaves do mapa em ordem, para gerar pipelines estáveis
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// trimSpace remove das pontas os mesmos caracteres que o $trim do MongoDB
+func trimSpace(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		switch r {
+		case 0, ' ', '\t', '\n', '\v', '\f', '\r', 0x00A0, 0x1680, 0x2028, 0x2029, 0x202F, 0x205F, 0x3000:
+			return true
+		}
+		return r >= 0x2000 && r <= 0x200A
+	})
+}
+
+// asciiLower e asciiUpper seguem o $toLower e o $toUpper do MongoDB, que só
+// alteram letras ASCII
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+func asciiUpper(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, s)
+}
+
+// NormalizeCountry converte o nome do país para o código ISO-3166 alfa-2.
+// Países desconhecidos são mantidos como vieram, sem espaços nas pontas.
+func NormalizeCountry(country string) string {
+	trimmed := trimSpace(country)
+	lower := asciiLower(trimmed)
+	for _, code := range sortedKeys(countryAliases) {
+		for _, alias := range countryAliases[code] {
+			if lower == alias {
+				return code
+			}
+		}
+	}
+	if alpha2Regexp.MatchString(trimmed) {
+		return asciiUpper(trimmed)
+	}
+	return trimmed
+}
+
+// NormalizeSubdivision retorna o código do estado ou província no país
+// (código ISO já normalizado), ou "" se não for conhecido
+func NormalizeSubdivision(country, state string) string {
+	codes, ok := subdivisionCodes[country]
+	if !ok {
+		return ""
+	}
+	lower := asciiLower(trimSpace(state))
+	if code, ok := codes[lower]; ok {
+		return code
+	}
+	// Estados que já vêm como código
+	for _, code := range codes {
+		if asciiLower(code) == lower {
+			return code
+		}
+	}
+	return ""
+}
+
+// NormalizePhone formata o telefone em E.164 usando o código do país para
+// números nacionais. Números que não podem ser interpretados são mantidos
+// como vieram, sem espaços nas pontas.
+func NormalizePhone(country, phone string) string {
+	digits := strings.Join(digitsRegexp.FindAllString(phone, -1), "")
+	international := internationalRegexp.MatchString(phone)
+	code := callingCodes[country]
+
+	var e164 string
+	switch {
+	case international:
+		e164 = digits
+	case strings.HasPrefix(digits, "00"):
+		e164 = digits[2:]
+	case code == "1":
+		// Plano de numeração norte-americano: 10 dígitos, com ou sem o 1
+		switch {
+		case len(digits) == 10:
+			e164 = "1" + digits
+		case len(digits) == 11 && strings.HasPrefix(digits, "1"):
+			e164 = digits
+		}
+	case code == "":
+	case strings.HasPrefix(digits, "0"):
+		// Prefixo de tronco nacional
+		e164 = code + digits[1:]
+	case strings.HasPrefix(digits, code) && len(digits) >= len(code)+8:
+		e164 = digits
+	default:
+		e164 = code + digits
+	}
+
+	if len(e164) < 8 || len(e164) > 15 || strings.HasPrefix(e164, "0") {
+		return trimSpace(phone)
+	}
+	return "+" + e164
+}
+
+// NormalizeWebsite canonicaliza a URL: esquema (http se ausente) e host em
+// minúsculas e barra final quando não há caminho. Retorna "" se não houver host.
+func NormalizeWebsite(url string) string {
+	m := websiteRegexp.FindStringSubmatch(url)
+	if m == nil {
+		return ""
+	}
+	scheme, host, rest := asciiLower(m[1]), asciiLower(m[2]), m[3]
+	if scheme == "" {
+		scheme = "http"
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return scheme + "://" + host + rest
+}
+
+// SplitZIP separa um CEP americano em ZIP5 e ZIP+4 (vazio se ausente). Fora
+// dos Estados Unidos, ou com formato inválido, retorna vazio.
+func SplitZIP(country, postalCode string) (zip5, zip4 string) {
+	if country != "US" {
+		return "", ""
+	}
+	m := zipRegexp.FindStringSubmatch(postalCode)
+	if m == nil {
+		return "", ""
+	}
+	return m[1], m[2]
+}
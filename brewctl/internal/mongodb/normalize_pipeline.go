@@ -0,0 +1,248 @@
+package mongodb
+
+import (
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// normalizationStages geram, a partir das tabelas de normalize.go, os estágios
+// que padronizam país, estado, telefone, site e CEP da silver. O país vem em
+// um estágio próprio porque as demais regras dependem do código já normalizado.
+func normalizationStages() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "country", Value: countryExpr("$country")},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "state_code", Value: subdivisionExpr("$country", "$state")},
+			{Key: "phone", Value: phoneExpr("$country", "$phone")},
+			{Key: "website_url", Value: websiteExpr("$website_url")},
+			{Key: "zip5", Value: zipPartExpr("$country", "$postal_code", 0)},
+			{Key: "zip4", Value: zipPartExpr("$country", "$postal_code", 1)},
+			{Key: "location", Value: locationPoint()},
+		}}},
+	}
+}
+
+// asString converte o valor para texto; null, ausente ou não conversível vira ""
+func asString(expr interface{}) bson.D {
+	return bson.D{{Key: "$convert", Value: bson.D{
+		{Key: "input", Value: expr},
+		{Key: "to", Value: "string"},
+		{Key: "onError", Value: ""},
+		{Key: "onNull", Value: ""},
+	}}}
+}
+
+// countryExpr é a versão em pipeline de NormalizeCountry; null continua null
+func countryExpr(country string) bson.D {
+	var branches bson.A
+	for _, code := range sortedKeys(countryAliases) {
+		branches = append(branches, bson.D{
+			{Key: "case", Value: bson.D{{Key: "$in", Value: bson.A{"$$lower", stringsToA(countryAliases[code])}}}},
+			{Key: "then", Value: code},
+		})
+	}
+
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "raw", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: country}}}}}}},
+		{Key: "in", Value: bson.D{{Key: "$let", Value: bson.D{
+			{Key: "vars", Value: bson.D{{Key: "lower", Value: bson.D{{Key: "$toLower", Value: "$$raw"}}}}},
+			{Key: "in", Value: bson.D{{Key: "$switch", Value: bson.D{
+				{Key: "branches", Value: branches},
+				{Key: "default", Value: bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$regexMatch", Value: bson.D{
+						{Key: "input", Value: asString("$$raw")},
+						{Key: "regex", Value: alpha2Pattern},
+					}}},
+					bson.D{{Key: "$toUpper", Value: "$$raw"}},
+					"$$raw",
+				}}}},
+			}}}},
+		}}}},
+	}}}
+}
+
+// subdivisionExpr é a versão em pipeline de NormalizeSubdivision; estados
+// desconhecidos viram null
+func subdivisionExpr(country, state string) bson.D {
+	var branches bson.A
+	for _, countryCode := range sortedKeys(subdivisionCodes) {
+		names := map[string][]string{}
+		for name, code := range subdivisionCodes[countryCode] {
+			names[code] = append(names[code], name)
+		}
+		for _, code := range sortedKeys(names) {
+			accepted := append(names[code], strings.ToLower(code))
+			sort.Strings(accepted)
+			branches = append(branches, bson.D{
+				{Key: "case", Value: bson.D{{Key: "$and", Value: bson.A{
+					bson.D{{Key: "$eq", Value: bson.A{country, countryCode}}},
+					bson.D{{Key: "$in", Value: bson.A{"$$state", stringsToA(accepted)}}},
+				}}}},
+				{Key: "then", Value: code},
+			})
+		}
+	}
+
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "state", Value: bson.D{{Key: "$toLower", Value: bson.D{
+			{Key: "$trim", Value: bson.D{{Key: "input", Value: asString(state)}}},
+		}}}}}},
+		{Key: "in", Value: bson.D{{Key: "$switch", Value: bson.D{
+			{Key: "branches", Value: branches},
+			{Key: "default", Value: nil},
+		}}}},
+	}}}
+}
+
+// phoneExpr é a versão em pipeline de NormalizePhone; números que não podem
+// ser interpretados mantêm o valor original sem espaços nas pontas, e só
+// telefones vazios viram null
+func phoneExpr(country, phone string) bson.D {
+	// Um ramo por código telefônico, com todos os países que o usam
+	countries := map[string][]string{}
+	for _, countryCode := range sortedKeys(callingCodes) {
+		code := callingCodes[countryCode]
+		countries[code] = append(countries[code], countryCode)
+	}
+	var codes bson.A
+	for _, code := range sortedKeys(countries) {
+		codes = append(codes, bson.D{
+			{Key: "case", Value: bson.D{{Key: "$in", Value: bson.A{country, stringsToA(countries[code])}}}},
+			{Key: "then", Value: code},
+		})
+	}
+
+	length := bson.D{{Key: "$strLenCP", Value: "$$digits"}}
+	prefix := func(n interface{}) bson.D {
+		return bson.D{{Key: "$substrCP", Value: bson.A{"$$digits", 0, n}}}
+	}
+	from := func(start int) bson.D {
+		return bson.D{{Key: "$substrCP", Value: bson.A{"$$digits", start, length}}}
+	}
+
+	e164 := bson.D{{Key: "$switch", Value: bson.D{
+		{Key: "branches", Value: bson.A{
+			bson.D{{Key: "case", Value: "$$international"}, {Key: "then", Value: "$$digits"}},
+			bson.D{{Key: "case", Value: bson.D{{Key: "$eq", Value: bson.A{prefix(2), "00"}}}}, {Key: "then", Value: from(2)}},
+			// Plano de numeração norte-americano: 10 dígitos, com ou sem o 1
+			bson.D{{Key: "case", Value: bson.D{{Key: "$eq", Value: bson.A{"$$code", "1"}}}}, {Key: "then", Value: bson.D{{Key: "$switch", Value: bson.D{
+				{Key: "branches", Value: bson.A{
+					bson.D{{Key: "case", Value: bson.D{{Key: "$eq", Value: bson.A{length, 10}}}}, {Key: "then", Value: bson.D{{Key: "$concat", Value: bson.A{"1", "$$digits"}}}}},
+					bson.D{{Key: "case", Value: bson.D{{Key: "$and", Value: bson.A{
+						bson.D{{Key: "$eq", Value: bson.A{length, 11}}},
+						bson.D{{Key: "$eq", Value: bson.A{prefix(1), "1"}}},
+					}}}}, {Key: "then", Value: "$$digits"}},
+				}},
+				{Key: "default", Value: ""},
+			}}}}},
+			bson.D{{Key: "case", Value: bson.D{{Key: "$eq", Value: bson.A{"$$code", ""}}}}, {Key: "then", Value: ""}},
+			// Prefixo de tronco nacional
+			bson.D{{Key: "case", Value: bson.D{{Key: "$eq", Value: bson.A{prefix(1), "0"}}}}, {Key: "then", Value: bson.D{{Key: "$concat", Value: bson.A{"$$code", from(1)}}}}},
+			bson.D{{Key: "case", Value: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{prefix(bson.D{{Key: "$strLenCP", Value: "$$code"}}), "$$code"}}},
+				bson.D{{Key: "$gte", Value: bson.A{length, bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$strLenCP", Value: "$$code"}}, 8}}}}}},
+			}}}}, {Key: "then", Value: "$$digits"}},
+		}},
+		{Key: "default", Value: bson.D{{Key: "$concat", Value: bson.A{"$$code", "$$digits"}}}},
+	}}}
+
+	valid := bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$gte", Value: bson.A{bson.D{{Key: "$strLenCP", Value: "$$e164"}}, 8}}},
+		bson.D{{Key: "$lte", Value: bson.A{bson.D{{Key: "$strLenCP", Value: "$$e164"}}, 15}}},
+		bson.D{{Key: "$ne", Value: bson.A{bson.D{{Key: "$substrCP", Value: bson.A{"$$e164", 0, 1}}}, "0"}}},
+	}}}
+
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{
+			{Key: "digits", Value: bson.D{{Key: "$reduce", Value: bson.D{
+				{Key: "input", Value: bson.D{{Key: "$regexFindAll", Value: bson.D{
+					{Key: "input", Value: asString(phone)},
+					{Key: "regex", Value: "[0-9]"},
+				}}}},
+				{Key: "initialValue", Value: ""},
+				{Key: "in", Value: bson.D{{Key: "$concat", Value: bson.A{"$$value", "$$this.match"}}}},
+			}}}},
+			{Key: "international", Value: bson.D{{Key: "$regexMatch", Value: bson.D{
+				{Key: "input", Value: asString(phone)},
+				{Key: "regex", Value: internationalPattern},
+			}}}},
+			{Key: "original", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: asString(phone)}}}}},
+			{Key: "code", Value: bson.D{{Key: "$switch", Value: bson.D{
+				{Key: "branches", Value: codes},
+				{Key: "default", Value: ""},
+			}}}},
+		}},
+		{Key: "in", Value: bson.D{{Key: "$let", Value: bson.D{
+			{Key: "vars", Value: bson.D{{Key: "e164", Value: e164}}},
+			{Key: "in", Value: bson.D{{Key: "$cond", Value: bson.A{
+				valid,
+				bson.D{{Key: "$concat", Value: bson.A{"+", "$$e164"}}},
+				bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$eq", Value: bson.A{"$$original", ""}}},
+					nil,
+					"$$original",
+				}}},
+			}}}},
+		}}}},
+	}}}
+}
+
+// websiteExpr é a versão em pipeline de NormalizeWebsite; sem host vira null
+func websiteExpr(url string) bson.D {
+	capture := func(i int) bson.D {
+		return bson.D{{Key: "$arrayElemAt", Value: bson.A{"$$match.captures", i}}}
+	}
+
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "match", Value: bson.D{{Key: "$regexFind", Value: bson.D{
+			{Key: "input", Value: asString(url)},
+			{Key: "regex", Value: websitePattern},
+		}}}}}},
+		{Key: "in", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$$match", nil}}},
+			nil,
+			bson.D{{Key: "$concat", Value: bson.A{
+				bson.D{{Key: "$toLower", Value: bson.D{{Key: "$ifNull", Value: bson.A{capture(0), "http"}}}}},
+				"://",
+				bson.D{{Key: "$toLower", Value: capture(1)}},
+				bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$eq", Value: bson.A{capture(2), ""}}},
+					"/",
+					capture(2),
+				}}},
+			}}},
+		}}}},
+	}}}
+}
+
+// zipPartExpr é a versão em pipeline de SplitZIP: part 0 é o ZIP5 e part 1 o
+// ZIP+4; fora dos Estados Unidos ou sem a parte, o valor é null
+func zipPartExpr(country, postalCode string, part int) bson.D {
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "match", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{country, "US"}}},
+			bson.D{{Key: "$regexFind", Value: bson.D{
+				{Key: "input", Value: asString(postalCode)},
+				{Key: "regex", Value: zipPattern},
+			}}},
+			nil,
+		}}}}}},
+		{Key: "in", Value: bson.D{{Key: "$ifNull", Value: bson.A{
+			bson.D{{Key: "$arrayElemAt", Value: bson.A{"$$match.captures", part}}},
+			nil,
+		}}}},
+	}}}
+}
+
+func stringsToA(values []string) bson.A {
+	a := make(bson.A, len(values))
+	for i, v := range values {
+		a[i] = v
+	}
+	return a
+}
@@ -0,0 +1,190 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"United States", "US"},
+		{" united states of america ", "US"},
+		{"USA", "US"},
+		{"England", "GB"},
+		{"Scotland", "GB"},
+		{"Isle of Man", "IM"},
+		{"Ireland", "IE"},
+		{"South Korea", "KR"},
+		{"Austria", "AT"},
+		{"Türkiye", "TR"},
+		{"Côte d'Ivoire", "CI"},
+		{"Bolivia (Plurinational State of)", "BO"},
+		{"Vietnam", "VN"},
+		{"Kenya", "KE"},
+		{"us", "US"},
+		{"ie", "IE"},
+		{"Atlantis", "Atlantis"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizeCountry(tt.input), tt.input)
+	}
+}
+
+func TestISOCountriesTable(t *testing.T) {
+	// A tabela cobre todos os códigos alfa-2 atribuídos, sem nomes repetidos
+	assert.Len(t, isoCountries, 249)
+	codes := map[string]bool{}
+	names := map[string]string{}
+	for _, c := range isoCountries {
+		assert.Regexp(t, `^[A-Z]{2}$`, c.code)
+		assert.False(t, codes[c.code], "duplicate code %s", c.code)
+		codes[c.code] = true
+		assert.Regexp(t, `^[1-9][0-9]{0,2}$`, c.callingCode, c.code)
+		assert.NotEmpty(t, c.names, c.code)
+		for _, name := range c.names {
+			assert.Equal(t, asciiLower(trimSpace(name)), name, c.code)
+			assert.Empty(t, names[name], "%q is used by %s and %s", name, names[name], c.code)
+			names[name] = c.code
+		}
+	}
+}
+
+func TestNormalizeSubdivision(t *testing.T) {
+	tests := []struct {
+		country, state, expected string
+	}{
+		{"US", "Oregon", "OR"},
+		{"US", " new york ", "NY"},
+		{"US", "District of Columbia", "DC"},
+		{"US", "or", "OR"},
+		{"US", "TX", "TX"},
+		{"CA", "British Columbia", "BC"},
+		{"CA", "Québec", "QC"},
+		{"AU", "Western Australia", "WA"},
+		{"US", "Western Australia", ""},
+		{"IE", "Dublin", ""},
+		{"US", "", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizeSubdivision(tt.country, tt.state), tt.country+"/"+tt.state)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		country, phone, expected string
+	}{
+		{"US", "5035551234", "+15035551234"},
+		{"US", "(503) 555-1234", "+15035551234"},
+		{"US", "1-503-555-1234", "+15035551234"},
+		{"CA", "604.555.1234", "+16045551234"},
+		{"US", "555-1234", "555-1234"},
+		{"IE", "01 234 5678", "+35312345678"},
+		{"IE", "+353 1 234 5678", "+35312345678"},
+		{"GB", "020 7946 0958", "+442079460958"},
+		{"GB", "0044 20 7946 0958", "+442079460958"},
+		{"GB", "44 20 7946 0958", "+442079460958"},
+		{"SG", "6123 4567", "+6561234567"},
+		{"KE", "020 1234567", "+254201234567"},
+		{"VN", "028 3823 4567", "+842838234567"},
+		{"XX", "+33 1 23 45 67 89", "+33123456789"},
+		{"XX", " 01 23 45 67 89 ", "01 23 45 67 89"},
+		{"US", "ext. 12", "ext. 12"},
+		{"US", "", ""},
+		{"US", "   ", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizePhone(tt.country, tt.phone), tt.country+"/"+tt.phone)
+	}
+}
+
+func TestNormalizeWebsite(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"http://www.FooBrewing.com", "http://www.foobrewing.com/"},
+		{"HTTPS://Foo.com/Beers?x=1", "https://foo.com/Beers?x=1"},
+		{"foo.com", "http://foo.com/"},
+		{" www.foo.com/taproom ", "http://www.foo.com/taproom"},
+		{"https://foo.com/", "https://foo.com/"},
+		{"", ""},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizeWebsite(tt.input), tt.input)
+	}
+}
+
+func TestSplitZIP(t *testing.T) {
+	tests := []struct {
+		country, postal, zip5, zip4 string
+	}{
+		{"US", "97201", "97201", ""},
+		{"US", "97201-1234", "97201", "1234"},
+		{"US", "972011234", "97201", "1234"},
+		{"US", " 97201 1234 ", "97201", "1234"},
+		{"US", "9720", "", ""},
+		{"US", "97201-12", "", ""},
+		{"CA", "V6B 1A1", "", ""},
+		{"GB", "97201", "", ""},
+	}
+	for _, tt := range tests {
+		zip5, zip4 := SplitZIP(tt.country, tt.postal)
+		assert.Equal(t, tt.zip5, zip5, tt.postal)
+		assert.Equal(t, tt.zip4, zip4, tt.postal)
+	}
+}
+
+// switchThens coleta os valores then de todos os $switch da expressão
+func switchThens(t *testing.T, expr bson.D) []interface{} {
+	var thens []interface{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch n := v.(type) {
+		case bson.D:
+			for _, e := range n {
+				if e.Key == "branches" {
+					for _, b := range e.Value.(bson.A) {
+						thens = append(thens, b.(bson.D).Map()["then"])
+					}
+					continue
+				}
+				walk(e.Value)
+			}
+		case bson.A:
+			for _, item := range n {
+				walk(item)
+			}
+		}
+	}
+	walk(expr)
+	return thens
+}
+
+func TestNormalizationExpressionsCoverTables(t *testing.T) {
+	// Cada código das tabelas tem um ramo no pipeline gerado
+	countries := switchThens(t, countryExpr("$country"))
+	for code := range countryAliases {
+		assert.Contains(t, countries, code)
+	}
+
+	subdivisions := 0
+	for _, codes := range subdivisionCodes {
+		seen := map[string]bool{}
+		for _, code := range codes {
+			seen[code] = true
+		}
+		subdivisions += len(seen)
+	}
+	assert.Len(t, switchThens(t, subdivisionExpr("$country", "$state")), subdivisions)
+
+	phones := switchThens(t, phoneExpr("$country", "$phone"))
+	for _, code := range callingCodes {
+		assert.Contains(t, phones, code)
+	}
+}
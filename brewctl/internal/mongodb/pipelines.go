@@ -0,0 +1,278 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Os builders abaixo são funções puras: os mesmos argumentos geram sempre o
+// mesmo pipeline. Eles são a fonte única das agregações, usada pelo serviço,
+// pelos testes golden em testdata/pipelines e pelo scripts/mongodb-aggregations.js.
+
+// SilverPipeline limpa os documentos do bronze selecionados por filter e os
+// grava na silver com uma cervejaria por id: o sync em append repete o mesmo
+// id no bronze, e vence a versão com o maior updated_at (as sem updated_at
+// válido contam como mais novas). Um documento já
+// existente só é substituído (e tem last_updated renovado) quando a nova versão
+// não é mais antiga e o conteúdo limpo muda; ingestion_date guarda a primeira carga.
+func SilverPipeline(filter bson.D, quality QualityModel) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		silverMatchStage(filter),
+		silverProjectStage(),
+	}
+	pipeline = append(pipeline, normalizationStages()...)
+	pipeline = append(pipeline, quality.Stages()...)
+	pipeline = append(pipeline, latestPerIDStages()...)
+	return append(pipeline, silverMergeStage())
+}
+
+// silverMatchStage aplica o filtro do lote; o id é a chave do $merge e não pode faltar
+func silverMatchStage(filter bson.D) bson.D {
+	match := append(bson.D{}, filter...)
+	match = append(match, bson.E{Key: "id", Value: bson.D{{Key: "$type", Value: "string"}}})
+	return bson.D{{Key: "$match", Value: match}}
+}
+
+// silverProjectStage seleciona os campos do bronze e converte coordenadas e datas
+func silverProjectStage() bson.D {
+	return bson.D{{Key: "$project", Value: bson.D{
+		{Key: "_id", Value: 1},
+		{Key: "id", Value: 1},
+		{Key: "name", Value: 1},
+		{Key: "brewery_type", Value: 1},
+		{Key: "address_1", Value: 1},
+		{Key: "city", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$city"}}}}},
+		{Key: "state_province", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$state_province"}}}}},
+		{Key: "state", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$state"}}}}},
+		{Key: "country", Value: 1},
+		{Key: "postal_code", Value: 1},
+		{Key: "longitude", Value: convertOrNull("$longitude", "double")},
+		{Key: "latitude", Value: convertOrNull("$latitude", "double")},
+		{Key: "phone", Value: 1},
+		{Key: "website_url", Value: 1},
+		{Key: "street", Value: 1},
+		{Key: "updated_at", Value: convertOrNull("$updated_at", "date")},
+		{Key: "ingestion_date", Value: "$$NOW"},
+		{Key: "last_updated", Value: "$$NOW"},
+	}}}
+}
+
+// convertOrNull converte o campo para o tipo; valores inválidos viram null
+func convertOrNull(field, to string) bson.D {
+	return bson.D{{Key: "$convert", Value: bson.D{
+		{Key: "input", Value: field},
+		{Key: "to", Value: to},
+		{Key: "onError", Value: nil},
+		{Key: "onNull", Value: nil},
+	}}}
+}
+
+// undatedField marca, só durante a ordenação, as versões sem updated_at válido
+const undatedField = "undated"
+
+// newestFirstStages ordenam as versões de cada id da mais nova para a mais
+// antiga. Uma versão sem updated_at válido conta como mais nova que qualquer
+// versão datada, porque em geral é uma correção da fonte; as demais seguem o
+// maior updated_at, e o documento mais recente do bronze desempata.
+func newestFirstStages() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: undatedField, Value: bson.D{{Key: "$ne", Value: bson.A{
+			bson.D{{Key: "$type", Value: "$updated_at"}},
+			"date",
+		}}}}}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "id", Value: 1},
+			{Key: undatedField, Value: -1},
+			{Key: "updated_at", Value: -1},
+			{Key: "_id", Value: -1},
+		}}},
+	}
+}
+
+// latestPerIDStages mantêm uma versão por id no lote, a primeira na ordem de
+// newestFirstStages
+func latestPerIDStages() mongo.Pipeline {
+	return append(newestFirstStages(), mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$id"},
+			{Key: "latest", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$replaceWith", Value: "$latest"}},
+		// O _id da silver é próprio e não muda entre versões do bronze
+		{{Key: "$unset", Value: bson.A{"_id", undatedField}}},
+	}...)
+}
+
+// silverMergeStage grava o lote na silver pelo id
+func silverMergeStage() bson.D {
+	return bson.D{{Key: "$merge", Value: bson.D{
+		{Key: "into", Value: CleanCollection},
+		{Key: "on", Value: "id"},
+		{Key: "whenMatched", Value: mongo.Pipeline{
+			{{Key: "$replaceWith", Value: bson.D{{Key: "$switch", Value: bson.D{
+				{Key: "branches", Value: bson.A{
+					// A silver já tem uma versão mais nova da cervejaria. Sem
+					// updated_at válido a nova versão sempre substitui, e a da
+					// silver só é mantida contra uma versão datada (a mesma
+					// ordem de newestFirstStages); comparar null direto com
+					// uma data daria a nova versão como mais antiga.
+					bson.D{
+						{Key: "case", Value: bson.D{{Key: "$and", Value: bson.A{
+							bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$$new.updated_at"}}, "date"}}},
+							bson.D{{Key: "$or", Value: bson.A{
+								bson.D{{Key: "$ne", Value: bson.A{bson.D{{Key: "$type", Value: "$updated_at"}}, "date"}}},
+								bson.D{{Key: "$lt", Value: bson.A{"$$new.updated_at", "$updated_at"}}},
+							}}},
+						}}}},
+						{Key: "then", Value: "$$ROOT"},
+					},
+					bson.D{
+						{Key: "case", Value: bson.D{{Key: "$eq", Value: bson.A{
+							withoutMetadata("$$ROOT"),
+							withoutMetadata("$$new"),
+						}}}},
+						{Key: "then", Value: "$$ROOT"},
+					},
+				}},
+				{Key: "default", Value: bson.D{{Key: "$mergeObjects", Value: bson.A{
+					"$$new",
+					bson.D{
+						{Key: "_id", Value: "$_id"},
+						{Key: "ingestion_date", Value: "$ingestion_date"},
+					},
+				}}}},
+			}}}}},
+		}},
+		{Key: "whenNotMatched", Value: "insert"},
+	}}}
+}
+
+// locationPoint monta o ponto GeoJSON usado pelo índice 2dsphere a partir das
+// coordenadas já convertidas; coordenadas inválidas omitem o campo
+func locationPoint() bson.D {
+	return bson.D{{Key: "$cond", Value: bson.D{
+		{Key: "if", Value: validCoordinatesExpr()},
+		{Key: "then", Value: bson.D{
+			{Key: "type", Value: "Point"},
+			{Key: "coordinates", Value: bson.A{"$longitude", "$latitude"}},
+		}},
+		{Key: "else", Value: "$$REMOVE"},
+	}}}
+}
+
+// withoutMetadata remove _id, ingestion_date e last_updated para comparar
+// apenas o conteúdo
+func withoutMetadata(doc string) bson.D {
+	var stripped interface{} = doc
+	for _, field := range []string{"_id", "ingestion_date", "last_updated"} {
+		stripped = bson.D{{Key: "$unsetField", Value: bson.D{
+			{Key: "field", Value: field},
+			{Key: "input", Value: stripped},
+		}}}
+	}
+	return stripped.(bson.D)
+}
+
+// GoldPipeline agrega a silver com completeness_score de pelo menos minScore
+// por país, estado e tipo, marcando cada documento com o build que o gerou, e
+// grava o resultado em into. built_at é o $$NOW do servidor, o mesmo para
+// todos os documentos do build.
+func GoldPipeline(buildID string, minScore float64, into string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "data_quality.completeness_score", Value: bson.D{{Key: "$gte", Value: minScore}}},
+		}}},
+		goldGroupStage(),
+		goldProjectStage(buildID),
+		{{Key: "$out", Value: into}},
+	}
+}
+
+func goldGroupStage() bson.D {
+	countIf := func(cond interface{}) bson.D {
+		return bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{cond, 1, 0}}}}}
+	}
+
+	return bson.D{{Key: "$group", Value: bson.D{
+		{Key: "_id", Value: bson.D{
+			{Key: "country", Value: "$country"},
+			{Key: "state", Value: "$state"},
+			{Key: "brewery_type", Value: "$brewery_type"},
+		}},
+		{Key: "total_breweries", Value: bson.D{{Key: "$sum", Value: 1}}},
+		{Key: "breweries_with_website", Value: countIf(bson.D{{Key: "$ne", Value: bson.A{"$website_url", nil}}})},
+		{Key: "breweries_with_phone", Value: countIf(bson.D{{Key: "$ne", Value: bson.A{"$phone", nil}}})},
+		{Key: "breweries_with_coordinates", Value: countIf("$data_quality.has_coordinates")},
+	}}}
+}
+
+func goldProjectStage(buildID string) bson.D {
+	return bson.D{{Key: "$project", Value: bson.D{
+		{Key: "_id", Value: 0},
+		{Key: "country", Value: "$_id.country"},
+		{Key: "state", Value: "$_id.state"},
+		{Key: "brewery_type", Value: "$_id.brewery_type"},
+		{Key: "total_breweries", Value: 1},
+		{Key: "breweries_with_website", Value: 1},
+		{Key: "breweries_with_phone", Value: 1},
+		{Key: "breweries_with_coordinates", Value: 1},
+		{Key: "build_id", Value: buildID},
+		{Key: "built_at", Value: "$$NOW"},
+	}}}
+}
+
+// TopStatesPipeline conta as cervejarias da silver por estado, do maior para o menor
+func TopStatesPipeline(limit int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$state"},
+			{Key: "total_breweries", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total_breweries", Value: -1}}}},
+		{{Key: "$limit", Value: int64(limit)}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "state", Value: "$_id"},
+			{Key: "total_breweries", Value: 1},
+		}}},
+	}
+}
+
+// BreweryTypesPipeline conta as cervejarias da silver por tipo e quantos
+// estados cada tipo cobre
+func BreweryTypesPipeline() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$brewery_type"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "states", Value: bson.D{{Key: "$addToSet", Value: "$state"}}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "brewery_type", Value: "$_id"},
+			{Key: "count", Value: 1},
+			{Key: "states_covered", Value: bson.D{{Key: "$size", Value: "$states"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+	}
+}
+
+// GeographicPipeline lista até limit cervejarias da silver com coordenadas válidas
+func GeographicPipeline(limit int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "data_quality.has_coordinates", Value: true},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "name", Value: 1},
+			{Key: "brewery_type", Value: 1},
+			{Key: "city", Value: 1},
+			{Key: "state", Value: 1},
+			{Key: "country", Value: 1},
+			{Key: "longitude", Value: 1},
+			{Key: "latitude", Value: 1},
+		}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+}
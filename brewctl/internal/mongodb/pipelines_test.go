@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compara o Extended JSON do pipeline com testdata/pipelines/<name>.json;
+// com -update o arquivo é regravado
+func assertGolden(t *testing.T, name string, pipeline mongo.Pipeline) {
+	t.Helper()
+
+	got, err := PipelineJSON(pipeline)
+	require.NoError(t, err)
+	got += "\n"
+
+	path := filepath.Join("testdata", "pipelines", name+".json")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test ./internal/mongodb -update to create the golden file")
+	assert.Equal(t, string(want), got, "pipeline changed; review it and run go test ./internal/mongodb -update")
+}
+
+func TestPipelinesGolden(t *testing.T) {
+	// No modo incremental só o $match muda, então apenas ele tem golden próprio
+	previous, _ := primitive.ObjectIDFromHex("6650a0000000000000000001")
+	current, _ := primitive.ObjectIDFromHex("6650b0000000000000000002")
+
+	cases := map[string]mongo.Pipeline{
+		"silver_full":              SilverPipeline(watermarkFilter(nil, nil, DefaultWatermarkOverlap), DefaultQualityModel()),
+		"silver_incremental_match": SilverPipeline(watermarkFilter(&previous, &current, DefaultWatermarkOverlap), DefaultQualityModel())[:1],
+		"gold":                     GoldPipeline("build-1", 0.6, "breweries_aggregated_build_build-1"),
+		"top_states":               TopStatesPipeline(10),
+		"brewery_types":            BreweryTypesPipeline(),
+		"geographic":               GeographicPipeline(100),
+	}
+	for name, pipeline := range cases {
+		t.Run(name, func(t *testing.T) {
+			assertGolden(t, name, pipeline)
+		})
+	}
+}
+
+// stage retorna o corpo do primeiro estágio com o operador informado
+func stage(t *testing.T, pipeline mongo.Pipeline, operator string) interface{} {
+	for _, s := range pipeline {
+		if s[0].Key == operator {
+			return s[0].Value
+		}
+	}
+	require.Failf(t, "missing stage", "pipeline has no %s stage", operator)
+	return nil
+}
+
+func TestSilverPipeline(t *testing.T) {
+	current := primitive.NewObjectID()
+
+	// Sem watermark o $match ainda descarta documentos sem id textual
+	full := SilverPipeline(watermarkFilter(nil, nil, DefaultWatermarkOverlap), DefaultQualityModel())
+	assert.Equal(t, bson.D{{Key: "id", Value: bson.D{{Key: "$type", Value: "string"}}}}, stage(t, full, "$match"))
+
+	pipeline := SilverPipeline(watermarkFilter(nil, &current, DefaultWatermarkOverlap), DefaultQualityModel())
+	match := stage(t, pipeline, "$match").(bson.D).Map()
+	assert.Contains(t, match, "_id")
+	assert.Contains(t, match, "id")
+
+	// Timestamps vêm do servidor e não são fixados no momento da montagem
+	project := stage(t, pipeline, "$project").(bson.D).Map()
+	assert.Equal(t, "$$NOW", project["ingestion_date"])
+	assert.Equal(t, "$$NOW", project["last_updated"])
+	assert.Contains(t, project, "updated_at")
+
+	// Normalização: o país é convertido antes das regras que dependem dele, e
+	// location alimenta o índice 2dsphere da silver
+	var sets []bson.M
+	for _, st := range pipeline {
+		if st[0].Key == "$set" {
+			sets = append(sets, st[0].Value.(bson.D).Map())
+		}
+	}
+	require.Len(t, sets, 5)
+	assert.Contains(t, sets[0], "country")
+	for _, field := range []string{"state_code", "phone", "website_url", "zip5", "zip4", "location"} {
+		assert.Contains(t, sets[1], field)
+	}
+
+	// O data_quality é calculado depois da normalização e das conversões
+	assert.Contains(t, sets[2], "data_quality")
+	assert.Contains(t, sets[3], "data_quality.completeness_score")
+	assert.NotContains(t, project, "data_quality")
+
+	// Dentro do lote fica só a versão mais recente de cada id; as sem
+	// updated_at válido vêm primeiro
+	assert.Contains(t, sets[4], undatedField)
+	assert.Equal(t, bson.D{
+		{Key: "id", Value: 1},
+		{Key: undatedField, Value: -1},
+		{Key: "updated_at", Value: -1},
+		{Key: "_id", Value: -1},
+	}, stage(t, pipeline, "$sort"))
+	assert.Equal(t, "$id", stage(t, pipeline, "$group").(bson.D).Map()["_id"])
+	assert.Equal(t, bson.A{"_id", undatedField}, stage(t, pipeline, "$unset"))
+
+	merge := stage(t, pipeline, "$merge").(bson.D).Map()
+	assert.Equal(t, "id", merge["on"])
+	assert.Equal(t, "$merge", pipeline[len(pipeline)-1][0].Key)
+
+	// Versões mais antigas e documentos sem mudança de conteúdo mantêm o
+	// documento atual, inclusive o last_updated. Uma versão sem updated_at
+	// válido nunca é mais antiga, e a atual sem updated_at só perde para outra
+	// também sem data.
+	whenMatched, ok := merge["whenMatched"].(mongo.Pipeline)
+	require.True(t, ok)
+	branches := whenMatched[0][0].Value.(bson.D)[0].Value.(bson.D).Map()["branches"].(bson.A)
+	require.Len(t, branches, 2)
+	older := branches[0].(bson.D).Map()
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$$new.updated_at"}}, "date"}}},
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "$ne", Value: bson.A{bson.D{{Key: "$type", Value: "$updated_at"}}, "date"}}},
+			bson.D{{Key: "$lt", Value: bson.A{"$$new.updated_at", "$updated_at"}}},
+		}}},
+	}}}, older["case"])
+	assert.Equal(t, "$$ROOT", older["then"])
+	assert.Equal(t, "$$ROOT", branches[1].(bson.D).Map()["then"])
+}
+
+func TestGoldPipeline(t *testing.T) {
+	pipeline := GoldPipeline("build-1", 0.6, "breweries_aggregated_build_build-1")
+
+	// O build é gravado por inteiro em uma coleção temporária, nunca mesclado na gold
+	last := pipeline[len(pipeline)-1][0]
+	assert.Equal(t, "$out", last.Key)
+	assert.Equal(t, "breweries_aggregated_build_build-1", last.Value)
+
+	match := pipeline[0][0].Value.(bson.D).Map()
+	assert.Equal(t, bson.D{{Key: "$gte", Value: 0.6}}, match["data_quality.completeness_score"])
+
+	project := pipeline[len(pipeline)-2][0].Value.(bson.D).Map()
+	assert.Equal(t, "build-1", project["build_id"])
+	// built_at vem do servidor, o mesmo para todo o build
+	assert.Equal(t, "$$NOW", project["built_at"])
+}
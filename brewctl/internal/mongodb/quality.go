@@ -0,0 +1,234 @@
+package mongodb
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// QualityCheck é o tipo de verificação de uma regra de qualidade
+type QualityCheck string
+
+const (
+	// CheckPresent exige um valor não vazio; strings só com espaços contam como vazias
+	CheckPresent QualityCheck = "present"
+	// CheckCoordinates exige longitude e latitude numéricas após a conversão,
+	// dentro da faixa válida e diferentes de (0, 0), usado como valor de preenchimento
+	CheckCoordinates QualityCheck = "coordinates"
+)
+
+// QualityRule é uma regra do modelo de qualidade. O resultado vira a flag
+// data_quality.flags.<Name> e, se aprovada, soma Weight ao score.
+type QualityRule struct {
+	Name   string
+	Check  QualityCheck
+	Field  string
+	Weight float64
+}
+
+// QualityModel define o data_quality da silver: as regras, com pesos, e o
+// score mínimo para um documento entrar na gold
+type QualityModel struct {
+	Rules    []QualityRule
+	MinScore float64
+}
+
+// legacyFlags são as regras também expostas como has_<regra>, usadas pela
+// gold, pelas consultas e pelo validator da silver
+var legacyFlags = []string{"coordinates", "website", "phone"}
+
+// DefaultQualityModel é o modelo usado pela silver
+func DefaultQualityModel() QualityModel {
+	return QualityModel{
+		Rules: []QualityRule{
+			{Name: "name", Check: CheckPresent, Field: "name", Weight: 3},
+			{Name: "brewery_type", Check: CheckPresent, Field: "brewery_type", Weight: 2},
+			{Name: "city", Check: CheckPresent, Field: "city", Weight: 2},
+			{Name: "state", Check: CheckPresent, Field: "state", Weight: 2},
+			{Name: "country", Check: CheckPresent, Field: "country", Weight: 2},
+			{Name: "address", Check: CheckPresent, Field: "address_1", Weight: 1},
+			{Name: "postal_code", Check: CheckPresent, Field: "postal_code", Weight: 1},
+			{Name: "phone", Check: CheckPresent, Field: "phone", Weight: 1},
+			{Name: "website", Check: CheckPresent, Field: "website_url", Weight: 1},
+			{Name: "coordinates", Check: CheckCoordinates, Weight: 2},
+		},
+		MinScore: 0.6,
+	}
+}
+
+// Validate confere nomes, pesos e a presença das regras expostas como has_*
+func (m QualityModel) Validate() error {
+	names := map[string]bool{}
+	total := 0.0
+	for _, rule := range m.Rules {
+		if rule.Name == "" || strings.ContainsAny(rule.Name, ".$") {
+			return fmt.Errorf("quality rule %q: invalid name", rule.Name)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("quality rule %q is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+
+		switch rule.Check {
+		case CheckPresent:
+			if rule.Field == "" {
+				return fmt.Errorf("quality rule %q: present check requires a field", rule.Name)
+			}
+		case CheckCoordinates:
+		default:
+			return fmt.Errorf("quality rule %q: unknown check %q", rule.Name, rule.Check)
+		}
+		if rule.Weight < 0 {
+			return fmt.Errorf("quality rule %q: weight must not be negative", rule.Name)
+		}
+		total += rule.Weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("quality model must have a positive total weight")
+	}
+	for _, name := range legacyFlags {
+		if !names[name] {
+			return fmt.Errorf("quality model must define the %q rule", name)
+		}
+	}
+	if m.MinScore < 0 || m.MinScore > 1 {
+		return fmt.Errorf("quality model min score must be between 0 and 1")
+	}
+	return nil
+}
+
+func (m QualityModel) totalWeight() float64 {
+	total := 0.0
+	for _, rule := range m.Rules {
+		total += rule.Weight
+	}
+	return total
+}
+
+// Stages compila o modelo em dois estágios: as flags de cada regra e, a partir
+// delas, o completeness_score ponderado e os campos has_*
+func (m QualityModel) Stages() mongo.Pipeline {
+	flags := bson.D{}
+	for _, rule := range m.Rules {
+		flags = append(flags, bson.E{Key: rule.Name, Value: rule.expr()})
+	}
+
+	var weighted bson.A
+	for _, rule := range m.Rules {
+		weighted = append(weighted, bson.D{{Key: "$cond", Value: bson.A{"$data_quality.flags." + rule.Name, rule.Weight, 0}}})
+	}
+	summary := bson.D{
+		{Key: "data_quality.completeness_score", Value: bson.D{{Key: "$divide", Value: bson.A{
+			bson.D{{Key: "$add", Value: weighted}},
+			m.totalWeight(),
+		}}}},
+	}
+	for _, name := range legacyFlags {
+		summary = append(summary, bson.E{Key: "data_quality.has_" + name, Value: "$data_quality.flags." + name})
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "data_quality", Value: bson.D{{Key: "flags", Value: flags}}}}}},
+		{{Key: "$set", Value: summary}},
+	}
+}
+
+func (r QualityRule) expr() bson.D {
+	if r.Check == CheckCoordinates {
+		return validCoordinatesExpr()
+	}
+	return presentExpr("$" + r.Field)
+}
+
+// presentExpr é verdadeiro para strings com algum caractere além de espaços e
+// para qualquer outro valor que não seja null ou ausente
+func presentExpr(field string) bson.D {
+	return bson.D{{Key: "$cond", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: field}}, "string"}}},
+		bson.D{{Key: "$gt", Value: bson.A{
+			bson.D{{Key: "$strLenCP", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: field}}}}}},
+			0,
+		}}},
+		bson.D{{Key: "$not", Value: bson.A{bson.D{{Key: "$in", Value: bson.A{
+			bson.D{{Key: "$type", Value: field}},
+			bson.A{"missing", "null"},
+		}}}}}},
+	}}}
+}
+
+// validCoordinatesExpr avalia longitude e latitude já convertidas para double
+func validCoordinatesExpr() bson.D {
+	return bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$isNumber", Value: "$longitude"}},
+		bson.D{{Key: "$isNumber", Value: "$latitude"}},
+		bson.D{{Key: "$gte", Value: bson.A{"$longitude", -180}}},
+		bson.D{{Key: "$lte", Value: bson.A{"$longitude", 180}}},
+		bson.D{{Key: "$gte", Value: bson.A{"$latitude", -90}}},
+		bson.D{{Key: "$lte", Value: bson.A{"$latitude", 90}}},
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "$ne", Value: bson.A{"$longitude", 0}}},
+			bson.D{{Key: "$ne", Value: bson.A{"$latitude", 0}}},
+		}}},
+	}}}
+}
+
+// QualityReport é o data_quality de um documento calculado em Go
+type QualityReport struct {
+	Flags             map[string]bool
+	CompletenessScore float64
+}
+
+// Evaluate aplica o modelo a um documento já limpo, com as mesmas regras dos
+// estágios gerados por Stages
+func (m QualityModel) Evaluate(doc bson.M) QualityReport {
+	report := QualityReport{Flags: map[string]bool{}}
+	passed := 0.0
+	for _, rule := range m.Rules {
+		var ok bool
+		if rule.Check == CheckCoordinates {
+			ok = validCoordinates(doc["longitude"], doc["latitude"])
+		} else {
+			ok = present(doc[rule.Field])
+		}
+		report.Flags[rule.Name] = ok
+		if ok {
+			passed += rule.Weight
+		}
+	}
+	report.CompletenessScore = passed / m.totalWeight()
+	return report
+}
+
+func present(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return trimSpace(v) != ""
+	default:
+		return true
+	}
+}
+
+func validCoordinates(longitude, latitude interface{}) bool {
+	lon, ok1 := longitude.(float64)
+	lat, ok2 := latitude.(float64)
+	if !ok1 || !ok2 || math.IsNaN(lon) || math.IsNaN(lat) {
+		return false
+	}
+	if lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+		return false
+	}
+	return lon != 0 || lat != 0
+}
+
+// qualityModel retorna o modelo configurado no serviço ou o padrão
+func (s *AggregationService) qualityModel() QualityModel {
+	if len(s.Quality.Rules) == 0 {
+		return DefaultQualityModel()
+	}
+	return s.Quality
+}
@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDefaultQualityModelIsValid(t *testing.T) {
+	require.NoError(t, DefaultQualityModel().Validate())
+}
+
+func TestQualityModelValidate(t *testing.T) {
+	base := DefaultQualityModel()
+	tests := map[string]func(m *QualityModel){
+		"duplicated rule":   func(m *QualityModel) { m.Rules = append(m.Rules, m.Rules[0]) },
+		"negative weight":   func(m *QualityModel) { m.Rules[0].Weight = -1 },
+		"missing field":     func(m *QualityModel) { m.Rules[0].Field = "" },
+		"unknown check":     func(m *QualityModel) { m.Rules[0].Check = "regex" },
+		"dotted name":       func(m *QualityModel) { m.Rules[0].Name = "a.b" },
+		"min score above 1": func(m *QualityModel) { m.MinScore = 1.5 },
+		"missing has_ rule": func(m *QualityModel) { m.Rules = m.Rules[:len(m.Rules)-1] },
+		"zero total weights": func(m *QualityModel) {
+			m.Rules = []QualityRule{{Name: "coordinates", Check: CheckCoordinates}, {Name: "phone", Check: CheckPresent, Field: "phone"}, {Name: "website", Check: CheckPresent, Field: "website_url"}}
+		},
+	}
+	for name, mutate := range tests {
+		m := base
+		m.Rules = append([]QualityRule(nil), base.Rules...)
+		mutate(&m)
+		assert.Error(t, m.Validate(), name)
+	}
+}
+
+func TestQualityModelEvaluate(t *testing.T) {
+	model := QualityModel{
+		Rules: []QualityRule{
+			{Name: "name", Check: CheckPresent, Field: "name", Weight: 3},
+			{Name: "phone", Check: CheckPresent, Field: "phone", Weight: 1},
+			{Name: "website", Check: CheckPresent, Field: "website_url", Weight: 1},
+			{Name: "coordinates", Check: CheckCoordinates, Weight: 5},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		doc   bson.M
+		flags map[string]bool
+		score float64
+	}{
+		{
+			name:  "complete",
+			doc:   bson.M{"name": "Foo", "phone": "+15035551234", "website_url": "http://foo.com/", "longitude": -122.6, "latitude": 45.5},
+			flags: map[string]bool{"name": true, "phone": true, "website": true, "coordinates": true},
+			score: 1,
+		},
+		{
+			name:  "empty and whitespace strings are missing",
+			doc:   bson.M{"name": "   ", "phone": "", "website_url": nil, "longitude": -122.6, "latitude": 45.5},
+			flags: map[string]bool{"name": false, "phone": false, "website": false, "coordinates": true},
+			score: 0.5,
+		},
+		{
+			name:  "coordinates that failed conversion",
+			doc:   bson.M{"name": "Foo", "longitude": nil, "latitude": 45.5},
+			flags: map[string]bool{"name": true, "phone": false, "website": false, "coordinates": false},
+			score: 0.3,
+		},
+		{
+			name:  "unconverted string coordinates",
+			doc:   bson.M{"name": "Foo", "longitude": "-122.6", "latitude": "45.5"},
+			flags: map[string]bool{"name": true, "phone": false, "website": false, "coordinates": false},
+			score: 0.3,
+		},
+		{
+			name:  "out of range",
+			doc:   bson.M{"name": "Foo", "longitude": -122.6, "latitude": 95.0},
+			flags: map[string]bool{"name": true, "phone": false, "website": false, "coordinates": false},
+			score: 0.3,
+		},
+		{
+			name:  "null island placeholder",
+			doc:   bson.M{"name": "Foo", "longitude": 0.0, "latitude": 0.0},
+			flags: map[string]bool{"name": true, "phone": false, "website": false, "coordinates": false},
+			score: 0.3,
+		},
+	}
+	for _, tt := range tests {
+		report := model.Evaluate(tt.doc)
+		assert.Equal(t, tt.flags, report.Flags, tt.name)
+		assert.InDelta(t, tt.score, report.CompletenessScore, 1e-9, tt.name)
+	}
+}
+
+func TestQualityModelStages(t *testing.T) {
+	model := DefaultQualityModel()
+	stages := model.Stages()
+	require.Len(t, stages, 2)
+
+	// Uma flag por regra
+	flags := stages[0][0].Value.(bson.D).Map()["data_quality"].(bson.D).Map()["flags"].(bson.D)
+	require.Len(t, flags, len(model.Rules))
+	for i, rule := range model.Rules {
+		assert.Equal(t, rule.Name, flags[i].Key)
+	}
+
+	// Score ponderado pelo total dos pesos e campos has_* derivados das flags
+	summary := stages[1][0].Value.(bson.D).Map()
+	divide := summary["data_quality.completeness_score"].(bson.D).Map()["$divide"].(bson.A)
+	assert.Equal(t, 17.0, divide[1])
+	assert.Len(t, divide[0].(bson.D).Map()["$add"].(bson.A), len(model.Rules))
+	assert.Equal(t, "$data_quality.flags.coordinates", summary["data_quality.has_coordinates"])
+	assert.Equal(t, "$data_quality.flags.website", summary["data_quality.has_website"])
+	assert.Equal(t, "$data_quality.flags.phone", summary["data_quality.has_phone"])
+}
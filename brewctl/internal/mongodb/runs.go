@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PipelineRunsCollection guarda o histórico de execuções de cada camada
+const PipelineRunsCollection = "pipeline_runs"
+
+const (
+	LayerSilver = "silver"
+	LayerGold   = "gold"
+
+	RunModeFull        = "full"
+	RunModeIncremental = "incremental"
+
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// PipelineRun é uma execução de uma camada. Na silver, Watermark é o maior
+// _id do bronze processado; a próxima execução incremental parte dele, menos
+// a janela de overlap, e Processed inclui os documentos relidos nela. Na
+// gold, BuildID identifica o snapshot e Processed é o total de linhas.
+type PipelineRun struct {
+	ID                primitive.ObjectID  `bson:"_id,omitempty"`
+	Layer             string              `bson:"layer"`
+	Mode              string              `bson:"mode"`
+	Status            string              `bson:"status"`
+	StartedAt         time.Time           `bson:"started_at"`
+	FinishedAt        *time.Time          `bson:"finished_at,omitempty"`
+	PreviousWatermark *primitive.ObjectID `bson:"previous_watermark,omitempty"`
+	Watermark         *primitive.ObjectID `bson:"watermark,omitempty"`
+	BuildID           string              `bson:"build_id,omitempty"`
+	Processed         int64               `bson:"processed"`
+	Error             string              `bson:"error,omitempty"`
+}
+
+// LastSuccessfulRun retorna a última execução bem-sucedida da camada, ou nil
+func (s *AggregationService) LastSuccessfulRun(ctx context.Context, layer string) (*PipelineRun, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	filter := bson.D{{Key: "layer", Value: layer}, {Key: "status", Value: RunStatusSucceeded}}
+
+	var run PipelineRun
+	err := s.DB.Collection(PipelineRunsCollection).FindOne(ctx, filter, opts).Decode(&run)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s runs: %v", layer, err)
+	}
+	return &run, nil
+}
+
+// startRun registra o início de uma execução
+func (s *AggregationService) startRun(ctx context.Context, run *PipelineRun) error {
+	run.Status = RunStatusRunning
+	run.StartedAt = time.Now().UTC()
+
+	result, err := s.DB.Collection(PipelineRunsCollection).InsertOne(ctx, run)
+	if err != nil {
+		return fmt.Errorf("failed to record %s run: %v", run.Layer, err)
+	}
+	run.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// finishRun grava o resultado da execução; um erro da camada marca a execução
+// como falha e não avança o watermark
+func (s *AggregationService) finishRun(ctx context.Context, run *PipelineRun, runErr error) error {
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.Status = RunStatusSucceeded
+	if runErr != nil {
+		run.Status = RunStatusFailed
+		run.Error = runErr.Error()
+	}
+
+	_, err := s.DB.Collection(PipelineRunsCollection).ReplaceOne(ctx, bson.D{{Key: "_id", Value: run.ID}}, run)
+	if err != nil {
+		return fmt.Errorf("failed to record %s run: %v", run.Layer, err)
+	}
+	return nil
+}
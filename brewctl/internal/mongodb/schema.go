@@ -0,0 +1,83 @@
+package mongodb
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// nullable aceita o tipo BSON informado ou null
+func nullable(bsonType string) bson.D {
+	return bson.D{{Key: "bsonType", Value: bson.A{bsonType, "null"}}}
+}
+
+// count aceita os inteiros gerados por $sum
+var count = bson.D{{Key: "bsonType", Value: bson.A{"int", "long"}}}
+
+// CleanValidator é o JSON Schema dos documentos gerados pela silver. Campos
+// copiados do bronze são opcionais, mas quando presentes têm o tipo esperado.
+func CleanValidator() bson.D {
+	return bson.D{{Key: "$jsonSchema", Value: bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"id", "data_quality", "ingestion_date", "last_updated"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "id", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "name", Value: nullable("string")},
+			{Key: "brewery_type", Value: nullable("string")},
+			{Key: "city", Value: nullable("string")},
+			{Key: "state", Value: nullable("string")},
+			{Key: "state_province", Value: nullable("string")},
+			{Key: "country", Value: nullable("string")},
+			{Key: "longitude", Value: nullable("double")},
+			{Key: "latitude", Value: nullable("double")},
+			{Key: "location", Value: bson.D{
+				{Key: "bsonType", Value: "object"},
+				{Key: "required", Value: bson.A{"type", "coordinates"}},
+				{Key: "properties", Value: bson.D{
+					{Key: "type", Value: bson.D{{Key: "enum", Value: bson.A{"Point"}}}},
+					{Key: "coordinates", Value: bson.D{
+						{Key: "bsonType", Value: "array"},
+						{Key: "minItems", Value: 2},
+						{Key: "maxItems", Value: 2},
+						{Key: "items", Value: bson.D{{Key: "bsonType", Value: "double"}}},
+					}},
+				}},
+			}},
+			{Key: "data_quality", Value: bson.D{
+				{Key: "bsonType", Value: "object"},
+				{Key: "required", Value: bson.A{"has_coordinates", "has_website", "has_phone", "completeness_score"}},
+				{Key: "properties", Value: bson.D{
+					{Key: "has_coordinates", Value: bson.D{{Key: "bsonType", Value: "bool"}}},
+					{Key: "has_website", Value: bson.D{{Key: "bsonType", Value: "bool"}}},
+					{Key: "has_phone", Value: bson.D{{Key: "bsonType", Value: "bool"}}},
+					{Key: "completeness_score", Value: bson.D{
+						{Key: "bsonType", Value: bson.A{"double", "int"}},
+						{Key: "minimum", Value: 0},
+						{Key: "maximum", Value: 1},
+					}},
+				}},
+			}},
+			{Key: "ingestion_date", Value: bson.D{{Key: "bsonType", Value: "date"}}},
+			{Key: "last_updated", Value: bson.D{{Key: "bsonType", Value: "date"}}},
+		}},
+	}}}
+}
+
+// GoldValidator é o JSON Schema dos documentos de um build da gold
+func GoldValidator() bson.D {
+	return bson.D{{Key: "$jsonSchema", Value: bson.D{
+		{Key: "bsonType", Value: "object"},
+		// country, state e brewery_type ficam ausentes quando faltam na silver
+		{Key: "required", Value: bson.A{
+			"total_breweries", "breweries_with_website", "breweries_with_phone",
+			"breweries_with_coordinates", "build_id", "built_at",
+		}},
+		{Key: "properties", Value: bson.D{
+			{Key: "country", Value: nullable("string")},
+			{Key: "state", Value: nullable("string")},
+			{Key: "brewery_type", Value: nullable("string")},
+			{Key: "total_breweries", Value: count},
+			{Key: "breweries_with_website", Value: count},
+			{Key: "breweries_with_phone", Value: count},
+			{Key: "breweries_with_coordinates", Value: count},
+			{Key: "build_id", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "built_at", Value: bson.D{{Key: "bsonType", Value: "date"}}},
+		}},
+	}}}
+}
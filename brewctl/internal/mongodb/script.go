@@ -0,0 +1,131 @@
+package mongodb
+
+//go:generate go run ./scriptgen ../../scripts/mongodb-aggregations.js
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Parâmetros usados nos pipelines do script
+const (
+	scriptTopStatesLimit  = 10
+	scriptGeographicLimit = 100
+)
+
+// jsVarPrefix marca, nos builders, strings que o script troca por uma variável JS
+const jsVarPrefix = "@js:"
+
+var jsVarPattern = regexp.MustCompile(`"` + jsVarPrefix + `(\w+)"`)
+
+func jsVar(name string) string {
+	return jsVarPrefix + name
+}
+
+var scriptTemplate = template.Must(template.New("script").Parse(`// Code generated by "go generate ./internal/mongodb"; DO NOT EDIT.
+//
+// Agregações do brewctl para o mongosh, geradas a partir dos builders de
+// internal/mongodb/pipelines.go. Rode "brewctl db migrate" antes: o $merge
+// da silver depende do índice único em {{.Clean}}.id.
+
+// 1. Silver Layer - limpeza, normalização e qualidade ({{.Raw}} -> {{.Clean}})
+db.getCollection("{{.Raw}}").aggregate({{.Silver}});
+
+// 2. Gold Layer - snapshot em uma coleção temporária que substitui {{.Gold}};
+// builds anteriores que falharam no meio são descartados antes
+db.getCollectionNames()
+    .filter((name) => name.startsWith("{{.Gold}}_build_"))
+    .forEach((name) => db.getCollection(name).drop());
+const buildId = new ObjectId().toHexString();
+const buildCollection = "{{.Gold}}_build_" + buildId;
+const goldInfo = db.getCollectionInfos({ name: "{{.Gold}}" })[0];
+db.createCollection(buildCollection, goldInfo ? goldInfo.options : {});
+if (goldInfo) {
+    const indexes = db.getCollection("{{.Gold}}").getIndexes().filter((index) => index.name !== "_id_");
+    indexes.forEach((index) => delete index.ns);
+    if (indexes.length > 0) {
+        db.runCommand({ createIndexes: buildCollection, indexes: indexes });
+    }
+}
+db.getCollection("{{.Clean}}").aggregate({{.GoldBuild}});
+db.adminCommand({
+    renameCollection: db.getName() + "." + buildCollection,
+    to: db.getName() + ".{{.Gold}}",
+    dropTarget: true
+});
+
+// 3. Top {{.TopStatesLimit}} estados
+db.getCollection("{{.Clean}}").aggregate({{.TopStates}});
+
+// 4. Distribuição por tipo de cervejaria
+db.getCollection("{{.Clean}}").aggregate({{.BreweryTypes}});
+
+// 5. Distribuição geográfica
+db.getCollection("{{.Clean}}").aggregate({{.Geographic}});
+`))
+
+// AggregationsScript gera o scripts/mongodb-aggregations.js: a silver em modo
+// full com o modelo de qualidade padrão, o build da gold e as consultas
+func AggregationsScript() (string, error) {
+	quality := DefaultQualityModel()
+	pipelines := map[string]mongo.Pipeline{
+		"Silver":       SilverPipeline(bson.D{}, quality),
+		"GoldBuild":    GoldPipeline(jsVar("buildId"), quality.MinScore, jsVar("buildCollection")),
+		"TopStates":    TopStatesPipeline(scriptTopStatesLimit),
+		"BreweryTypes": BreweryTypesPipeline(),
+		"Geographic":   GeographicPipeline(scriptGeographicLimit),
+	}
+
+	data := map[string]interface{}{
+		"Raw":            RawCollection,
+		"Clean":          CleanCollection,
+		"Gold":           GoldCollection,
+		"TopStatesLimit": scriptTopStatesLimit,
+	}
+	for name, pipeline := range pipelines {
+		js, err := PipelineJSON(pipeline)
+		if err != nil {
+			return "", fmt.Errorf("failed to render %s pipeline: %v", name, err)
+		}
+		data[name] = jsVarPattern.ReplaceAllString(js, "$1")
+	}
+
+	var out bytes.Buffer
+	if err := scriptTemplate.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render aggregations script: %v", err)
+	}
+	return out.String(), nil
+}
+
+// PipelineJSON formata o pipeline como Extended JSON relaxed indentado, o
+// formato dos testes golden e do script
+func PipelineJSON(pipeline mongo.Pipeline) (string, error) {
+	return ExtJSON(pipeline)
+}
+
+// ExtJSON formata documentos, arrays ou valores como Extended JSON relaxed indentado
+func ExtJSON(value interface{}) (string, error) {
+	// O Extended JSON só é gerado para documentos, então o valor vai em um envelope
+	raw, err := bson.MarshalExtJSON(bson.D{{Key: "value", Value: value}}, false, false)
+	if err != nil {
+		return "", err
+	}
+	var envelope struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, envelope.Value, "", "    "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,24 @@
+package mongodb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregationsScriptIsUpToDate(t *testing.T) {
+	script, err := AggregationsScript()
+	require.NoError(t, err)
+
+	checkedIn, err := os.ReadFile(filepath.Join("..", "..", "scripts", "mongodb-aggregations.js"))
+	require.NoError(t, err)
+	assert.Equal(t, string(checkedIn), script, "scripts/mongodb-aggregations.js is stale; run go generate ./internal/mongodb")
+
+	// As variáveis do build da gold são substituídas por expressões JS
+	assert.NotContains(t, script, jsVarPrefix)
+	assert.Contains(t, script, `"build_id": buildId`)
+	assert.Contains(t, script, `"$out": buildCollection`)
+}
@@ -0,0 +1,24 @@
+// scriptgen grava o scripts/mongodb-aggregations.js gerado pelos builders de
+// pipeline; é executado por go generate ./internal/mongodb
+package main
+
+import (
+	"log"
+	"os"
+
+	"brewctl/internal/mongodb"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: scriptgen <output.js>")
+	}
+
+	script, err := mongodb.AggregationsScript()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if err := os.WriteFile(os.Args[1], []byte(script), 0644); err != nil {
+		log.Fatalf("❌ Failed to write %s: %v", os.Args[1], err)
+	}
+}
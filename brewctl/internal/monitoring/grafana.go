@@ -1,45 +1,45 @@
 package monitoring
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"time"
-)
 
-func DeployGrafana() error {
-	fmt.Println("📈 Deploying Grafana...")
+	"brewctl/internal/config"
+	"brewctl/internal/kube/helm"
+)
 
-	// Add Grafana Helm repo
-	cmd := exec.Command("helm", "repo", "add", "grafana", "https://grafana.github.io/helm-charts")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add grafana repo: %v", err)
-	}
+// GrafanaChart é o chart do Grafana com a versão fixada pelo brewctl
+var GrafanaChart = helm.Chart{
+	Repo:    "grafana",
+	RepoURL: "https://grafana.github.io/helm-charts",
+	Name:    "grafana",
+	Version: "7.0.11",
+}
 
-	// Update Helm repos
-	cmd = exec.Command("helm", "repo", "update")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to update helm repos: %v", err)
+// GrafanaRelease descreve o release Helm do Grafana; os Values fixam o
+// NodePort mapeado pelo Kind e prevalecem sobre o values file
+func GrafanaRelease(cfg config.MonitoringConfig) helm.Release {
+	return helm.Release{
+		Name:        "grafana",
+		Namespace:   cfg.Namespace,
+		Chart:       GrafanaChart,
+		ValuesFiles: helm.OptionalValuesFiles(cfg.GrafanaValuesFile, config.DefaultGrafanaValuesFile),
+		Values: map[string]interface{}{
+			"service.type":     "NodePort",
+			"service.nodePort": 32000,
+		},
+		Timeout: 5 * time.Minute,
 	}
+}
 
-	// Deploy Grafana
-	cmd = exec.Command("helm", "install", "grafana", "grafana/grafana",
-		"--namespace", "default",
-		"--set", "service.type=NodePort",
-		"--set", "service.nodePort=32000",
-		"--set", "adminPassword=admin",
-		"--set", "persistence.enabled=true",
-		"--set", "persistence.size=10Gi",
-		"--wait",
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+func DeployGrafana(m *helm.Manager, cfg config.MonitoringConfig) error {
+	fmt.Println("📈 Deploying Grafana...")
 
-	if err := cmd.Run(); err != nil {
+	if err := m.UpgradeInstall(context.Background(), GrafanaRelease(cfg)); err != nil {
 		return fmt.Errorf("failed to deploy Grafana: %v", err)
 	}
 
-	time.Sleep(30 * time.Second)
 	fmt.Println("✅ Grafana deployed successfully")
 	return nil
 }
@@ -1,19 +1,75 @@
 package monitoring
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"brewctl/internal/config"
+	"brewctl/internal/kube"
+	"brewctl/internal/kube/helm"
+)
 
 // ✅ ADICIONAR: Função Deploy que integra Prometheus + Grafana
-func Deploy() error {
+func Deploy(cfg config.MonitoringConfig) error {
 	fmt.Println("📊 Deploying monitoring stack...")
 
-	if err := DeployPrometheus(); err != nil {
+	client, err := kube.NewClient("")
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureNamespace(context.Background(), cfg.Namespace); err != nil {
+		return err
+	}
+
+	m := helm.NewManager()
+
+	if err := DeployPrometheus(m, cfg); err != nil {
 		return fmt.Errorf("failed to deploy Prometheus: %v", err)
 	}
 
-	if err := DeployGrafana(); err != nil {
+	if err := DeployGrafana(m, cfg); err != nil {
 		return fmt.Errorf("failed to deploy Grafana: %v", err)
 	}
 
 	fmt.Println("✅ Monitoring stack deployed successfully")
 	return nil
 }
+
+// Teardown desinstala Grafana e Prometheus. Com keepData os PVCs dos releases
+// são anotados para que o Helm os preserve; sem ele, os que o uninstall não
+// remove (os do StatefulSet do Alertmanager) são apagados em seguida
+func Teardown(cfg config.MonitoringConfig, keepData bool) error {
+	fmt.Println("🗑️ Removing monitoring stack...")
+
+	client, err := kube.NewClient("")
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	m := helm.NewManager()
+	for _, r := range []helm.Release{GrafanaRelease(cfg), PrometheusRelease(cfg)} {
+		selector := "app.kubernetes.io/instance=" + r.Name
+
+		if keepData {
+			kept, err := client.KeepPVCs(ctx, r.Namespace, selector)
+			if err != nil {
+				return fmt.Errorf("failed to keep %s volumes: %v", r.Name, err)
+			}
+			for _, name := range kept {
+				fmt.Printf("ℹ️ Keeping persistentvolumeclaim/%s\n", name)
+			}
+		}
+
+		if err := m.Uninstall(ctx, r.Name, r.Namespace); err != nil {
+			return err
+		}
+
+		if !keepData {
+			if _, err := client.DeletePVCs(ctx, r.Namespace, selector, ""); err != nil {
+				return fmt.Errorf("failed to delete %s volumes: %v", r.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,45 +1,45 @@
 package monitoring
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"time"
-)
 
-func DeployPrometheus() error {
-	fmt.Println("📊 Deploying Prometheus...")
+	"brewctl/internal/config"
+	"brewctl/internal/kube/helm"
+)
 
-	// Add Prometheus Helm repo
-	cmd := exec.Command("helm", "repo", "add", "prometheus-community", "https://prometheus-community.github.io/helm-charts")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add prometheus repo: %v", err)
-	}
+// PrometheusChart é o chart do Prometheus com a versão fixada pelo brewctl
+var PrometheusChart = helm.Chart{
+	Repo:    "prometheus-community",
+	RepoURL: "https://prometheus-community.github.io/helm-charts",
+	Name:    "prometheus",
+	Version: "25.8.0",
+}
 
-	// Update Helm repos
-	cmd = exec.Command("helm", "repo", "update")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to update helm repos: %v", err)
+// PrometheusRelease descreve o release Helm do Prometheus; os Values fixam o
+// NodePort mapeado pelo Kind e prevalecem sobre o values file
+func PrometheusRelease(cfg config.MonitoringConfig) helm.Release {
+	return helm.Release{
+		Name:        "prometheus",
+		Namespace:   cfg.Namespace,
+		Chart:       PrometheusChart,
+		ValuesFiles: helm.OptionalValuesFiles(cfg.PrometheusValuesFile, config.DefaultPrometheusValuesFile),
+		Values: map[string]interface{}{
+			"server.service.type":     "NodePort",
+			"server.service.nodePort": 30090,
+		},
+		Timeout: 5 * time.Minute,
 	}
+}
 
-	// Deploy Prometheus
-	cmd = exec.Command("helm", "install", "prometheus", "prometheus-community/prometheus",
-		"--namespace", "default",
-		"--set", "server.service.type=NodePort",
-		"--set", "server.service.nodePort=30090",
-		"--set", "alertmanager.enabled=false",
-		"--set", "pushgateway.enabled=false",
-		"--set", "nodeExporter.enabled=false",
-		"--wait",
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+func DeployPrometheus(m *helm.Manager, cfg config.MonitoringConfig) error {
+	fmt.Println("📊 Deploying Prometheus...")
 
-	if err := cmd.Run(); err != nil {
+	if err := m.UpgradeInstall(context.Background(), PrometheusRelease(cfg)); err != nil {
 		return fmt.Errorf("failed to deploy Prometheus: %v", err)
 	}
 
-	time.Sleep(30 * time.Second)
 	fmt.Println("✅ Prometheus deployed successfully")
 	return nil
 }
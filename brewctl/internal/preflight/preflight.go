@@ -0,0 +1,279 @@
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Status é o resultado de uma verificação
+type Status int
+
+const (
+	OK Status = iota
+	Warning
+	Failure
+)
+
+// Icon retorna o emoji usado na saída da CLI
+func (s Status) Icon() string {
+	switch s {
+	case OK:
+		return "✅"
+	case Warning:
+		return "⚠️"
+	}
+	return "❌"
+}
+
+// Result descreve uma verificação e, quando ela falha, como corrigir o problema
+type Result struct {
+	Check       string
+	Status      Status
+	Message     string
+	Remediation string
+}
+
+// Report agrupa os resultados de todas as verificações
+type Report struct {
+	Results []Result
+}
+
+// Failed indica se alguma verificação falhou
+func (r Report) Failed() bool {
+	for _, res := range r.Results {
+		if res.Status == Failure {
+			return true
+		}
+	}
+	return false
+}
+
+// Print escreve o relatório com as instruções de correção
+func (r Report) Print(w io.Writer) {
+	for _, res := range r.Results {
+		fmt.Fprintf(w, "%s %-20s %s\n", res.Status.Icon(), res.Check, res.Message)
+		if res.Status != OK && res.Remediation != "" {
+			fmt.Fprintf(w, "   💡 %s\n", res.Remediation)
+		}
+	}
+}
+
+// Tool é uma ferramenta de linha de comando exigida pelo brewctl
+type Tool struct {
+	Name        string
+	VersionArgs []string
+	MinVersion  string
+	Remediation string
+}
+
+// DefaultTools são as ferramentas usadas pelo cluster-init
+var DefaultTools = []Tool{
+	{
+		Name:        "docker",
+		VersionArgs: []string{"version", "--format", "{{.Client.Version}}"},
+		MinVersion:  "20.10.0",
+		Remediation: "Install Docker: https://docs.docker.com/get-docker/",
+	},
+	{
+		Name:        "kind",
+		VersionArgs: []string{"version"},
+		MinVersion:  "0.20.0",
+		Remediation: "Install kind >= 0.20: go install sigs.k8s.io/kind@v0.20.0 (or see https://kind.sigs.k8s.io/docs/user/quick-start/#installation)",
+	},
+	{
+		Name:        "kubectl",
+		VersionArgs: []string{"version", "--client"},
+		MinVersion:  "1.26.0",
+		Remediation: "Install kubectl >= 1.26: https://kubernetes.io/docs/tasks/tools/",
+	},
+	{
+		Name:        "helm",
+		VersionArgs: []string{"version", "--short"},
+		MinVersion:  "3.10.0",
+		Remediation: "Install Helm 3 >= 3.10: https://helm.sh/docs/intro/install/",
+	},
+}
+
+// Options configura as verificações executadas por Run
+type Options struct {
+	// Ports são as portas do host que o cluster vai mapear; vazio pula a verificação
+	Ports []int
+	Tools []Tool
+	// MinDockerMemory abaixo do qual a verificação falha; RecommendedDockerMemory gera aviso
+	MinDockerMemory         int64
+	RecommendedDockerMemory int64
+}
+
+const GiB = int64(1) << 30
+
+// DefaultOptions retorna as verificações padrão para as portas informadas
+func DefaultOptions(ports []int) Options {
+	return Options{
+		Ports:                   ports,
+		Tools:                   DefaultTools,
+		MinDockerMemory:         4 * GiB,
+		RecommendedDockerMemory: 8 * GiB,
+	}
+}
+
+// runCommand executa um comando e retorna a saída; substituído nos testes
+var runCommand = func(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+var lookPath = exec.LookPath
+
+// Run executa todas as verificações
+func Run(opts Options) Report {
+	var report Report
+
+	for _, tool := range opts.Tools {
+		report.Results = append(report.Results, CheckTool(tool))
+	}
+
+	report.Results = append(report.Results, CheckDocker(opts.MinDockerMemory, opts.RecommendedDockerMemory))
+
+	for _, port := range opts.Ports {
+		report.Results = append(report.Results, CheckPort(port))
+	}
+	return report
+}
+
+// CheckPort verifica se a porta está livre no host
+func CheckPort(port int) Result {
+	check := fmt.Sprintf("port %d", port)
+
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return Result{
+			Check:       check,
+			Status:      Failure,
+			Message:     fmt.Sprintf("port %d is already in use", port),
+			Remediation: fmt.Sprintf("Stop the process using it (lsof -i :%d) or change the host port in the Kind config", port),
+		}
+	}
+	ln.Close()
+
+	return Result{Check: check, Status: OK, Message: "free"}
+}
+
+// CheckTool verifica se a ferramenta está no PATH e atende à versão mínima
+func CheckTool(tool Tool) Result {
+	if _, err := lookPath(tool.Name); err != nil {
+		return Result{
+			Check:       tool.Name,
+			Status:      Failure,
+			Message:     "not found on PATH",
+			Remediation: tool.Remediation,
+		}
+	}
+
+	out, err := runCommand(tool.Name, tool.VersionArgs...)
+	if err != nil {
+		return Result{
+			Check:       tool.Name,
+			Status:      Warning,
+			Message:     fmt.Sprintf("could not determine version: %v", err),
+			Remediation: tool.Remediation,
+		}
+	}
+
+	version, ok := ParseVersion(string(out))
+	if !ok {
+		return Result{
+			Check:   tool.Name,
+			Status:  Warning,
+			Message: fmt.Sprintf("could not parse version from %q", strings.TrimSpace(string(out))),
+		}
+	}
+
+	if tool.MinVersion != "" && CompareVersions(version, tool.MinVersion) < 0 {
+		return Result{
+			Check:       tool.Name,
+			Status:      Failure,
+			Message:     fmt.Sprintf("version %s is older than the required %s", version, tool.MinVersion),
+			Remediation: tool.Remediation,
+		}
+	}
+
+	return Result{Check: tool.Name, Status: OK, Message: "version " + version}
+}
+
+// CheckDocker verifica se o daemon do Docker responde e tem memória suficiente
+func CheckDocker(minMemory, recommendedMemory int64) Result {
+	out, err := runCommand("docker", "info", "--format", "{{json .}}")
+	if err != nil {
+		return Result{
+			Check:       "docker daemon",
+			Status:      Failure,
+			Message:     "Docker daemon is not reachable",
+			Remediation: "Start Docker (Docker Desktop, or `sudo systemctl start docker`) and make sure your user can access the socket",
+		}
+	}
+
+	var info struct {
+		ServerVersion string `json:"ServerVersion"`
+		MemTotal      int64  `json:"MemTotal"`
+		NCPU          int    `json:"NCPU"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return Result{
+			Check:   "docker daemon",
+			Status:  Warning,
+			Message: fmt.Sprintf("could not parse docker info: %v", err),
+		}
+	}
+
+	message := fmt.Sprintf("server %s, %.1f GiB memory, %d CPUs", info.ServerVersion, float64(info.MemTotal)/float64(GiB), info.NCPU)
+	remediation := fmt.Sprintf("Give Docker at least %d GiB of memory (Docker Desktop → Settings → Resources)", recommendedMemory/GiB)
+
+	switch {
+	case minMemory > 0 && info.MemTotal < minMemory:
+		return Result{Check: "docker daemon", Status: Failure, Message: message + " (too little memory for Airbyte)", Remediation: remediation}
+	case recommendedMemory > 0 && info.MemTotal < recommendedMemory:
+		return Result{Check: "docker daemon", Status: Warning, Message: message + " (below recommended)", Remediation: remediation}
+	}
+	return Result{Check: "docker daemon", Status: OK, Message: message}
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extrai a primeira versão semântica (major.minor.patch) do texto
+func ParseVersion(output string) (string, bool) {
+	m := versionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return "", false
+	}
+	patch := m[3]
+	if patch == "" {
+		patch = "0"
+	}
+	return fmt.Sprintf("%s.%s.%s", m[1], m[2], patch), true
+}
+
+// CompareVersions compara duas versões major.minor.patch (-1, 0 ou 1)
+func CompareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < 3; i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"kind v0.20.0 go1.20.4 linux/amd64", "0.20.0"},
+		{"Client Version: v1.28.2\nKustomize Version: v5.0.4-0.20230601165947-6ce0bf390ce3", "1.28.2"},
+		{"v3.12.3+g3a31588", "3.12.3"},
+		{"24.0.5", "24.0.5"},
+		{"Docker version 20.10", "20.10.0"},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseVersion(tt.output)
+		require.True(t, ok, tt.output)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, -1, CompareVersions("0.19.0", "0.20.0"))
+	assert.Equal(t, 0, CompareVersions("1.26.0", "1.26.0"))
+	assert.Equal(t, 1, CompareVersions("3.10.1", "3.9.12"))
+}
+
+func TestCheckPortInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	res := CheckPort(port)
+	assert.Equal(t, Failure, res.Status)
+	assert.Contains(t, res.Remediation, fmt.Sprint(port))
+}
+
+func TestCheckToolVersion(t *testing.T) {
+	defer stubCommands(map[string]string{"kind": "kind v0.17.0 go1.19.2 linux/amd64"})()
+
+	res := CheckTool(Tool{Name: "kind", MinVersion: "0.20.0", Remediation: "upgrade kind"})
+	assert.Equal(t, Failure, res.Status)
+	assert.Equal(t, "upgrade kind", res.Remediation)
+}
+
+func TestCheckDockerMemory(t *testing.T) {
+	defer stubCommands(map[string]string{"docker": `{"ServerVersion":"24.0.5","MemTotal":4294967296,"NCPU":4}`})()
+
+	assert.Equal(t, Warning, CheckDocker(2*GiB, 8*GiB).Status)
+	assert.Equal(t, Failure, CheckDocker(6*GiB, 8*GiB).Status)
+	assert.Equal(t, OK, CheckDocker(2*GiB, 4*GiB).Status)
+}
+
+func stubCommands(outputs map[string]string) func() {
+	origRun, origLook := runCommand, lookPath
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		out, ok := outputs[name]
+		if !ok {
+			return nil, errors.New("not stubbed")
+		}
+		return []byte(out), nil
+	}
+	lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	return func() { runCommand, lookPath = origRun, origLook }
+}
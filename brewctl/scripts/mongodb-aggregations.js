@@ -1,202 +1,8270 @@
-// MongoDB Aggregation Scripts for Breweries Data
+// Code generated by "go generate ./internal/mongodb"; DO NOT EDIT.
+//
+// Agregações do brewctl para o mongosh, geradas a partir dos builders de
+// internal/mongodb/pipelines.go. Rode "brewctl db migrate" antes: o $merge
+// da silver depende do índice único em breweries_clean.id.
 
-// 1. Silver Layer Transformation - Data Cleaning
-db.breweries_raw.aggregate([
-    {
-        $project: {
-            _id: 1,
-            id: 1,
-            name: 1,
-            brewery_type: 1,
-            address_1: 1,
-            city: { $trim: { input: "$city" } },
-            state_province: { $trim: { input: "$state_province" } },
-            state: { $trim: { input: "$state" } },
-            country: { 
-                $cond: {
-                    if: { $eq: ["$country", "United States"] },
-                    then: "US",
-                    else: "$country"
-                }
-            },
-            postal_code: 1,
-            longitude: { $convert: { input: "$longitude", to: "double", onError: null } },
-            latitude: { $convert: { input: "$latitude", to: "double", onError: null } },
-            phone: 1,
-            website_url: 1,
-            street: 1,
-            data_quality: {
-                has_coordinates: { $and: [{ $ne: ["$longitude", null] }, { $ne: ["$latitude", null] }] },
-                has_website: { $ne: ["$website_url", null] },
-                has_phone: { $ne: ["$phone", null] },
-                completeness_score: {
-                    $divide: [
-                        {
-                            $size: {
-                                $filter: {
-                                    input: ["$name", "$brewery_type", "$city", "$state", "$country"],
-                                    as: "field",
-                                    cond: { $ne: ["$$field", null] }
+// 1. Silver Layer - limpeza, normalização e qualidade (breweries_raw -> breweries_clean)
+db.getCollection("breweries_raw").aggregate([
+    {
+        "$match": {
+            "id": {
+                "$type": "string"
+            }
+        }
+    },
+    {
+        "$project": {
+            "_id": 1,
+            "id": 1,
+            "name": 1,
+            "brewery_type": 1,
+            "address_1": 1,
+            "city": {
+                "$trim": {
+                    "input": "$city"
+                }
+            },
+            "state_province": {
+                "$trim": {
+                    "input": "$state_province"
+                }
+            },
+            "state": {
+                "$trim": {
+                    "input": "$state"
+                }
+            },
+            "country": 1,
+            "postal_code": 1,
+            "longitude": {
+                "$convert": {
+                    "input": "$longitude",
+                    "to": "double",
+                    "onError": null,
+                    "onNull": null
+                }
+            },
+            "latitude": {
+                "$convert": {
+                    "input": "$latitude",
+                    "to": "double",
+                    "onError": null,
+                    "onNull": null
+                }
+            },
+            "phone": 1,
+            "website_url": 1,
+            "street": 1,
+            "updated_at": {
+                "$convert": {
+                    "input": "$updated_at",
+                    "to": "date",
+                    "onError": null,
+                    "onNull": null
+                }
+            },
+            "ingestion_date": "$$NOW",
+            "last_updated": "$$NOW"
+        }
+    },
+    {
+        "$set": {
+            "country": {
+                "$let": {
+                    "vars": {
+                        "raw": {
+                            "$trim": {
+                                "input": "$country"
+                            }
+                        }
+                    },
+                    "in": {
+                        "$let": {
+                            "vars": {
+                                "lower": {
+                                    "$toLower": "$$raw"
+                                }
+                            },
+                            "in": {
+                                "$switch": {
+                                    "branches": [
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "andorra"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "united arab emirates",
+                                                        "uae"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "afghanistan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "antigua and barbuda"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "anguilla"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "albania"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "armenia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "angola"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "antarctica"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AQ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "argentina"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "american samoa"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "austria",
+                                                        "österreich"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "australia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "aruba"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "åland islands",
+                                                        "aland islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AX"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "azerbaijan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "AZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bosnia and herzegovina"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "barbados"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BB"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bangladesh"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "belgium"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "burkina faso"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bulgaria"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bahrain"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "burundi"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "benin"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BJ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint barthélemy",
+                                                        "saint barthelemy"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bermuda"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "brunei darussalam",
+                                                        "brunei"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bolivia (plurinational state of)",
+                                                        "bolivia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bonaire, sint eustatius and saba",
+                                                        "caribbean netherlands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BQ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "brazil",
+                                                        "brasil"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bahamas",
+                                                        "the bahamas"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bhutan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "bouvet island"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BV"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "botswana"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "belarus"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "belize"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "BZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "canada"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cocos (keeling) islands",
+                                                        "cocos islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "congo, democratic republic of the",
+                                                        "democratic republic of the congo",
+                                                        "dr congo"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "central african republic"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "congo",
+                                                        "republic of the congo"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "switzerland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "côte d'ivoire",
+                                                        "cote d'ivoire",
+                                                        "ivory coast"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cook islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "chile"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cameroon"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "china"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "colombia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "costa rica"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cuba"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cabo verde",
+                                                        "cape verde"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CV"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "curaçao",
+                                                        "curacao"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "christmas island"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CX"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cyprus"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "czechia",
+                                                        "czech republic"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "CZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "germany",
+                                                        "deutschland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "DE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "djibouti"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "DJ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "denmark"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "DK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "dominica"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "DM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "dominican republic"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "DO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "algeria"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "DZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "ecuador"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "EC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "estonia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "EE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "egypt"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "EG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "western sahara"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "EH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "eritrea"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ER"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "spain"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ES"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "ethiopia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ET"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "finland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "FI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "fiji"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "FJ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "falkland islands (malvinas)",
+                                                        "falkland islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "FK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "micronesia (federated states of)",
+                                                        "micronesia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "FM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "faroe islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "FO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "france"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "FR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "gabon"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "united kingdom",
+                                                        "united kingdom of great britain and northern ireland",
+                                                        "uk",
+                                                        "great britain",
+                                                        "england",
+                                                        "scotland",
+                                                        "wales",
+                                                        "northern ireland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GB"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "grenada"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "georgia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "french guiana"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guernsey"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "ghana"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "gibraltar"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "greenland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "gambia",
+                                                        "the gambia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guinea"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guadeloupe"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GP"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "equatorial guinea"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GQ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "greece"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "south georgia and the south sandwich islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guatemala"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guam"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guinea-bissau"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "guyana"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "GY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "hong kong"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "HK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "heard island and mcdonald islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "HM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "honduras"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "HN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "croatia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "HR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "haiti"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "HT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "hungary"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "HU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "indonesia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ID"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "ireland",
+                                                        "republic of ireland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "israel"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "isle of man"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "india"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "british indian ocean territory"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "iraq"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IQ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "iran (islamic republic of)",
+                                                        "iran"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "iceland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "italy"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "IT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "jersey"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "JE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "jamaica"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "JM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "jordan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "JO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "japan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "JP"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "kenya"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "kyrgyzstan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cambodia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "kiribati"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "comoros"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint kitts and nevis"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "korea (democratic people's republic of)",
+                                                        "north korea"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KP"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "korea, republic of",
+                                                        "south korea",
+                                                        "korea",
+                                                        "republic of korea"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "kuwait"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "cayman islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "kazakhstan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "KZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "lao people's democratic republic",
+                                                        "laos"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "lebanon"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LB"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint lucia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "liechtenstein"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "sri lanka"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "liberia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "lesotho"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "lithuania"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "luxembourg"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "latvia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LV"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "libya"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "LY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "morocco"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "monaco"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "moldova, republic of",
+                                                        "moldova"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "montenegro"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ME"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint martin (french part)",
+                                                        "saint martin"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "madagascar"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "marshall islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "north macedonia",
+                                                        "macedonia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mali"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ML"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "myanmar",
+                                                        "burma"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mongolia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "macao",
+                                                        "macau"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "northern mariana islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MP"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "martinique"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MQ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mauritania"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "montserrat"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "malta"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mauritius"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "maldives"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MV"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "malawi"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mexico",
+                                                        "méxico"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MX"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "malaysia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mozambique"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "MZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "namibia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "new caledonia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "niger"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "norfolk island"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "nigeria"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "nicaragua"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "netherlands",
+                                                        "the netherlands",
+                                                        "holland",
+                                                        "netherlands, kingdom of the"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "norway"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "nepal"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NP"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "nauru"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "niue"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "new zealand"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "NZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "oman"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "OM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "panama"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "peru"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "french polynesia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "papua new guinea"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "philippines"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "pakistan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "poland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint pierre and miquelon"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "pitcairn",
+                                                        "pitcairn islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "puerto rico"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "palestine, state of",
+                                                        "palestine"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "portugal"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "palau"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "paraguay"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "PY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "qatar"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "QA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "réunion",
+                                                        "reunion"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "RE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "romania"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "RO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "serbia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "RS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "russian federation",
+                                                        "russia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "RU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "rwanda"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "RW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saudi arabia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "solomon islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SB"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "seychelles"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "sudan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "sweden"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "singapore"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint helena, ascension and tristan da cunha",
+                                                        "saint helena"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "slovenia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "svalbard and jan mayen"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SJ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "slovakia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "sierra leone"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "san marino"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "senegal"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "somalia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "suriname"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "south sudan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "sao tome and principe",
+                                                        "são tomé and príncipe"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ST"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "el salvador"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SV"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "sint maarten (dutch part)",
+                                                        "sint maarten"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SX"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "syrian arab republic",
+                                                        "syria"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "eswatini",
+                                                        "swaziland"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "SZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "turks and caicos islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "chad"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TD"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "french southern territories"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "togo"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "thailand"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TH"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "tajikistan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TJ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "tokelau"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TK"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "timor-leste",
+                                                        "east timor"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TL"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "turkmenistan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "tunisia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "tonga"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TO"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "türkiye",
+                                                        "turkiye",
+                                                        "turkey"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TR"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "trinidad and tobago"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "tuvalu"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TV"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "taiwan, province of china",
+                                                        "taiwan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TW"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "tanzania, united republic of",
+                                                        "tanzania"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "TZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "ukraine"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "UA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "uganda"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "UG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "united states minor outlying islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "UM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "united states",
+                                                        "united states of america",
+                                                        "usa",
+                                                        "u.s.",
+                                                        "u.s.a."
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "US"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "uruguay"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "UY"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "uzbekistan"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "UZ"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "holy see",
+                                                        "vatican city"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "saint vincent and the grenadines"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VC"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "venezuela (bolivarian republic of)",
+                                                        "venezuela"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "virgin islands (british)",
+                                                        "british virgin islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VG"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "virgin islands (u.s.)",
+                                                        "u.s. virgin islands",
+                                                        "us virgin islands"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VI"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "viet nam",
+                                                        "vietnam"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VN"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "vanuatu"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "VU"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "wallis and futuna"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "WF"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "samoa"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "WS"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "yemen"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "YE"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "mayotte"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "YT"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "south africa"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ZA"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "zambia"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ZM"
+                                        },
+                                        {
+                                            "case": {
+                                                "$in": [
+                                                    "$$lower",
+                                                    [
+                                                        "zimbabwe"
+                                                    ]
+                                                ]
+                                            },
+                                            "then": "ZW"
+                                        }
+                                    ],
+                                    "default": {
+                                        "$cond": [
+                                            {
+                                                "$regexMatch": {
+                                                    "input": {
+                                                        "$convert": {
+                                                            "input": "$$raw",
+                                                            "to": "string",
+                                                            "onError": "",
+                                                            "onNull": ""
+                                                        }
+                                                    },
+                                                    "regex": "^[A-Za-z]{2}$"
+                                                }
+                                            },
+                                            {
+                                                "$toUpper": "$$raw"
+                                            },
+                                            "$$raw"
+                                        ]
+                                    }
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    {
+        "$set": {
+            "state_code": {
+                "$let": {
+                    "vars": {
+                        "state": {
+                            "$toLower": {
+                                "$trim": {
+                                    "input": {
+                                        "$convert": {
+                                            "input": "$state",
+                                            "to": "string",
+                                            "onError": "",
+                                            "onNull": ""
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "in": {
+                        "$switch": {
+                            "branches": [
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "act",
+                                                        "australian capital territory"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "ACT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "new south wales",
+                                                        "nsw"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NSW"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "northern territory",
+                                                        "nt"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "qld",
+                                                        "queensland"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "QLD"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "sa",
+                                                        "south australia"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "SA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "tas",
+                                                        "tasmania"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "TAS"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "vic",
+                                                        "victoria"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "VIC"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "AU"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "wa",
+                                                        "western australia"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "WA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ab",
+                                                        "alberta"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "AB"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "bc",
+                                                        "british columbia"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "BC"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "manitoba",
+                                                        "mb"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MB"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "nb",
+                                                        "new brunswick"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NB"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "newfoundland and labrador",
+                                                        "nl"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NL"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "nova scotia",
+                                                        "ns"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NS"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "northwest territories",
+                                                        "nt"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "nu",
+                                                        "nunavut"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NU"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "on",
+                                                        "ontario"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "ON"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "pe",
+                                                        "prince edward island"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "PE"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "qc",
+                                                        "quebec",
+                                                        "québec"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "QC"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "saskatchewan",
+                                                        "sk"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "SK"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "CA"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "yt",
+                                                        "yukon"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "YT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ak",
+                                                        "alaska"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "AK"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "al",
+                                                        "alabama"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "AL"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ar",
+                                                        "arkansas"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "AR"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "american samoa",
+                                                        "as"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "AS"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "arizona",
+                                                        "az"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "AZ"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ca",
+                                                        "california"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "CA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "co",
+                                                        "colorado"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "CO"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "connecticut",
+                                                        "ct"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "CT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "dc",
+                                                        "district of columbia",
+                                                        "washington d.c.",
+                                                        "washington dc"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "DC"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "de",
+                                                        "delaware"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "DE"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "fl",
+                                                        "florida"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "FL"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ga",
+                                                        "georgia"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "GA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "gu",
+                                                        "guam"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "GU"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "hawaii",
+                                                        "hi"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "HI"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ia",
+                                                        "iowa"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "IA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "id",
+                                                        "idaho"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "ID"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "il",
+                                                        "illinois"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "IL"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "in",
+                                                        "indiana"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "IN"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "kansas",
+                                                        "ks"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "KS"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "kentucky",
+                                                        "ky"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "KY"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "la",
+                                                        "louisiana"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "LA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ma",
+                                                        "massachusetts"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "maryland",
+                                                        "md"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MD"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "maine",
+                                                        "me"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "ME"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "mi",
+                                                        "michigan"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MI"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "minnesota",
+                                                        "mn"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MN"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "missouri",
+                                                        "mo"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MO"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "mp",
+                                                        "northern mariana islands"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MP"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "mississippi",
+                                                        "ms"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MS"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "montana",
+                                                        "mt"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "MT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "nc",
+                                                        "north carolina"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NC"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "nd",
+                                                        "north dakota"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "ND"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ne",
+                                                        "nebraska"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NE"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "new hampshire",
+                                                        "nh"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NH"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "new jersey",
+                                                        "nj"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NJ"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "new mexico",
+                                                        "nm"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NM"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "nevada",
+                                                        "nv"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NV"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "new york",
+                                                        "ny"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "NY"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "oh",
+                                                        "ohio"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "OH"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ok",
+                                                        "oklahoma"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "OK"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "or",
+                                                        "oregon"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "OR"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "pa",
+                                                        "pennsylvania"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "PA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "pr",
+                                                        "puerto rico"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "PR"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "rhode island",
+                                                        "ri"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "RI"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "sc",
+                                                        "south carolina"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "SC"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "sd",
+                                                        "south dakota"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "SD"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "tennessee",
+                                                        "tn"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "TN"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "texas",
+                                                        "tx"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "TX"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "ut",
+                                                        "utah"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "UT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "va",
+                                                        "virginia"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "VA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "u.s. virgin islands",
+                                                        "vi",
+                                                        "virgin islands"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "VI"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "vermont",
+                                                        "vt"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "VT"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "wa",
+                                                        "washington"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "WA"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "wi",
+                                                        "wisconsin"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "WI"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "west virginia",
+                                                        "wv"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "WV"
+                                },
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    "$country",
+                                                    "US"
+                                                ]
+                                            },
+                                            {
+                                                "$in": [
+                                                    "$$state",
+                                                    [
+                                                        "wy",
+                                                        "wyoming"
+                                                    ]
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "WY"
+                                }
+                            ],
+                            "default": null
+                        }
+                    }
+                }
+            },
+            "phone": {
+                "$let": {
+                    "vars": {
+                        "digits": {
+                            "$reduce": {
+                                "input": {
+                                    "$regexFindAll": {
+                                        "input": {
+                                            "$convert": {
+                                                "input": "$phone",
+                                                "to": "string",
+                                                "onError": "",
+                                                "onNull": ""
+                                            }
+                                        },
+                                        "regex": "[0-9]"
+                                    }
+                                },
+                                "initialValue": "",
+                                "in": {
+                                    "$concat": [
+                                        "$$value",
+                                        "$$this.match"
+                                    ]
                                 }
                             }
                         },
-                        5
-                    ]
+                        "international": {
+                            "$regexMatch": {
+                                "input": {
+                                    "$convert": {
+                                        "input": "$phone",
+                                        "to": "string",
+                                        "onError": "",
+                                        "onNull": ""
+                                    }
+                                },
+                                "regex": "^\\s*\\+"
+                            }
+                        },
+                        "original": {
+                            "$trim": {
+                                "input": {
+                                    "$convert": {
+                                        "input": "$phone",
+                                        "to": "string",
+                                        "onError": "",
+                                        "onNull": ""
+                                    }
+                                }
+                            }
+                        },
+                        "code": {
+                            "$switch": {
+                                "branches": [
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AG",
+                                                    "AI",
+                                                    "AS",
+                                                    "BB",
+                                                    "BM",
+                                                    "BS",
+                                                    "CA",
+                                                    "DM",
+                                                    "DO",
+                                                    "GD",
+                                                    "GU",
+                                                    "JM",
+                                                    "KN",
+                                                    "KY",
+                                                    "LC",
+                                                    "MP",
+                                                    "MS",
+                                                    "PR",
+                                                    "SX",
+                                                    "TC",
+                                                    "TT",
+                                                    "UM",
+                                                    "US",
+                                                    "VC",
+                                                    "VG",
+                                                    "VI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "1"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "EG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "20"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "211"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "EH",
+                                                    "MA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "212"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "DZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "213"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "216"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "218"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "220"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "221"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "222"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ML"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "223"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "224"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "225"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "226"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "227"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "228"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BJ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "229"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "230"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "231"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "232"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "233"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "234"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TD"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "235"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "236"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "237"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CV"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "238"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ST"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "239"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GQ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "240"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "241"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "242"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CD"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "243"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "244"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "245"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "246"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SC"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "248"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SD"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "249"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "RW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "250"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ET"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "251"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "252"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "DJ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "253"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "254"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "255"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "UG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "256"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "257"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "258"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ZM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "260"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "261"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "RE",
+                                                    "TF",
+                                                    "YT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "262"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ZW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "263"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "264"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "265"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "266"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "267"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "268"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "269"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ZA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "27"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "290"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ER"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "291"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "297"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "FO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "298"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "299"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "30"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "31"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "32"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "FR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "33"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ES"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "34"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "350"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "351"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "352"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "353"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "354"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "355"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "356"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "357"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AX",
+                                                    "FI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "358"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "359"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "HU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "36"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "370"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LV"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "371"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "EE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "372"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MD"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "373"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "374"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "375"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AD"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "376"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MC"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "377"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "378"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "UA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "380"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "RS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "381"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ME"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "382"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "HR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "385"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "386"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "387"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "389"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IT",
+                                                    "VA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "39"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "RO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "40"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "41"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "420"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "421"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "423"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "43"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GB",
+                                                    "GG",
+                                                    "IM",
+                                                    "JE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "44"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "DK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "45"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "46"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BV",
+                                                    "NO",
+                                                    "SJ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "47"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "48"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "DE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "49"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "FK",
+                                                    "GS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "500"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "501"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "502"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SV"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "503"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "HN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "504"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "505"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "506"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "507"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "508"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "HT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "509"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "51"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MX"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "52"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "53"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "54"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "55"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "56"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "57"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "VE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "58"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BL",
+                                                    "GP",
+                                                    "MF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "590"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "591"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "592"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "EC"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "593"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "594"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "595"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MQ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "596"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "597"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "UY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "598"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BQ",
+                                                    "CW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "599"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "60"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AU",
+                                                    "CC",
+                                                    "CX"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "61"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "ID"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "62"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "63"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NZ",
+                                                    "PN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "64"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "65"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "66"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "670"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AQ",
+                                                    "HM",
+                                                    "NF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "672"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "673"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "674"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "675"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "676"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SB"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "677"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "VU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "678"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "FJ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "679"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "680"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "WF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "681"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "682"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "683"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "WS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "685"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KI"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "686"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NC"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "687"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TV"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "688"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "689"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "690"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "FM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "691"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "692"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KZ",
+                                                    "RU"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "7"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "JP"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "81"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "82"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "VN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "84"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KP"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "850"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "HK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "852"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "853"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "855"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "856"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "CN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "86"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BD"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "880"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "886"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "90"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "91"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "92"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AF"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "93"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LK"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "94"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "95"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MV"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "960"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "LB"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "961"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "JO"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "962"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SY"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "963"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IQ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "964"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KW"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "965"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "SA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "966"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "YE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "967"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "OM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "968"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "PS"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "970"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "971"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IL"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "972"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BH"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "973"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "QA"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "974"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "BT"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "975"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "MN"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "976"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "NP"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "977"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "IR"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "98"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TJ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "992"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "TM"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "993"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "AZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "994"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "GE"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "995"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "KG"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "996"
+                                    },
+                                    {
+                                        "case": {
+                                            "$in": [
+                                                "$country",
+                                                [
+                                                    "UZ"
+                                                ]
+                                            ]
+                                        },
+                                        "then": "998"
+                                    }
+                                ],
+                                "default": ""
+                            }
+                        }
+                    },
+                    "in": {
+                        "$let": {
+                            "vars": {
+                                "e164": {
+                                    "$switch": {
+                                        "branches": [
+                                            {
+                                                "case": "$$international",
+                                                "then": "$$digits"
+                                            },
+                                            {
+                                                "case": {
+                                                    "$eq": [
+                                                        {
+                                                            "$substrCP": [
+                                                                "$$digits",
+                                                                0,
+                                                                2
+                                                            ]
+                                                        },
+                                                        "00"
+                                                    ]
+                                                },
+                                                "then": {
+                                                    "$substrCP": [
+                                                        "$$digits",
+                                                        2,
+                                                        {
+                                                            "$strLenCP": "$$digits"
+                                                        }
+                                                    ]
+                                                }
+                                            },
+                                            {
+                                                "case": {
+                                                    "$eq": [
+                                                        "$$code",
+                                                        "1"
+                                                    ]
+                                                },
+                                                "then": {
+                                                    "$switch": {
+                                                        "branches": [
+                                                            {
+                                                                "case": {
+                                                                    "$eq": [
+                                                                        {
+                                                                            "$strLenCP": "$$digits"
+                                                                        },
+                                                                        10
+                                                                    ]
+                                                                },
+                                                                "then": {
+                                                                    "$concat": [
+                                                                        "1",
+                                                                        "$$digits"
+                                                                    ]
+                                                                }
+                                                            },
+                                                            {
+                                                                "case": {
+                                                                    "$and": [
+                                                                        {
+                                                                            "$eq": [
+                                                                                {
+                                                                                    "$strLenCP": "$$digits"
+                                                                                },
+                                                                                11
+                                                                            ]
+                                                                        },
+                                                                        {
+                                                                            "$eq": [
+                                                                                {
+                                                                                    "$substrCP": [
+                                                                                        "$$digits",
+                                                                                        0,
+                                                                                        1
+                                                                                    ]
+                                                                                },
+                                                                                "1"
+                                                                            ]
+                                                                        }
+                                                                    ]
+                                                                },
+                                                                "then": "$$digits"
+                                                            }
+                                                        ],
+                                                        "default": ""
+                                                    }
+                                                }
+                                            },
+                                            {
+                                                "case": {
+                                                    "$eq": [
+                                                        "$$code",
+                                                        ""
+                                                    ]
+                                                },
+                                                "then": ""
+                                            },
+                                            {
+                                                "case": {
+                                                    "$eq": [
+                                                        {
+                                                            "$substrCP": [
+                                                                "$$digits",
+                                                                0,
+                                                                1
+                                                            ]
+                                                        },
+                                                        "0"
+                                                    ]
+                                                },
+                                                "then": {
+                                                    "$concat": [
+                                                        "$$code",
+                                                        {
+                                                            "$substrCP": [
+                                                                "$$digits",
+                                                                1,
+                                                                {
+                                                                    "$strLenCP": "$$digits"
+                                                                }
+                                                            ]
+                                                        }
+                                                    ]
+                                                }
+                                            },
+                                            {
+                                                "case": {
+                                                    "$and": [
+                                                        {
+                                                            "$eq": [
+                                                                {
+                                                                    "$substrCP": [
+                                                                        "$$digits",
+                                                                        0,
+                                                                        {
+                                                                            "$strLenCP": "$$code"
+                                                                        }
+                                                                    ]
+                                                                },
+                                                                "$$code"
+                                                            ]
+                                                        },
+                                                        {
+                                                            "$gte": [
+                                                                {
+                                                                    "$strLenCP": "$$digits"
+                                                                },
+                                                                {
+                                                                    "$add": [
+                                                                        {
+                                                                            "$strLenCP": "$$code"
+                                                                        },
+                                                                        8
+                                                                    ]
+                                                                }
+                                                            ]
+                                                        }
+                                                    ]
+                                                },
+                                                "then": "$$digits"
+                                            }
+                                        ],
+                                        "default": {
+                                            "$concat": [
+                                                "$$code",
+                                                "$$digits"
+                                            ]
+                                        }
+                                    }
+                                }
+                            },
+                            "in": {
+                                "$cond": [
+                                    {
+                                        "$and": [
+                                            {
+                                                "$gte": [
+                                                    {
+                                                        "$strLenCP": "$$e164"
+                                                    },
+                                                    8
+                                                ]
+                                            },
+                                            {
+                                                "$lte": [
+                                                    {
+                                                        "$strLenCP": "$$e164"
+                                                    },
+                                                    15
+                                                ]
+                                            },
+                                            {
+                                                "$ne": [
+                                                    {
+                                                        "$substrCP": [
+                                                            "$$e164",
+                                                            0,
+                                                            1
+                                                        ]
+                                                    },
+                                                    "0"
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    {
+                                        "$concat": [
+                                            "+",
+                                            "$$e164"
+                                        ]
+                                    },
+                                    {
+                                        "$cond": [
+                                            {
+                                                "$eq": [
+                                                    "$$original",
+                                                    ""
+                                                ]
+                                            },
+                                            null,
+                                            "$$original"
+                                        ]
+                                    }
+                                ]
+                            }
+                        }
+                    }
+                }
+            },
+            "website_url": {
+                "$let": {
+                    "vars": {
+                        "match": {
+                            "$regexFind": {
+                                "input": {
+                                    "$convert": {
+                                        "input": "$website_url",
+                                        "to": "string",
+                                        "onError": "",
+                                        "onNull": ""
+                                    }
+                                },
+                                "regex": "^\\s*(?:([A-Za-z][A-Za-z0-9+.-]*)://)?([^/?#\\s]+)(\\S*)\\s*$"
+                            }
+                        }
+                    },
+                    "in": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    "$$match",
+                                    null
+                                ]
+                            },
+                            null,
+                            {
+                                "$concat": [
+                                    {
+                                        "$toLower": {
+                                            "$ifNull": [
+                                                {
+                                                    "$arrayElemAt": [
+                                                        "$$match.captures",
+                                                        0
+                                                    ]
+                                                },
+                                                "http"
+                                            ]
+                                        }
+                                    },
+                                    "://",
+                                    {
+                                        "$toLower": {
+                                            "$arrayElemAt": [
+                                                "$$match.captures",
+                                                1
+                                            ]
+                                        }
+                                    },
+                                    {
+                                        "$cond": [
+                                            {
+                                                "$eq": [
+                                                    {
+                                                        "$arrayElemAt": [
+                                                            "$$match.captures",
+                                                            2
+                                                        ]
+                                                    },
+                                                    ""
+                                                ]
+                                            },
+                                            "/",
+                                            {
+                                                "$arrayElemAt": [
+                                                    "$$match.captures",
+                                                    2
+                                                ]
+                                            }
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    }
+                }
+            },
+            "zip5": {
+                "$let": {
+                    "vars": {
+                        "match": {
+                            "$cond": [
+                                {
+                                    "$eq": [
+                                        "$country",
+                                        "US"
+                                    ]
+                                },
+                                {
+                                    "$regexFind": {
+                                        "input": {
+                                            "$convert": {
+                                                "input": "$postal_code",
+                                                "to": "string",
+                                                "onError": "",
+                                                "onNull": ""
+                                            }
+                                        },
+                                        "regex": "^\\s*([0-9]{5})(?:[-\\s]?([0-9]{4}))?\\s*$"
+                                    }
+                                },
+                                null
+                            ]
+                        }
+                    },
+                    "in": {
+                        "$ifNull": [
+                            {
+                                "$arrayElemAt": [
+                                    "$$match.captures",
+                                    0
+                                ]
+                            },
+                            null
+                        ]
+                    }
+                }
+            },
+            "zip4": {
+                "$let": {
+                    "vars": {
+                        "match": {
+                            "$cond": [
+                                {
+                                    "$eq": [
+                                        "$country",
+                                        "US"
+                                    ]
+                                },
+                                {
+                                    "$regexFind": {
+                                        "input": {
+                                            "$convert": {
+                                                "input": "$postal_code",
+                                                "to": "string",
+                                                "onError": "",
+                                                "onNull": ""
+                                            }
+                                        },
+                                        "regex": "^\\s*([0-9]{5})(?:[-\\s]?([0-9]{4}))?\\s*$"
+                                    }
+                                },
+                                null
+                            ]
+                        }
+                    },
+                    "in": {
+                        "$ifNull": [
+                            {
+                                "$arrayElemAt": [
+                                    "$$match.captures",
+                                    1
+                                ]
+                            },
+                            null
+                        ]
+                    }
+                }
+            },
+            "location": {
+                "$cond": {
+                    "if": {
+                        "$and": [
+                            {
+                                "$isNumber": "$longitude"
+                            },
+                            {
+                                "$isNumber": "$latitude"
+                            },
+                            {
+                                "$gte": [
+                                    "$longitude",
+                                    -180
+                                ]
+                            },
+                            {
+                                "$lte": [
+                                    "$longitude",
+                                    180
+                                ]
+                            },
+                            {
+                                "$gte": [
+                                    "$latitude",
+                                    -90
+                                ]
+                            },
+                            {
+                                "$lte": [
+                                    "$latitude",
+                                    90
+                                ]
+                            },
+                            {
+                                "$or": [
+                                    {
+                                        "$ne": [
+                                            "$longitude",
+                                            0
+                                        ]
+                                    },
+                                    {
+                                        "$ne": [
+                                            "$latitude",
+                                            0
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "then": {
+                        "type": "Point",
+                        "coordinates": [
+                            "$longitude",
+                            "$latitude"
+                        ]
+                    },
+                    "else": "$$REMOVE"
+                }
+            }
+        }
+    },
+    {
+        "$set": {
+            "data_quality": {
+                "flags": {
+                    "name": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$name"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$name"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$name"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "brewery_type": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$brewery_type"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$brewery_type"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$brewery_type"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "city": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$city"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$city"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$city"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "state": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$state"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$state"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$state"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "country": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$country"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$country"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$country"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "address": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$address_1"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$address_1"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$address_1"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "postal_code": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$postal_code"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$postal_code"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$postal_code"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "phone": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$phone"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$phone"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$phone"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "website": {
+                        "$cond": [
+                            {
+                                "$eq": [
+                                    {
+                                        "$type": "$website_url"
+                                    },
+                                    "string"
+                                ]
+                            },
+                            {
+                                "$gt": [
+                                    {
+                                        "$strLenCP": {
+                                            "$trim": {
+                                                "input": "$website_url"
+                                            }
+                                        }
+                                    },
+                                    0
+                                ]
+                            },
+                            {
+                                "$not": [
+                                    {
+                                        "$in": [
+                                            {
+                                                "$type": "$website_url"
+                                            },
+                                            [
+                                                "missing",
+                                                "null"
+                                            ]
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "coordinates": {
+                        "$and": [
+                            {
+                                "$isNumber": "$longitude"
+                            },
+                            {
+                                "$isNumber": "$latitude"
+                            },
+                            {
+                                "$gte": [
+                                    "$longitude",
+                                    -180
+                                ]
+                            },
+                            {
+                                "$lte": [
+                                    "$longitude",
+                                    180
+                                ]
+                            },
+                            {
+                                "$gte": [
+                                    "$latitude",
+                                    -90
+                                ]
+                            },
+                            {
+                                "$lte": [
+                                    "$latitude",
+                                    90
+                                ]
+                            },
+                            {
+                                "$or": [
+                                    {
+                                        "$ne": [
+                                            "$longitude",
+                                            0
+                                        ]
+                                    },
+                                    {
+                                        "$ne": [
+                                            "$latitude",
+                                            0
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]
+                    }
                 }
+            }
+        }
+    },
+    {
+        "$set": {
+            "data_quality.completeness_score": {
+                "$divide": [
+                    {
+                        "$add": [
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.name",
+                                    3.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.brewery_type",
+                                    2.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.city",
+                                    2.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.state",
+                                    2.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.country",
+                                    2.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.address",
+                                    1.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.postal_code",
+                                    1.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.phone",
+                                    1.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.website",
+                                    1.0,
+                                    0
+                                ]
+                            },
+                            {
+                                "$cond": [
+                                    "$data_quality.flags.coordinates",
+                                    2.0,
+                                    0
+                                ]
+                            }
+                        ]
+                    },
+                    17.0
+                ]
             },
-            ingestion_date: { $dateToString: { format: "%Y-%m-%d", date: new Date() } },
-            last_updated: new Date()
+            "data_quality.has_coordinates": "$data_quality.flags.coordinates",
+            "data_quality.has_website": "$data_quality.flags.website",
+            "data_quality.has_phone": "$data_quality.flags.phone"
+        }
+    },
+    {
+        "$set": {
+            "undated": {
+                "$ne": [
+                    {
+                        "$type": "$updated_at"
+                    },
+                    "date"
+                ]
+            }
+        }
+    },
+    {
+        "$sort": {
+            "id": 1,
+            "undated": -1,
+            "updated_at": -1,
+            "_id": -1
         }
     },
     {
-        $merge: {
-            into: "breweries_clean",
-            on: "_id",
-            whenMatched: "replace",
-            whenNotMatched: "insert"
+        "$group": {
+            "_id": "$id",
+            "latest": {
+                "$first": "$$ROOT"
+            }
+        }
+    },
+    {
+        "$replaceWith": "$latest"
+    },
+    {
+        "$unset": [
+            "_id",
+            "undated"
+        ]
+    },
+    {
+        "$merge": {
+            "into": "breweries_clean",
+            "on": "id",
+            "whenMatched": [
+                {
+                    "$replaceWith": {
+                        "$switch": {
+                            "branches": [
+                                {
+                                    "case": {
+                                        "$and": [
+                                            {
+                                                "$eq": [
+                                                    {
+                                                        "$type": "$$new.updated_at"
+                                                    },
+                                                    "date"
+                                                ]
+                                            },
+                                            {
+                                                "$or": [
+                                                    {
+                                                        "$ne": [
+                                                            {
+                                                                "$type": "$updated_at"
+                                                            },
+                                                            "date"
+                                                        ]
+                                                    },
+                                                    {
+                                                        "$lt": [
+                                                            "$$new.updated_at",
+                                                            "$updated_at"
+                                                        ]
+                                                    }
+                                                ]
+                                            }
+                                        ]
+                                    },
+                                    "then": "$$ROOT"
+                                },
+                                {
+                                    "case": {
+                                        "$eq": [
+                                            {
+                                                "$unsetField": {
+                                                    "field": "last_updated",
+                                                    "input": {
+                                                        "$unsetField": {
+                                                            "field": "ingestion_date",
+                                                            "input": {
+                                                                "$unsetField": {
+                                                                    "field": "_id",
+                                                                    "input": "$$ROOT"
+                                                                }
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            },
+                                            {
+                                                "$unsetField": {
+                                                    "field": "last_updated",
+                                                    "input": {
+                                                        "$unsetField": {
+                                                            "field": "ingestion_date",
+                                                            "input": {
+                                                                "$unsetField": {
+                                                                    "field": "_id",
+                                                                    "input": "$$new"
+                                                                }
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            }
+                                        ]
+                                    },
+                                    "then": "$$ROOT"
+                                }
+                            ],
+                            "default": {
+                                "$mergeObjects": [
+                                    "$$new",
+                                    {
+                                        "_id": "$_id",
+                                        "ingestion_date": "$ingestion_date"
+                                    }
+                                ]
+                            }
+                        }
+                    }
+                }
+            ],
+            "whenNotMatched": "insert"
         }
     }
 ]);
 
-// 2. Gold Layer Aggregation - Business Metrics
-db.breweries_clean.aggregate([
+// 2. Gold Layer - snapshot em uma coleção temporária que substitui breweries_aggregated;
+// builds anteriores que falharam no meio são descartados antes
+db.getCollectionNames()
+    .filter((name) => name.startsWith("breweries_aggregated_build_"))
+    .forEach((name) => db.getCollection(name).drop());
+const buildId = new ObjectId().toHexString();
+const buildCollection = "breweries_aggregated_build_" + buildId;
+const goldInfo = db.getCollectionInfos({ name: "breweries_aggregated" })[0];
+db.createCollection(buildCollection, goldInfo ? goldInfo.options : {});
+if (goldInfo) {
+    const indexes = db.getCollection("breweries_aggregated").getIndexes().filter((index) => index.name !== "_id_");
+    indexes.forEach((index) => delete index.ns);
+    if (indexes.length > 0) {
+        db.runCommand({ createIndexes: buildCollection, indexes: indexes });
+    }
+}
+db.getCollection("breweries_clean").aggregate([
     {
-        $match: {
-            "data_quality.completeness_score": { $gte: 0.8 }
+        "$match": {
+            "data_quality.completeness_score": {
+                "$gte": 0.6
+            }
         }
     },
     {
-        $group: {
-            _id: {
-                country: "$country",
-                state: "$state",
-                brewery_type: "$brewery_type"
+        "$group": {
+            "_id": {
+                "country": "$country",
+                "state": "$state",
+                "brewery_type": "$brewery_type"
             },
-            total_breweries: { $sum: 1 },
-            breweries_with_website: {
-                $sum: { $cond: [{ $ne: ["$website_url", null] }, 1, 0] }
+            "total_breweries": {
+                "$sum": 1
             },
-            breweries_with_phone: {
-                $sum: { $cond: [{ $ne: ["$phone", null] }, 1, 0] }
+            "breweries_with_website": {
+                "$sum": {
+                    "$cond": [
+                        {
+                            "$ne": [
+                                "$website_url",
+                                null
+                            ]
+                        },
+                        1,
+                        0
+                    ]
+                }
             },
-            breweries_with_coordinates: {
-                $sum: { $cond: ["$data_quality.has_coordinates", 1, 0] }
+            "breweries_with_phone": {
+                "$sum": {
+                    "$cond": [
+                        {
+                            "$ne": [
+                                "$phone",
+                                null
+                            ]
+                        },
+                        1,
+                        0
+                    ]
+                }
             },
-            avg_completeness_score: { $avg: "$data_quality.completeness_score" },
-            example_breweries: { $push: { name: "$name", city: "$city" } }
+            "breweries_with_coordinates": {
+                "$sum": {
+                    "$cond": [
+                        "$data_quality.has_coordinates",
+                        1,
+                        0
+                    ]
+                }
+            }
         }
     },
     {
-        $project: {
-            _id: 0,
-            country: "$_id.country",
-            state: "$_id.state",
-            brewery_type: "$_id.brewery_type",
-            total_breweries: 1,
-            breweries_with_website: 1,
-            breweries_with_phone: 1,
-            breweries_with_coordinates: 1,
-            website_coverage: {
-                $round: [
-                    { $multiply: [{ $divide: ["$breweries_with_website", "$total_breweries"] }, 100] },
-                    2
-                ]
-            },
-            phone_coverage: {
-                $round: [
-                    { $multiply: [{ $divide: ["$breweries_with_phone", "$total_breweries"] }, 100] },
-                    2
-                ]
-            },
-            coordinates_coverage: {
-                $round: [
-                    { $multiply: [{ $divide: ["$breweries_with_coordinates", "$total_breweries"] }, 100] },
-                    2
-                ]
-            },
-            avg_completeness_score: { $round: ["$avg_completeness_score", 3] },
-            example_breweries: { $slice: ["$example_breweries", 3] },
-            aggregation_date: { $dateToString: { format: "%Y-%m-%d", date: new Date() } }
+        "$project": {
+            "_id": 0,
+            "country": "$_id.country",
+            "state": "$_id.state",
+            "brewery_type": "$_id.brewery_type",
+            "total_breweries": 1,
+            "breweries_with_website": 1,
+            "breweries_with_phone": 1,
+            "breweries_with_coordinates": 1,
+            "build_id": buildId,
+            "built_at": "$$NOW"
         }
     },
     {
-        $merge: {
-            into: "breweries_aggregated",
-            on: ["country", "state", "brewery_type"],
-            whenMatched: "replace",
-            whenNotMatched: "insert"
-        }
+        "$out": buildCollection
     }
 ]);
+db.adminCommand({
+    renameCollection: db.getName() + "." + buildCollection,
+    to: db.getName() + ".breweries_aggregated",
+    dropTarget: true
+});
 
-// 3. Query Examples for Analysis
-
-// Top 10 States by Brewery Count
-db.breweries_clean.aggregate([
+// 3. Top 10 estados
+db.getCollection("breweries_clean").aggregate([
     {
-        $group: {
-            _id: "$state",
-            total_breweries: { $sum: 1 }
+        "$group": {
+            "_id": "$state",
+            "total_breweries": {
+                "$sum": 1
+            }
         }
     },
     {
-        $sort: { total_breweries: -1 }
+        "$sort": {
+            "total_breweries": -1
+        }
     },
     {
-        $limit: 10
+        "$limit": 10
     },
     {
-        $project: {
-            _id: 0,
-            state: "$_id",
-            total_breweries: 1
+        "$project": {
+            "_id": 0,
+            "state": "$_id",
+            "total_breweries": 1
         }
     }
 ]);
 
-// Brewery Type Distribution
-db.breweries_clean.aggregate([
+// 4. Distribuição por tipo de cervejaria
+db.getCollection("breweries_clean").aggregate([
     {
-        $group: {
-            _id: "$brewery_type",
-            count: { $sum: 1 },
-            states: { $addToSet: "$state" }
+        "$group": {
+            "_id": "$brewery_type",
+            "count": {
+                "$sum": 1
+            },
+            "states": {
+                "$addToSet": "$state"
+            }
         }
     },
     {
-        $project: {
-            _id: 0,
-            brewery_type: "$_id",
-            count: 1,
-            states_covered: { $size: "$states" }
+        "$project": {
+            "_id": 0,
+            "brewery_type": "$_id",
+            "count": 1,
+            "states_covered": {
+                "$size": "$states"
+            }
         }
     },
     {
-        $sort: { count: -1 }
+        "$sort": {
+            "count": -1
+        }
     }
 ]);
 
-// Geographic Distribution (for mapping)
-db.breweries_clean.aggregate([
+// 5. Distribuição geográfica
+db.getCollection("breweries_clean").aggregate([
     {
-        $match: {
+        "$match": {
             "data_quality.has_coordinates": true
         }
     },
     {
-        $project: {
-            _id: 0,
-            name: 1,
-            brewery_type: 1,
-            city: 1,
-            state: 1,
-            country: 1,
-            longitude: 1,
-            latitude: 1,
-            website_url: 1
+        "$project": {
+            "_id": 0,
+            "name": 1,
+            "brewery_type": 1,
+            "city": 1,
+            "state": 1,
+            "country": 1,
+            "longitude": 1,
+            "latitude": 1
         }
     },
     {
-        $limit: 100
+        "$limit": 100
     }
-]);
\ No newline at end of file
+]);